@@ -0,0 +1,75 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/stackrox/sensor-metrics-analyzer/internal/analyzer"
+	"github.com/stackrox/sensor-metrics-analyzer/internal/reporter"
+)
+
+func coverageCommand() {
+	fs := flag.NewFlagSet("coverage", flag.ExitOnError)
+	rulesDir := fs.String("rules", ".", "Directory containing TOML rules (default: current directory)")
+	loadLevelDir := fs.String("load-level-dir", "./load-level", "Directory containing load detection rules")
+	output := fs.String("output", "", "Output file (default: stdout)")
+	format := fs.String("format", "console", "Output format: console, markdown")
+	acsVersionOverride := fs.String("acs-version", "", "Override detected ACS version; rules not applicable to it are skipped")
+	strict := fs.Bool("strict", false, "Fail on malformed metrics lines instead of skipping them")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: metrics-analyzer coverage [flags] <metrics-file>\n\n")
+		fmt.Fprintf(os.Stderr, "Checks rules against a real scrape: lists rules whose metrics are absent and\n")
+		fmt.Fprintf(os.Stderr, "rox_sensor_* metrics no rule references, ranked by type.\n\n")
+		fmt.Fprintf(os.Stderr, "Arguments:\n")
+		fmt.Fprintf(os.Stderr, "  metrics-file       Path to Prometheus metrics file\n\n")
+		fmt.Fprintf(os.Stderr, "Flags:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\n⚠️  Note: Flags must come BEFORE the metrics file!\n")
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  metrics-analyzer coverage --rules ./automated-rules metrics.txt\n")
+		fmt.Fprintf(os.Stderr, "  metrics-analyzer coverage --format markdown --output coverage.md --rules ./automated-rules metrics.txt\n")
+	}
+
+	fs.Parse(os.Args[2:])
+
+	if fs.NArg() != 1 {
+		fmt.Fprintf(os.Stderr, "Error: expected one metrics file, flags must come before it\n")
+		fmt.Fprintf(os.Stderr, "Usage: metrics-analyzer coverage [flags] <metrics-file>\n")
+		os.Exit(1)
+	}
+
+	report, err := analyzer.CoverageFile(fs.Arg(0), analyzer.Options{
+		RulesDir:           *rulesDir,
+		LoadLevelDir:       *loadLevelDir,
+		ACSVersionOverride: *acsVersionOverride,
+		Logger:             os.Stderr,
+		StrictParse:        *strict,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Coverage check failed: %v\n", err)
+		os.Exit(exitCodeAnalysisFailed)
+	}
+
+	var outputContent string
+	switch *format {
+	case "console":
+		outputContent = reporter.GenerateCoverageConsole(report)
+	case "markdown":
+		outputContent = reporter.GenerateCoverageMarkdown(report)
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown format: %s\n", *format)
+		os.Exit(exitCodeAnalysisFailed)
+	}
+
+	if *output == "" {
+		fmt.Print(outputContent)
+		return
+	}
+	if err := os.WriteFile(*output, []byte(outputContent), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to write output: %v\n", err)
+		os.Exit(exitCodeAnalysisFailed)
+	}
+	fmt.Fprintf(os.Stderr, "Report written to %s\n", *output)
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/stackrox/sensor-metrics-analyzer/internal/analyzer"
+	"github.com/stackrox/sensor-metrics-analyzer/internal/reporter"
+	"github.com/stackrox/sensor-metrics-analyzer/internal/rules"
+	"github.com/stackrox/sensor-metrics-analyzer/internal/tui"
+)
+
+func diffCommand() {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	rulesDir := fs.String("rules", ".", "Directory containing TOML rules, used for metrics files (default: current directory)")
+	loadLevelDir := fs.String("load-level-dir", "./load-level", "Directory containing load detection rules")
+	output := fs.String("output", "", "Output file (default: stdout)")
+	format := fs.String("format", "console", "Output format: console, markdown, tui (interactive)")
+	loadLevelOverride := fs.String("load-level", "", "Override detected load level (a level declared by the load detection rules, e.g. low/medium/high) for metrics files")
+	loadStrategy := fs.String("load-strategy", "max", "How to combine multiple load detection rules: max, weighted_vote")
+	acsVersionOverride := fs.String("acs-version", "", "Override detected ACS version for metrics files")
+	strict := fs.Bool("strict", false, "Fail on malformed metrics lines instead of skipping them")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: metrics-analyzer diff [flags] <before> <after>\n\n")
+		fmt.Fprintf(os.Stderr, "Compares two analyses rule by rule: status transitions and value deltas.\n\n")
+		fmt.Fprintf(os.Stderr, "Arguments:\n")
+		fmt.Fprintf(os.Stderr, "  before, after      Metrics files, or JSON reports written by 'analyze --format json'\n\n")
+		fmt.Fprintf(os.Stderr, "Flags:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\n⚠️  Note: Flags must come BEFORE the files!\n")
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  metrics-analyzer diff --rules ./automated-rules metrics-before.txt metrics-after.txt\n")
+		fmt.Fprintf(os.Stderr, "  metrics-analyzer diff --format markdown --output diff.md before.json after.json\n")
+		fmt.Fprintf(os.Stderr, "  metrics-analyzer diff --format tui --rules ./automated-rules before.json metrics-after.txt\n")
+	}
+
+	fs.Parse(os.Args[2:])
+
+	if fs.NArg() != 2 {
+		fmt.Fprintf(os.Stderr, "Error: expected two files to compare, flags must come before them\n")
+		fmt.Fprintf(os.Stderr, "Usage: metrics-analyzer diff [flags] <before> <after>\n")
+		os.Exit(1)
+	}
+
+	opts := analyzer.Options{
+		RulesDir:           *rulesDir,
+		LoadLevelDir:       *loadLevelDir,
+		LoadLevelOverride:  *loadLevelOverride,
+		LoadStrategy:       *loadStrategy,
+		ACSVersionOverride: *acsVersionOverride,
+		Logger:             os.Stderr,
+		StrictParse:        *strict,
+	}
+	var reports [2]rules.AnalysisReport
+	for i, path := range fs.Args() {
+		report, err := loadReport(path, opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitCodeAnalysisFailed)
+		}
+		reports[i] = report
+	}
+	diff := analyzer.DiffReports(reports[0], reports[1])
+
+	var outputContent string
+	switch *format {
+	case "tui":
+		if *output != "" {
+			fmt.Fprintf(os.Stderr, "Warning: --output is ignored in TUI mode\n")
+		}
+		if err := tui.RunDiff(diff); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: TUI error: %v\n", err)
+			os.Exit(exitCodeAnalysisFailed)
+		}
+		return
+	case "console":
+		outputContent = reporter.GenerateDiffConsole(diff)
+	case "markdown":
+		outputContent = reporter.GenerateDiffMarkdown(diff)
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown format: %s\n", *format)
+		os.Exit(exitCodeAnalysisFailed)
+	}
+
+	if *output == "" {
+		fmt.Print(outputContent)
+		return
+	}
+	if err := os.WriteFile(*output, []byte(outputContent), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to write output: %v\n", err)
+		os.Exit(exitCodeAnalysisFailed)
+	}
+	fmt.Fprintf(os.Stderr, "Report written to %s\n", *output)
+}
+
+// loadReport reads a JSON report, or analyzes a metrics file when the file is not
+// one. Errors name the file.
+func loadReport(path string, opts analyzer.Options) (rules.AnalysisReport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return rules.AnalysisReport{}, err
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		// A metrics file never starts with {, so a report that fails to parse is not
+		// retried as metrics
+		report, err := reporter.ParseJSON(data)
+		if err != nil {
+			return rules.AnalysisReport{}, fmt.Errorf("%s: %w", path, err)
+		}
+		return report, nil
+	}
+	report, err := analyzer.AnalyzeFile(path, opts)
+	if err != nil {
+		return rules.AnalysisReport{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return report, nil
+}
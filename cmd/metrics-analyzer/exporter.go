@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/stackrox/sensor-metrics-analyzer/internal/analyzer"
+	"github.com/stackrox/sensor-metrics-analyzer/internal/exporter"
+	"github.com/stackrox/sensor-metrics-analyzer/internal/rules"
+)
+
+// defaultExporterListenAddr is the address the exporter serves /metrics on
+const defaultExporterListenAddr = ":9190"
+
+func exporterCommand() {
+	fs := flag.NewFlagSet("exporter", flag.ExitOnError)
+	rulesDir := fs.String("rules", ".", "Directory containing TOML rules (default: current directory)")
+	loadLevelDir := fs.String("load-level-dir", "./load-level", "Directory containing load detection rules")
+	clusterName := fs.String("cluster", "", "Cluster name label (extracted from the file name or URL host if not provided)")
+	loadLevelOverride := fs.String("load-level", "", "Override detected load level (a level declared by the load detection rules, e.g. low/medium/high)")
+	loadStrategy := fs.String("load-strategy", "max", "How to combine multiple load detection rules: max, weighted_vote")
+	acsVersionOverride := fs.String("acs-version", "", "Override detected ACS version")
+	strict := fs.Bool("strict", false, "Fail on malformed metrics lines instead of skipping them")
+	interval := fs.Duration("interval", 30*time.Second, "Time between refreshes of the analysis")
+	listen := fs.String("listen", defaultExporterListenAddr, "Address to serve /metrics on")
+	scrapeOpts := addScrapeFlags(fs)
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: metrics-analyzer exporter [flags] --url <metrics-url>\n")
+		fmt.Fprintf(os.Stderr, "       metrics-analyzer exporter [flags] <metrics-file>\n\n")
+		fmt.Fprintf(os.Stderr, "Periodically analyzes a Sensor metrics endpoint (or re-reads a file) and\n")
+		fmt.Fprintf(os.Stderr, "exposes the results as Prometheus metrics on /metrics, e.g.\n")
+		fmt.Fprintf(os.Stderr, "sensor_metrics_analyzer_rule_status{cluster,rule} (0 GREEN, 1 YELLOW, 2 RED,\n")
+		fmt.Fprintf(os.Stderr, "-1 UNKNOWN, which alerts on rule_status > 0 ignore).\n\n")
+		fmt.Fprintf(os.Stderr, "Flags:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  metrics-analyzer exporter --rules ./automated-rules --url http://localhost:9090/metrics\n")
+		fmt.Fprintf(os.Stderr, "  metrics-analyzer exporter --listen :9190 --interval 1m --url https://sensor.stackrox:9090/metrics \\\n")
+		fmt.Fprintf(os.Stderr, "    --bearer-token-file /var/run/secrets/kubernetes.io/serviceaccount/token --ca-file ca.pem\n")
+	}
+
+	fs.Parse(os.Args[2:])
+
+	if (*scrapeOpts.url == "") == (fs.NArg() != 1) {
+		fmt.Fprintf(os.Stderr, "Error: expected either --url or a metrics file, flags must come before the file\n")
+		fmt.Fprintf(os.Stderr, "Usage: metrics-analyzer exporter [flags] --url <metrics-url>\n")
+		os.Exit(exitCodeAnalysisFailed)
+	}
+	if *interval <= 0 {
+		fmt.Fprintf(os.Stderr, "Error: --interval must be positive\n")
+		os.Exit(exitCodeAnalysisFailed)
+	}
+
+	opts := analyzer.Options{
+		RulesDir:           *rulesDir,
+		LoadLevelDir:       *loadLevelDir,
+		ClusterName:        *clusterName,
+		LoadLevelOverride:  *loadLevelOverride,
+		LoadStrategy:       *loadStrategy,
+		ACSVersionOverride: *acsVersionOverride,
+		StrictParse:        *strict,
+	}
+	source, refresh, err := newRefresher(fs.Arg(0), scrapeOpts.options(), opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(exitCodeAnalysisFailed)
+	}
+
+	cluster := *clusterName
+	if cluster == "" && *scrapeOpts.url != "" {
+		cluster = clusterNameFromURL(*scrapeOpts.url)
+	}
+	exp := exporter.New(cluster)
+
+	if err := serveExporter(exp, *listen, source, *interval, refresh); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(exitCodeAnalysisFailed)
+	}
+}
+
+// serveExporter refreshes the exporter in the background and serves it until
+// the process is interrupted
+func serveExporter(exp *exporter.Exporter, listen, source string, interval time.Duration, refresh func() (rules.AnalysisReport, error)) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", exp)
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, "ok")
+	})
+	server := &http.Server{Addr: listen, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go exp.Run(ctx, interval, refresh, os.Stderr)
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Fprintf(os.Stderr, "Analyzing %s every %s, serving metrics on %s/metrics\n", source, interval, listen)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/stackrox/sensor-metrics-analyzer/internal/rules"
+)
+
+// Exit codes of the analyze command, so it can gate CI pipelines.
+// Invalid flags exit with 2, like the flag package does for unknown flags.
+const (
+	exitCodeClean             = 0
+	exitCodeAnalysisFailed    = 1
+	exitCodeInvalidFlags      = 2
+	exitCodeThresholdBreached = 3
+)
+
+// Gate outcomes reported in the machine-readable summary line
+const (
+	gateResultClean    = "clean"
+	gateResultBreached = "breached"
+	gateResultFailed   = "failed"
+)
+
+// parseFailOn validates the --fail-on flag value. An empty value disables gating.
+func parseFailOn(value string) (rules.Status, error) {
+	switch strings.ToLower(value) {
+	case "":
+		return "", nil
+	case "red":
+		return rules.StatusRed, nil
+	case "yellow":
+		return rules.StatusYellow, nil
+	default:
+		return "", fmt.Errorf("invalid --fail-on value: %s (must be red or yellow)", value)
+	}
+}
+
+// gateExitCode returns the exit code for a produced report.
+// With failOn RED only RED results breach; with YELLOW both YELLOW and RED do.
+// With failOnUnknown, rules that could not be evaluated breach as well.
+func gateExitCode(report rules.AnalysisReport, failOn rules.Status, failOnUnknown bool) int {
+	if failOnUnknown && report.Summary.UnknownCount > 0 {
+		return exitCodeThresholdBreached
+	}
+	switch failOn {
+	case rules.StatusRed:
+		if report.Summary.RedCount > 0 {
+			return exitCodeThresholdBreached
+		}
+	case rules.StatusYellow:
+		if report.Summary.RedCount > 0 || report.Summary.YellowCount > 0 {
+			return exitCodeThresholdBreached
+		}
+	}
+	return exitCodeClean
+}
+
+// gateSummaryLine formats the one-line logfmt summary printed to stderr when gating
+func gateSummaryLine(report rules.AnalysisReport, failOn rules.Status, exitCode int) string {
+	result := gateResultClean
+	if exitCode == exitCodeThresholdBreached {
+		result = gateResultBreached
+	}
+	return fmt.Sprintf("analysis_result=%s fail_on=%s exit_code=%d total=%d red=%d yellow=%d green=%d unknown=%d",
+		result,
+		strings.ToLower(string(failOn)),
+		exitCode,
+		report.Summary.TotalAnalyzed,
+		report.Summary.RedCount,
+		report.Summary.YellowCount,
+		report.Summary.GreenCount,
+		report.Summary.UnknownCount,
+	)
+}
+
+// gateFailureLine formats the summary line when no report could be produced
+func gateFailureLine(failOn rules.Status) string {
+	return fmt.Sprintf("analysis_result=%s fail_on=%s exit_code=%d",
+		gateResultFailed, strings.ToLower(string(failOn)), exitCodeAnalysisFailed)
+}
@@ -4,6 +4,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/stackrox/sensor-metrics-analyzer/internal/analyzer"
@@ -23,8 +24,22 @@ func main() {
 	switch command {
 	case "analyze":
 		analyzeCommand()
+	case "analyze-bundle":
+		analyzeBundleCommand()
+	case "watch":
+		watchCommand()
+	case "exporter":
+		exporterCommand()
+	case "diff":
+		diffCommand()
+	case "coverage":
+		coverageCommand()
 	case "validate":
 		validateCommand()
+	case "test-rules":
+		testRulesCommand()
+	case "new-rule":
+		newRuleCommand()
 	case "list-rules":
 		listRulesCommand()
 	default:
@@ -39,33 +54,59 @@ func analyzeCommand() {
 	rulesDir := fs.String("rules", ".", "Directory containing TOML rules (default: current directory)")
 	loadLevelDir := fs.String("load-level-dir", "./load-level", "Directory containing load detection rules")
 	output := fs.String("output", "", "Output file (default: stdout)")
-	format := fs.String("format", "console", "Output format: console, markdown, tui (interactive)")
+	format := fs.String("format", "console", "Output format: console, markdown, json, tui (interactive)")
 	clusterName := fs.String("cluster", "", "Cluster name (extracted from filename if not provided)")
-	loadLevelOverride := fs.String("load-level", "", "Override detected load level (low/medium/high)")
+	loadLevelOverride := fs.String("load-level", "", "Override detected load level (a level declared by the load detection rules, e.g. low/medium/high)")
+	loadStrategy := fs.String("load-strategy", "max", "How to combine multiple load detection rules: max, weighted_vote")
 	acsVersionOverride := fs.String("acs-version", "", "Override detected ACS version")
 	templatePath := fs.String("template", "./templates/markdown.tmpl", "Path to markdown template")
+	baselineFile := fs.String("baseline", "", "Earlier metrics file of the same Sensor; evaluates rates between the two scrapes")
+	interval := fs.Duration("interval", 0, "Time between --baseline and the metrics file (default: derived from file modification times), or between --url scrapes (default: 30s)")
+	strict := fs.Bool("strict", false, "Fail on malformed metrics lines instead of skipping them")
+	failOn := fs.String("fail-on", "", "Exit with code 3 if any result is at least this severe: red, yellow (default: never)")
+	failOnUnknown := fs.Bool("fail-on-unknown", false, "Exit with code 3 if any rule could not be evaluated, e.g. because its metric is missing")
+	scrapeOpts := addScrapeFlags(fs)
+	samples := fs.Int("samples", 1, "With --url, number of scrapes taken --interval apart; more than one evaluates rates")
 
 	fs.Usage = func() {
-		fmt.Fprintf(os.Stderr, "Usage: metrics-analyzer analyze [flags] <metrics-file>\n\n")
+		fmt.Fprintf(os.Stderr, "Usage: metrics-analyzer analyze [flags] <metrics-file>\n")
+		fmt.Fprintf(os.Stderr, "       metrics-analyzer analyze [flags] --url <metrics-url>\n\n")
 		fmt.Fprintf(os.Stderr, "Analyzes Prometheus metrics using declarative TOML rules.\n\n")
 		fmt.Fprintf(os.Stderr, "Arguments:\n")
 		fmt.Fprintf(os.Stderr, "  metrics-file       Path to Prometheus metrics file\n\n")
 		fmt.Fprintf(os.Stderr, "Flags:\n")
 		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExit codes:\n")
+		fmt.Fprintf(os.Stderr, "  0  analysis completed (and no result breached --fail-on or --fail-on-unknown)\n")
+		fmt.Fprintf(os.Stderr, "  1  analysis failed\n")
+		fmt.Fprintf(os.Stderr, "  2  invalid flags\n")
+		fmt.Fprintf(os.Stderr, "  3  a result breached --fail-on or --fail-on-unknown\n")
 		fmt.Fprintf(os.Stderr, "\n⚠️  Note: Flags must come BEFORE the metrics file!\n")
 		fmt.Fprintf(os.Stderr, "\nExamples:\n")
 		fmt.Fprintf(os.Stderr, "  metrics-analyzer analyze metrics.txt\n")
 		fmt.Fprintf(os.Stderr, "  metrics-analyzer analyze --rules ./automated-rules metrics.txt\n")
 		fmt.Fprintf(os.Stderr, "  metrics-analyzer analyze --format markdown --output report.md metrics.txt\n")
 		fmt.Fprintf(os.Stderr, "  metrics-analyzer analyze --format tui --rules ./automated-rules metrics.txt\n")
+		fmt.Fprintf(os.Stderr, "  metrics-analyzer analyze --format json --output report.json metrics.txt\n")
+		fmt.Fprintf(os.Stderr, "  metrics-analyzer analyze --fail-on red --rules ./automated-rules metrics.txt\n")
+		fmt.Fprintf(os.Stderr, "  metrics-analyzer analyze --fail-on red --fail-on-unknown metrics.txt\n")
+		fmt.Fprintf(os.Stderr, "  metrics-analyzer analyze --baseline metrics-before.txt --interval 5m metrics-after.txt\n")
+		fmt.Fprintf(os.Stderr, "  metrics-analyzer analyze --strict metrics.txt\n")
+		fmt.Fprintf(os.Stderr, "  metrics-analyzer analyze --url https://sensor:9090/metrics --bearer-token-file token --ca-file ca.pem\n")
+		fmt.Fprintf(os.Stderr, "  metrics-analyzer analyze --url http://localhost:9090/metrics --samples 5 --interval 1m\n")
 	}
 
 	fs.Parse(os.Args[2:])
 
-	if fs.NArg() < 1 {
+	if err := validateURLFlags(*scrapeOpts.url, *baselineFile, *samples, fs.NArg()); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(exitCodeInvalidFlags)
+	}
+
+	if *scrapeOpts.url == "" && fs.NArg() < 1 {
 		fmt.Fprintf(os.Stderr, "Error: missing metrics file\n")
 		fmt.Fprintf(os.Stderr, "Usage: metrics-analyzer analyze [flags] <metrics-file>\n")
-		os.Exit(1)
+		os.Exit(exitCodeInvalidFlags)
 	}
 
 	metricsFile := fs.Arg(0)
@@ -80,79 +121,209 @@ func analyzeCommand() {
 			fmt.Fprintf(os.Stderr, "  metrics-analyzer analyze [flags] <metrics-file>\n\n")
 			fmt.Fprintf(os.Stderr, "Example:\n")
 			fmt.Fprintf(os.Stderr, "  metrics-analyzer analyze --format tui --rules ./automated-rules %s\n", metricsFile)
-			os.Exit(1)
+			os.Exit(exitCodeInvalidFlags)
 		}
 	}
 
-	report, err := analyzer.AnalyzeFile(metricsFile, analyzer.Options{
+	failOnStatus, err := parseFailOn(*failOn)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(exitCodeInvalidFlags)
+	}
+
+	opts := analyzer.Options{
 		RulesDir:           *rulesDir,
 		LoadLevelDir:       *loadLevelDir,
 		ClusterName:        *clusterName,
 		LoadLevelOverride:  *loadLevelOverride,
+		LoadStrategy:       *loadStrategy,
 		ACSVersionOverride: *acsVersionOverride,
 		Logger:             os.Stderr,
-	})
+		BaselineFile:       *baselineFile,
+		Interval:           *interval,
+		StrictParse:        *strict,
+	}
+	var report rules.AnalysisReport
+	if *scrapeOpts.url != "" {
+		report, err = analyzeURL(scrapeOpts.options(), *samples, *interval, opts)
+	} else {
+		report, err = analyzer.AnalyzeFile(metricsFile, opts)
+	}
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to analyze metrics: %v\n", err)
-		os.Exit(1)
+		exitAnalysisFailed(failOnStatus, *failOnUnknown)
+	}
+
+	if err := writeReport(report, *format, *output, *templatePath); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		exitAnalysisFailed(failOnStatus, *failOnUnknown)
+	}
+
+	if failOnStatus != "" || *failOnUnknown {
+		exitCode := gateExitCode(report, failOnStatus, *failOnUnknown)
+		fmt.Fprintln(os.Stderr, gateSummaryLine(report, failOnStatus, exitCode))
+		os.Exit(exitCode)
 	}
+}
 
-	// Generate report
+// exitAnalysisFailed exits with the analysis-failed code, printing the
+// machine-readable summary line when gating is enabled.
+func exitAnalysisFailed(failOn rules.Status, failOnUnknown bool) {
+	if failOn != "" || failOnUnknown {
+		fmt.Fprintln(os.Stderr, gateFailureLine(failOn))
+	}
+	os.Exit(exitCodeAnalysisFailed)
+}
+
+// writeReport renders the report in the requested format to stdout or the output file
+func writeReport(report rules.AnalysisReport, format, output, templatePath string) error {
 	var outputContent string
-	switch *format {
+	switch format {
 	case "tui":
 		// Interactive TUI mode
-		if *output != "" {
+		if output != "" {
 			fmt.Fprintf(os.Stderr, "Warning: --output is ignored in TUI mode\n")
 		}
 		if err := tui.Run(report); err != nil {
-			fmt.Fprintf(os.Stderr, "TUI error: %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("TUI error: %w", err)
 		}
-		return
+		return nil
 	case "console":
 		// If output file specified, still use console format
-		if *output != "" {
+		if output != "" {
 			outputContent = reporter.GenerateConsole(report)
 		} else {
 			reporter.PrintConsole(report)
-			return
+			return nil
 		}
 	case "markdown":
-		markdown, mdErr := reporter.GenerateMarkdown(report, *templatePath)
-		if mdErr != nil {
-			fmt.Fprintf(os.Stderr, "Markdown generation failed: %v\n", mdErr)
-			os.Exit(1)
+		markdown, err := reporter.GenerateMarkdown(report, templatePath)
+		if err != nil {
+			return fmt.Errorf("markdown generation failed: %w", err)
 		}
 		outputContent = markdown
+	case "json":
+		jsonReport, err := reporter.GenerateJSON(report)
+		if err != nil {
+			return fmt.Errorf("JSON generation failed: %w", err)
+		}
+		outputContent = jsonReport
 	default:
-		fmt.Fprintf(os.Stderr, "Unknown format: %s\n", *format)
-		os.Exit(1)
+		return fmt.Errorf("unknown format: %s", format)
 	}
 
 	// Write output
+	if output == "" {
+		fmt.Print(outputContent)
+		return nil
+	}
+	if err := os.WriteFile(output, []byte(outputContent), 0644); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Report written to %s\n", output)
+	return nil
+}
+
+func analyzeBundleCommand() {
+	fs := flag.NewFlagSet("analyze-bundle", flag.ExitOnError)
+	rulesDir := fs.String("rules", ".", "Directory containing TOML rules (default: current directory)")
+	loadLevelDir := fs.String("load-level-dir", "./load-level", "Directory containing load detection rules")
+	output := fs.String("output", "", "Output file (default: stdout)")
+	format := fs.String("format", "console", "Output format: console, markdown, json")
+	loadLevelOverride := fs.String("load-level", "", "Override detected load level (a level declared by the load detection rules, e.g. low/medium/high) for all clusters")
+	loadStrategy := fs.String("load-strategy", "max", "How to combine multiple load detection rules: max, weighted_vote")
+	acsVersionOverride := fs.String("acs-version", "", "Override detected ACS version for all clusters")
+	templatePath := fs.String("template", "./templates/markdown.tmpl", "Path to markdown template")
+	strict := fs.Bool("strict", false, "Fail a cluster's analysis on malformed metrics lines instead of skipping them")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: metrics-analyzer analyze-bundle [flags] <bundle>\n\n")
+		fmt.Fprintf(os.Stderr, "Analyzes every *-sensor-metrics.txt file of a diagnostic bundle and\n")
+		fmt.Fprintf(os.Stderr, "produces one combined report with a per-cluster summary.\n\n")
+		fmt.Fprintf(os.Stderr, "Arguments:\n")
+		fmt.Fprintf(os.Stderr, "  bundle             Directory, .zip or .tar.gz diagnostic bundle\n\n")
+		fmt.Fprintf(os.Stderr, "Flags:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\n⚠️  Note: Flags must come BEFORE the bundle!\n")
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  metrics-analyzer analyze-bundle --rules ./automated-rules stackrox_debug_dump.zip\n")
+		fmt.Fprintf(os.Stderr, "  metrics-analyzer analyze-bundle --format markdown --output report.md ./diagnostic-bundle\n")
+		fmt.Fprintf(os.Stderr, "  metrics-analyzer analyze-bundle --format json --output report.json bundle.tar.gz\n")
+	}
+
+	fs.Parse(os.Args[2:])
+
+	if fs.NArg() != 1 {
+		fmt.Fprintf(os.Stderr, "Error: expected exactly one bundle, flags must come before it\n")
+		fmt.Fprintf(os.Stderr, "Usage: metrics-analyzer analyze-bundle [flags] <bundle>\n")
+		os.Exit(1)
+	}
+
+	bundle, err := analyzer.AnalyzeBundle(fs.Arg(0), analyzer.Options{
+		RulesDir:           *rulesDir,
+		LoadLevelDir:       *loadLevelDir,
+		LoadLevelOverride:  *loadLevelOverride,
+		LoadStrategy:       *loadStrategy,
+		ACSVersionOverride: *acsVersionOverride,
+		Logger:             os.Stderr,
+		StrictParse:        *strict,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to analyze bundle: %v\n", err)
+		os.Exit(exitCodeAnalysisFailed)
+	}
+
+	var outputContent string
+	switch *format {
+	case "console":
+		outputContent = reporter.GenerateBundleConsole(bundle)
+	case "markdown":
+		outputContent, err = reporter.GenerateBundleMarkdown(bundle, *templatePath)
+	case "json":
+		outputContent, err = reporter.GenerateBundleJSON(bundle)
+	default:
+		err = fmt.Errorf("unknown format: %s", *format)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(exitCodeAnalysisFailed)
+	}
+
 	if *output == "" {
 		fmt.Print(outputContent)
 	} else {
-		err := os.WriteFile(*output, []byte(outputContent), 0644)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to write output: %v\n", err)
-			os.Exit(1)
+		if err := os.WriteFile(*output, []byte(outputContent), 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to write output: %v\n", err)
+			os.Exit(exitCodeAnalysisFailed)
 		}
 		fmt.Fprintf(os.Stderr, "Report written to %s\n", *output)
 	}
+
+	// A bundle in which no metrics file could be analyzed is a failed analysis
+	for _, cluster := range bundle.Clusters {
+		if cluster.Error == "" {
+			return
+		}
+	}
+	os.Exit(exitCodeAnalysisFailed)
 }
 
 func validateCommand() {
 	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	loadLevelDir := fs.String("load-level-dir", "", "Directory containing load detection rules (default: <rules-directory>/load-level)")
+	strictLint := fs.Bool("strict", false, "Fail on lint warnings as well as errors")
 	fs.Usage = func() {
-		fmt.Fprintf(os.Stderr, "Usage: metrics-analyzer validate [rules-directory]\n\n")
-		fmt.Fprintf(os.Stderr, "Validates TOML rule files in the specified directory.\n\n")
+		fmt.Fprintf(os.Stderr, "Usage: metrics-analyzer validate [flags] [rules-directory]\n\n")
+		fmt.Fprintf(os.Stderr, "Validates TOML rule files in the specified directory and reports every error and lint warning.\n\n")
 		fmt.Fprintf(os.Stderr, "Arguments:\n")
 		fmt.Fprintf(os.Stderr, "  rules-directory    Directory containing TOML rule files (default: ./automated-rules)\n\n")
-		fmt.Fprintf(os.Stderr, "Examples:\n")
+		fmt.Fprintf(os.Stderr, "Flags:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\n⚠️  Note: Flags must come BEFORE the rules directory!\n")
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
 		fmt.Fprintf(os.Stderr, "  metrics-analyzer validate\n")
 		fmt.Fprintf(os.Stderr, "  metrics-analyzer validate ./automated-rules\n")
+		fmt.Fprintf(os.Stderr, "  metrics-analyzer validate --strict ./automated-rules\n")
 		fmt.Fprintf(os.Stderr, "  metrics-analyzer validate --help\n")
 	}
 
@@ -162,16 +333,33 @@ func validateCommand() {
 	if fs.NArg() > 0 {
 		rulesDir = fs.Arg(0)
 	}
+	if *loadLevelDir == "" {
+		*loadLevelDir = filepath.Join(rulesDir, "load-level")
+	}
 
 	fmt.Printf("Validating rules in %s...\n", rulesDir)
 
-	rulesList, err := rules.LoadRules(rulesDir)
+	result, err := rules.LintRules(rulesDir, *loadLevelDir)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Validation failed: %v\n", err)
 		os.Exit(1)
 	}
+	for _, d := range result.Diagnostics {
+		fmt.Println(d)
+	}
+
+	errorCount, warningCount := result.Errors(), result.Warnings()
+	if errorCount > 0 || (*strictLint && warningCount > 0) {
+		fmt.Fprintf(os.Stderr, "❌ Validation failed: %d errors, %d warnings\n", errorCount, warningCount)
+		os.Exit(1)
+	}
 
-	fmt.Printf("✅ All %d rules are valid!\n", len(rulesList))
+	fmt.Printf("✅ All %d rules and %d load detection rules are valid!", len(result.Rules), len(result.LoadRules))
+	if warningCount > 0 {
+		fmt.Printf(" (%d warnings)", warningCount)
+	}
+	fmt.Println()
+	fmt.Printf("Load levels: %s\n", rules.FormatLoadLevels(result.LoadLevels))
 }
 
 func listRulesCommand() {
@@ -214,18 +402,36 @@ func printUsage() {
 	fmt.Println("Usage: metrics-analyzer <command> [options]")
 	fmt.Println()
 	fmt.Println("Commands:")
-	fmt.Println("  analyze      Analyze a Prometheus metrics file")
-	fmt.Println("  validate     Validate TOML rule files")
-	fmt.Println("  list-rules   List all available rules")
+	fmt.Println("  analyze         Analyze a Prometheus metrics file")
+	fmt.Println("  analyze-bundle  Analyze all Sensor metrics files of a diagnostic bundle")
+	fmt.Println("  watch           Re-analyze a file or URL on an interval in the TUI")
+	fmt.Println("  exporter        Serve analysis results of a URL as Prometheus metrics")
+	fmt.Println("  diff            Compare two analyses (metrics files or JSON reports)")
+	fmt.Println("  coverage        Check which rules and Sensor metrics a metrics file covers")
+	fmt.Println("  validate        Validate TOML rule files")
+	fmt.Println("  test-rules      Run the tests declared for TOML rules")
+	fmt.Println("  new-rule        Write a pre-filled rule for a metric of a sample scrape")
+	fmt.Println("  list-rules      List all available rules")
 	fmt.Println()
 	fmt.Println("Examples:")
 	fmt.Println("  metrics-analyzer analyze metrics.txt")
 	fmt.Println("  metrics-analyzer analyze --rules ./automated-rules metrics.txt")
 	fmt.Println("  metrics-analyzer analyze --format markdown --output report.md metrics.txt")
 	fmt.Println("  metrics-analyzer analyze --format tui --rules ./automated-rules metrics.txt")
+	fmt.Println("  metrics-analyzer analyze --format json --output report.json metrics.txt")
+	fmt.Println("  metrics-analyzer analyze --fail-on red metrics.txt")
 	fmt.Println("  metrics-analyzer analyze --load-level high --acs-version 4.8 metrics.txt")
+	fmt.Println("  metrics-analyzer analyze --baseline metrics-before.txt --interval 5m metrics-after.txt")
+	fmt.Println("  metrics-analyzer analyze --url http://localhost:9090/metrics --samples 5 --interval 1m")
+	fmt.Println("  metrics-analyzer analyze-bundle --rules ./automated-rules stackrox_debug_dump.zip")
+	fmt.Println("  metrics-analyzer watch --url http://localhost:9090/metrics --interval 15s")
+	fmt.Println("  metrics-analyzer exporter --rules ./automated-rules --url http://localhost:9090/metrics")
+	fmt.Println("  metrics-analyzer diff --rules ./automated-rules before.json metrics-after.txt")
+	fmt.Println("  metrics-analyzer coverage --rules ./automated-rules metrics.txt")
 	fmt.Println("  metrics-analyzer validate")
 	fmt.Println("  metrics-analyzer validate ./automated-rules")
+	fmt.Println("  metrics-analyzer test-rules ./automated-rules")
+	fmt.Println("  metrics-analyzer new-rule rox_sensor_resolver_channel_size metrics.txt")
 	fmt.Println("  metrics-analyzer list-rules")
 	fmt.Println()
 	fmt.Println("Note: Flags must come BEFORE positional arguments!")
@@ -1,11 +1,18 @@
 package main
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/stackrox/sensor-metrics-analyzer/internal/analyzer"
+	"github.com/stackrox/sensor-metrics-analyzer/internal/rules"
+	"github.com/stackrox/sensor-metrics-analyzer/internal/scrape"
 )
 
 func TestE2EAnalyzeCommand(t *testing.T) {
@@ -113,6 +120,14 @@ func TestE2EValidateCommand(t *testing.T) {
 			},
 			wantError: false, // Returns 0 rules, not an error
 		},
+		"should fail on lint warnings in strict mode": {
+			args: []string{
+				"validate",
+				"--strict",
+				"../../testdata/fixtures",
+			},
+			wantError: true, // The fixtures have no red remediation
+		},
 	}
 
 	for name, tt := range tests {
@@ -145,6 +160,183 @@ func TestE2EValidateCommand(t *testing.T) {
 	}
 }
 
+func TestE2ETestRulesCommand(t *testing.T) {
+	failingDir := t.TempDir()
+	rule := "rule_type = \"gauge_threshold\"\nmetric_name = \"queue_size\"\n[thresholds]\nlow = 10\nhigh = 100\nhigher_is_worse = true\n" +
+		"[[tests]]\nname = \"wrong status\"\nmetrics = \"queue_size 500\"\nstatus = \"GREEN\"\n"
+	if err := os.WriteFile(filepath.Join(failingDir, "queue_size.toml"), []byte(rule), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		args       []string
+		wantError  bool
+		wantOutput string
+	}{
+		"should pass the tests of the bundled rules": {
+			args:       []string{"test-rules", "../../automated-rules"},
+			wantOutput: "rule tests passed",
+		},
+		"should fail on a failing test": {
+			args:       []string{"test-rules", failingDir},
+			wantError:  true,
+			wantOutput: "--- FAIL: queue_size/wrong status",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			binPath := filepath.Join("..", "..", "bin", "metrics-analyzer")
+			absPath, err := filepath.Abs(binPath)
+			if err != nil {
+				t.Fatalf("Failed to get absolute path: %v", err)
+			}
+
+			if _, err := os.Stat(absPath); os.IsNotExist(err) {
+				t.Skipf("Binary %s does not exist, skipping e2e test", absPath)
+				return
+			}
+
+			cmd := exec.Command(absPath, tt.args...)
+			output, err := cmd.CombinedOutput()
+			if (err != nil) != tt.wantError {
+				t.Fatalf("Command error = %v, wantError %v\nOutput: %s", err, tt.wantError, string(output))
+			}
+			if !strings.Contains(string(output), tt.wantOutput) {
+				t.Errorf("Output does not contain %q:\n%s", tt.wantOutput, string(output))
+			}
+		})
+	}
+}
+
+func TestE2ECoverageCommand(t *testing.T) {
+	tests := map[string]struct {
+		args       []string
+		wantError  bool
+		wantOutput string
+	}{
+		"should report rules with absent metrics": {
+			args:       []string{"coverage", "--rules", "../../automated-rules", "../../testdata/fixtures/sample_metrics.txt"},
+			wantOutput: "Rules With Absent Metrics",
+		},
+		"should write markdown": {
+			args:       []string{"coverage", "--format", "markdown", "--rules", "../../automated-rules", "../../testdata/fixtures/sample_metrics.txt"},
+			wantOutput: "## Sensor Metrics Without Rules",
+		},
+		"should require a metrics file": {
+			args:      []string{"coverage", "--rules", "../../automated-rules"},
+			wantError: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			binPath := filepath.Join("..", "..", "bin", "metrics-analyzer")
+			absPath, err := filepath.Abs(binPath)
+			if err != nil {
+				t.Fatalf("Failed to get absolute path: %v", err)
+			}
+
+			if _, err := os.Stat(absPath); os.IsNotExist(err) {
+				t.Skipf("Binary %s does not exist, skipping e2e test", absPath)
+				return
+			}
+
+			cmd := exec.Command(absPath, tt.args...)
+			output, err := cmd.CombinedOutput()
+			if (err != nil) != tt.wantError {
+				t.Fatalf("Command error = %v, wantError %v\nOutput: %s", err, tt.wantError, string(output))
+			}
+			if !strings.Contains(string(output), tt.wantOutput) {
+				t.Errorf("Output does not contain %q:\n%s", tt.wantOutput, string(output))
+			}
+		})
+	}
+}
+
+func TestE2EDiffCommand(t *testing.T) {
+	unsupported := filepath.Join(t.TempDir(), "report.json")
+	if err := os.WriteFile(unsupported, []byte(`{"schema_version": "99", "results": []}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		args       []string
+		wantError  bool
+		wantOutput string
+	}{
+		"should compare two metrics files": {
+			args: []string{"diff", "--rules", "../../testdata/fixtures",
+				"../../testdata/fixtures/sample_metrics.txt", "../../testdata/fixtures/sample_metrics.txt"},
+			wantOutput: "Analysis Diff Report",
+		},
+		"should reject a report with an unsupported schema version": {
+			args:       []string{"diff", "--rules", "../../testdata/fixtures", unsupported, "../../testdata/fixtures/sample_metrics.txt"},
+			wantError:  true,
+			wantOutput: `unsupported JSON report schema version "99"`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			binPath := filepath.Join("..", "..", "bin", "metrics-analyzer")
+			absPath, err := filepath.Abs(binPath)
+			if err != nil {
+				t.Fatalf("Failed to get absolute path: %v", err)
+			}
+
+			if _, err := os.Stat(absPath); os.IsNotExist(err) {
+				t.Skipf("Binary %s does not exist, skipping e2e test", absPath)
+				return
+			}
+
+			cmd := exec.Command(absPath, tt.args...)
+			output, err := cmd.CombinedOutput()
+			if (err != nil) != tt.wantError {
+				t.Fatalf("Command error = %v, wantError %v\nOutput: %s", err, tt.wantError, string(output))
+			}
+			if !strings.Contains(string(output), tt.wantOutput) {
+				t.Errorf("Output does not contain %q:\n%s", tt.wantOutput, string(output))
+			}
+		})
+	}
+}
+
+func TestE2ENewRuleCommand(t *testing.T) {
+	binPath := filepath.Join("..", "..", "bin", "metrics-analyzer")
+	absPath, err := filepath.Abs(binPath)
+	if err != nil {
+		t.Fatalf("Failed to get absolute path: %v", err)
+	}
+	if _, err := os.Stat(absPath); os.IsNotExist(err) {
+		t.Skipf("Binary %s does not exist, skipping e2e test", absPath)
+	}
+
+	rulesDir := t.TempDir()
+	newRule := func() ([]byte, error) {
+		cmd := exec.Command(absPath, "new-rule", "--rules", rulesDir,
+			"rox_sensor_num_pods_in_store", "../../testdata/fixtures/sample_metrics.txt")
+		return cmd.CombinedOutput()
+	}
+
+	output, err := newRule()
+	if err != nil {
+		t.Fatalf("Command failed: %v\nOutput: %s", err, string(output))
+	}
+	if _, err := os.Stat(filepath.Join(rulesDir, "rox_sensor_num_pods_in_store.toml")); err != nil {
+		t.Fatalf("Rule file not written: %v\nOutput: %s", err, string(output))
+	}
+
+	output, err = exec.Command(absPath, "validate", "--strict", rulesDir).CombinedOutput()
+	if err != nil {
+		t.Errorf("Generated rule does not validate: %v\nOutput: %s", err, string(output))
+	}
+
+	if output, err := newRule(); err == nil {
+		t.Errorf("Expected an error when the rule file exists. Output: %s", string(output))
+	}
+}
+
 func TestE2EListRulesCommand(t *testing.T) {
 	tests := map[string]struct {
 		args      []string
@@ -244,3 +436,200 @@ func TestE2EFullWorkflow(t *testing.T) {
 		}
 	}
 }
+
+func TestE2EAnalyzeExitCodes(t *testing.T) {
+	tests := map[string]struct {
+		args     []string
+		wantCode int
+	}{
+		"should exit 3 when a result breaches --fail-on": {
+			args:     []string{"analyze", "--fail-on", "yellow", "--rules", "../../testdata/fixtures", "../../testdata/fixtures/sample_metrics.txt"},
+			wantCode: exitCodeThresholdBreached,
+		},
+		"should exit 2 on an invalid --fail-on value": {
+			args:     []string{"analyze", "--fail-on", "yelow", "../../testdata/fixtures/sample_metrics.txt"},
+			wantCode: exitCodeInvalidFlags,
+		},
+		"should exit 2 without a metrics file": {
+			args:     []string{"analyze", "--fail-on", "red"},
+			wantCode: exitCodeInvalidFlags,
+		},
+		"should exit 2 on flags after the metrics file": {
+			args:     []string{"analyze", "../../testdata/fixtures/sample_metrics.txt", "--fail-on", "red"},
+			wantCode: exitCodeInvalidFlags,
+		},
+		"should exit 2 on --samples without --url": {
+			args:     []string{"analyze", "--samples", "3", "../../testdata/fixtures/sample_metrics.txt"},
+			wantCode: exitCodeInvalidFlags,
+		},
+		"should exit 2 on --url with a metrics file": {
+			args:     []string{"analyze", "--url", "http://localhost:9090/metrics", "../../testdata/fixtures/sample_metrics.txt"},
+			wantCode: exitCodeInvalidFlags,
+		},
+		"should exit 1 when the analysis fails": {
+			args:     []string{"analyze", "nonexistent.txt"},
+			wantCode: exitCodeAnalysisFailed,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			absPath, err := filepath.Abs(filepath.Join("..", "..", "bin", "metrics-analyzer"))
+			if err != nil {
+				t.Fatalf("Failed to get absolute path: %v", err)
+			}
+			if _, err := os.Stat(absPath); os.IsNotExist(err) {
+				t.Skipf("Binary %s does not exist, skipping e2e test. Run 'make build' first.", absPath)
+			}
+
+			output, err := exec.Command(absPath, tt.args...).CombinedOutput()
+			code := 0
+			if exitErr, ok := err.(*exec.ExitError); ok {
+				code = exitErr.ExitCode()
+			} else if err != nil {
+				t.Fatalf("Command failed: %v", err)
+			}
+			if code != tt.wantCode {
+				t.Errorf("exit code = %d, want %d\nOutput: %s", code, tt.wantCode, output)
+			}
+		})
+	}
+}
+
+func TestGateExitCode(t *testing.T) {
+	tests := map[string]struct {
+		failOn        string
+		failOnUnknown bool
+		summary       rules.Summary
+		wantCode      int
+		wantLine      string
+	}{
+		"should be clean when no red results and failing on red": {
+			failOn:   "red",
+			summary:  rules.Summary{TotalAnalyzed: 3, YellowCount: 1, GreenCount: 2},
+			wantCode: exitCodeClean,
+			wantLine: "analysis_result=clean fail_on=red exit_code=0 total=3 red=0 yellow=1 green=2 unknown=0",
+		},
+		"should breach when red results and failing on red": {
+			failOn:   "red",
+			summary:  rules.Summary{TotalAnalyzed: 2, RedCount: 1, GreenCount: 1},
+			wantCode: exitCodeThresholdBreached,
+			wantLine: "analysis_result=breached fail_on=red exit_code=3 total=2 red=1 yellow=0 green=1 unknown=0",
+		},
+		"should breach on yellow results when failing on yellow": {
+			failOn:   "YELLOW",
+			summary:  rules.Summary{TotalAnalyzed: 1, YellowCount: 1},
+			wantCode: exitCodeThresholdBreached,
+			wantLine: "analysis_result=breached fail_on=yellow exit_code=3 total=1 red=0 yellow=1 green=0 unknown=0",
+		},
+		"should breach on red results when failing on yellow": {
+			failOn:   "yellow",
+			summary:  rules.Summary{TotalAnalyzed: 1, RedCount: 1},
+			wantCode: exitCodeThresholdBreached,
+			wantLine: "analysis_result=breached fail_on=yellow exit_code=3 total=1 red=1 yellow=0 green=0 unknown=0",
+		},
+		"should ignore unknown results by default": {
+			failOn:   "red",
+			summary:  rules.Summary{TotalAnalyzed: 2, GreenCount: 1, UnknownCount: 1},
+			wantCode: exitCodeClean,
+			wantLine: "analysis_result=clean fail_on=red exit_code=0 total=2 red=0 yellow=0 green=1 unknown=1",
+		},
+		"should breach on unknown results when failing on unknown": {
+			failOnUnknown: true,
+			summary:       rules.Summary{TotalAnalyzed: 2, GreenCount: 1, UnknownCount: 1},
+			wantCode:      exitCodeThresholdBreached,
+			wantLine:      "analysis_result=breached fail_on= exit_code=3 total=2 red=0 yellow=0 green=1 unknown=1",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			failOn, err := parseFailOn(tt.failOn)
+			if err != nil {
+				t.Fatalf("parseFailOn() error = %v", err)
+			}
+			report := rules.AnalysisReport{Summary: tt.summary}
+
+			code := gateExitCode(report, failOn, tt.failOnUnknown)
+			if code != tt.wantCode {
+				t.Errorf("gateExitCode() = %d, want %d", code, tt.wantCode)
+			}
+			if line := gateSummaryLine(report, failOn, code); line != tt.wantLine {
+				t.Errorf("gateSummaryLine() = %q, want %q", line, tt.wantLine)
+			}
+		})
+	}
+
+	if _, err := parseFailOn("green"); err == nil {
+		t.Error("parseFailOn() expected error for unsupported value")
+	}
+}
+
+func TestAnalyzeURL(t *testing.T) {
+	metricsPath := filepath.Join("..", "..", "testdata", "fixtures", "sample_metrics.txt")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, metricsPath)
+	}))
+	defer server.Close()
+
+	tests := map[string]struct {
+		samples      int
+		interval     time.Duration
+		wantInterval time.Duration
+	}{
+		"should analyze a single scrape": {
+			samples: 1,
+		},
+		"should evaluate rates over repeated scrapes": {
+			samples:      3,
+			interval:     10 * time.Millisecond,
+			wantInterval: 20 * time.Millisecond,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			report, err := analyzeURL(scrape.Options{URL: server.URL + "/metrics"}, tt.samples, tt.interval, analyzer.Options{
+				RulesDir: filepath.Join("..", "..", "testdata", "fixtures"),
+			})
+			if err != nil {
+				t.Fatalf("analyzeURL() error = %v", err)
+			}
+			if report.ClusterName != "127.0.0.1" {
+				t.Errorf("analyzeURL() cluster name = %q, want the URL host", report.ClusterName)
+			}
+			if report.RateInterval != tt.wantInterval {
+				t.Errorf("analyzeURL() rate interval = %v, want %v", report.RateInterval, tt.wantInterval)
+			}
+			if len(report.Results) == 0 {
+				t.Error("analyzeURL() returned no results")
+			}
+		})
+	}
+}
+
+func TestValidateURLFlags(t *testing.T) {
+	tests := map[string]struct {
+		url        string
+		baseline   string
+		samples    int
+		positional int
+		wantError  bool
+	}{
+		"should accept a metrics file":           {samples: 1, positional: 1},
+		"should accept a URL with samples":       {url: "http://sensor/metrics", samples: 3},
+		"should reject samples without a URL":    {samples: 3, positional: 1, wantError: true},
+		"should reject a URL and a metrics file": {url: "http://sensor/metrics", samples: 1, positional: 1, wantError: true},
+		"should reject a URL and a baseline":     {url: "http://sensor/metrics", baseline: "before.txt", samples: 1, wantError: true},
+		"should reject fewer than one sample":    {url: "http://sensor/metrics", samples: 0, wantError: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := validateURLFlags(tt.url, tt.baseline, tt.samples, tt.positional)
+			if (err != nil) != tt.wantError {
+				t.Errorf("validateURLFlags() error = %v, wantError %v", err, tt.wantError)
+			}
+		})
+	}
+}
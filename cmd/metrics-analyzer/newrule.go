@@ -0,0 +1,94 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/stackrox/sensor-metrics-analyzer/internal/parser"
+	"github.com/stackrox/sensor-metrics-analyzer/internal/scaffold"
+)
+
+func newRuleCommand() {
+	fs := flag.NewFlagSet("new-rule", flag.ExitOnError)
+	rulesDir := fs.String("rules", "./automated-rules", "Directory to write the rule file to")
+	output := fs.String("output", "", "Rule file to write, or - for stdout (default: <rules>/<metric-name>.toml)")
+	force := fs.Bool("force", false, "Overwrite an existing rule file")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: metrics-analyzer new-rule [flags] <metric-name> <metrics-file>\n\n")
+		fmt.Fprintf(os.Stderr, "Writes a pre-filled TOML rule for a metric of a sample scrape. The rule type is\n")
+		fmt.Fprintf(os.Stderr, "picked from the metric's # TYPE and labels (histogram, summary, queue_operations for an\n")
+		fmt.Fprintf(os.Stderr, "Operation label with Add/Remove values, gauge_threshold otherwise) and thresholds\n")
+		fmt.Fprintf(os.Stderr, "are proposed from the observed values. Counters are graded per second over the Sensor\n")
+		fmt.Fprintf(os.Stderr, "uptime. Review the generated file before committing it.\n\n")
+		fmt.Fprintf(os.Stderr, "Arguments:\n")
+		fmt.Fprintf(os.Stderr, "  metric-name        Metric to write a rule for\n")
+		fmt.Fprintf(os.Stderr, "  metrics-file       Path to a Prometheus metrics file containing the metric\n\n")
+		fmt.Fprintf(os.Stderr, "Flags:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\n⚠️  Note: Flags must come BEFORE the arguments!\n")
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  metrics-analyzer new-rule rox_sensor_resolver_channel_size metrics.txt\n")
+		fmt.Fprintf(os.Stderr, "  metrics-analyzer new-rule --output - rox_sensor_k8s_event_processing_duration metrics.txt\n")
+	}
+
+	fs.Parse(os.Args[2:])
+
+	if fs.NArg() != 2 {
+		fmt.Fprintf(os.Stderr, "Error: expected a metric name and a metrics file, flags must come before them\n")
+		fmt.Fprintf(os.Stderr, "Usage: metrics-analyzer new-rule [flags] <metric-name> <metrics-file>\n")
+		os.Exit(1)
+	}
+	metricName, metricsFile := fs.Arg(0), fs.Arg(1)
+
+	metrics, err := parser.ParseFile(metricsFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to parse metrics: %v\n", err)
+		os.Exit(exitCodeAnalysisFailed)
+	}
+	// Counters are averaged over the Sensor uptime when the scrape was saved
+	var scrapeTime time.Time
+	if info, err := os.Stat(metricsFile); err == nil {
+		scrapeTime = info.ModTime()
+	}
+	s, err := scaffold.Generate(metrics, metricName, scrapeTime)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(exitCodeAnalysisFailed)
+	}
+	content, err := s.TOML()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(exitCodeAnalysisFailed)
+	}
+
+	if *output == "-" {
+		fmt.Print(string(content))
+		return
+	}
+	path := *output
+	if path == "" {
+		path = filepath.Join(*rulesDir, s.Rule.MetricName+".toml")
+	}
+	if _, err := os.Stat(path); err == nil && !*force {
+		fmt.Fprintf(os.Stderr, "Error: %s already exists, use --force to overwrite it\n", path)
+		os.Exit(exitCodeAnalysisFailed)
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to write rule: %v\n", err)
+		os.Exit(exitCodeAnalysisFailed)
+	}
+
+	fmt.Printf("✅ Wrote %s rule to %s\n", s.Rule.RuleType, path)
+	switch {
+	case s.Derived:
+		fmt.Printf("Thresholds proposed from the observed value %g; review them and the TODOs before committing.\n", s.Observed)
+	case s.Rule.CounterAverage != "":
+		fmt.Printf("The scrape's Sensor uptime is unknown, so the thresholds are placeholders; review them and the TODOs before committing.\n")
+	default:
+		fmt.Printf("The scrape had no observations, so the thresholds are placeholders; review them and the TODOs before committing.\n")
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/stackrox/sensor-metrics-analyzer/internal/analyzer"
+	"github.com/stackrox/sensor-metrics-analyzer/internal/rules"
+	"github.com/stackrox/sensor-metrics-analyzer/internal/scrape"
+)
+
+// defaultSampleInterval is the time between scrapes when --samples is set without --interval
+const defaultSampleInterval = 30 * time.Second
+
+// scrapeFlags are the --url connection flags shared by analyze and watch
+type scrapeFlags struct {
+	url                *string
+	bearerToken        *string
+	bearerTokenFile    *string
+	caFile             *string
+	certFile           *string
+	keyFile            *string
+	insecureSkipVerify *bool
+	timeout            *time.Duration
+}
+
+func addScrapeFlags(fs *flag.FlagSet) *scrapeFlags {
+	return &scrapeFlags{
+		url:                fs.String("url", "", "Scrape a live metrics endpoint instead of reading a file, e.g. https://sensor:9090/metrics"),
+		bearerToken:        fs.String("bearer-token", "", "With --url, bearer token sent in the Authorization header"),
+		bearerTokenFile:    fs.String("bearer-token-file", "", "With --url, file containing the bearer token"),
+		caFile:             fs.String("ca-file", "", "With --url, CA certificate to verify the server"),
+		certFile:           fs.String("cert-file", "", "With --url, client certificate for mutual TLS"),
+		keyFile:            fs.String("key-file", "", "With --url, client key for mutual TLS"),
+		insecureSkipVerify: fs.Bool("insecure-skip-verify", false, "With --url, skip server certificate verification"),
+		timeout:            fs.Duration("timeout", scrape.DefaultTimeout, "With --url, timeout of a single scrape"),
+	}
+}
+
+func (f *scrapeFlags) options() scrape.Options {
+	return scrape.Options{
+		URL:                *f.url,
+		BearerToken:        *f.bearerToken,
+		BearerTokenFile:    *f.bearerTokenFile,
+		CAFile:             *f.caFile,
+		CertFile:           *f.certFile,
+		KeyFile:            *f.keyFile,
+		InsecureSkipVerify: *f.insecureSkipVerify,
+		Timeout:            *f.timeout,
+	}
+}
+
+// clusterNameFromURL names a scraped cluster after the endpoint host
+func clusterNameFromURL(metricsURL string) string {
+	parsed, err := url.Parse(metricsURL)
+	if err != nil {
+		return ""
+	}
+	return parsed.Hostname()
+}
+
+// analyzeURL scrapes a live metrics endpoint and analyzes the result. With more than
+// one sample, scrapes are taken interval apart and rules see rates over all of them.
+func analyzeURL(scrapeOpts scrape.Options, samples int, interval time.Duration, opts analyzer.Options) (rules.AnalysisReport, error) {
+	client, err := scrape.NewClient(scrapeOpts)
+	if err != nil {
+		return rules.AnalysisReport{}, err
+	}
+	if opts.ClusterName == "" {
+		opts.ClusterName = clusterNameFromURL(scrapeOpts.URL)
+	}
+	if samples > 1 && interval == 0 {
+		interval = defaultSampleInterval
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	bodies, err := client.ScrapeSeries(ctx, samples, interval, opts.Logger)
+	if err != nil {
+		return rules.AnalysisReport{}, err
+	}
+	opts.ScrapeTime = time.Now()
+	if len(bodies) == 1 {
+		return analyzer.AnalyzeReader(bytes.NewReader(bodies[0]), opts)
+	}
+
+	readers := make([]io.Reader, 0, len(bodies))
+	for _, body := range bodies {
+		readers = append(readers, bytes.NewReader(body))
+	}
+	return analyzer.AnalyzeSnapshotSequence(readers, interval, opts)
+}
+
+// validateURLFlags rejects flag combinations that do not apply to live scraping
+func validateURLFlags(metricsURL, baselineFile string, samples, positional int) error {
+	if metricsURL == "" {
+		if samples != 1 {
+			return fmt.Errorf("--samples requires --url")
+		}
+		return nil
+	}
+	if positional > 0 {
+		return fmt.Errorf("--url and a metrics file are mutually exclusive")
+	}
+	if baselineFile != "" {
+		return fmt.Errorf("--url and --baseline are mutually exclusive; use --samples for rates")
+	}
+	if samples < 1 {
+		return fmt.Errorf("--samples must be at least 1, got %d", samples)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/stackrox/sensor-metrics-analyzer/internal/rules"
+	"github.com/stackrox/sensor-metrics-analyzer/internal/ruletest"
+)
+
+func testRulesCommand() {
+	fs := flag.NewFlagSet("test-rules", flag.ExitOnError)
+	loadLevelDir := fs.String("load-level-dir", "", "Directory containing load detection rules (default: <rules-directory>/load-level)")
+	run := fs.String("run", "", "Only run tests whose <rule>/<test> name matches this regular expression")
+	verbose := fs.Bool("v", false, "Also list passing tests")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: metrics-analyzer test-rules [flags] [rules-directory]\n\n")
+		fmt.Fprintf(os.Stderr, "Runs the tests declared in rule files ([[tests]]) and in sibling <rule>_test.toml files.\n\n")
+		fmt.Fprintf(os.Stderr, "Arguments:\n")
+		fmt.Fprintf(os.Stderr, "  rules-directory    Directory containing TOML rule files (default: ./automated-rules)\n\n")
+		fmt.Fprintf(os.Stderr, "Flags:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\n⚠️  Note: Flags must come BEFORE the rules directory!\n")
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  metrics-analyzer test-rules\n")
+		fmt.Fprintf(os.Stderr, "  metrics-analyzer test-rules -v ./automated-rules\n")
+		fmt.Fprintf(os.Stderr, "  metrics-analyzer test-rules --run 'network_flow.*/high' ./automated-rules\n")
+	}
+
+	fs.Parse(os.Args[2:])
+
+	rulesDir := "./automated-rules"
+	if fs.NArg() > 0 {
+		rulesDir = fs.Arg(0)
+	}
+	if *loadLevelDir == "" {
+		*loadLevelDir = filepath.Join(rulesDir, "load-level")
+	}
+
+	var filter *regexp.Regexp
+	if *run != "" {
+		var err error
+		if filter, err = regexp.Compile(*run); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid --run pattern: %v\n", err)
+			os.Exit(exitCodeAnalysisFailed)
+		}
+	}
+
+	loadRules, err := rules.LoadLoadDetectionRules(*loadLevelDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load load detection rules: %v\n", err)
+		os.Exit(exitCodeAnalysisFailed)
+	}
+	loadLevels, _ := rules.LoadTiers(loadRules)
+
+	cases, err := ruletest.LoadCases(rulesDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load rule tests: %v\n", err)
+		os.Exit(exitCodeAnalysisFailed)
+	}
+
+	if filter != nil {
+		selected := cases[:0]
+		for _, c := range cases {
+			if filter.MatchString(c.Name()) {
+				selected = append(selected, c)
+			}
+		}
+		cases = selected
+	}
+
+	passed, failed := 0, 0
+	for _, result := range ruletest.RunAll(cases, loadLevels) {
+		name := result.Case.Name()
+		if result.Passed() {
+			passed++
+			if *verbose {
+				fmt.Printf("--- PASS: %s\n", name)
+			}
+			continue
+		}
+		failed++
+		fmt.Printf("--- FAIL: %s (%s)\n", name, result.Case.TestFile)
+		for _, failure := range result.Failures {
+			fmt.Printf("    %s\n", failure)
+		}
+	}
+
+	switch {
+	case failed > 0:
+		fmt.Printf("FAIL: %d of %d rule tests failed\n", failed, passed+failed)
+		os.Exit(exitCodeAnalysisFailed)
+	case passed == 0:
+		fmt.Printf("No rule tests found in %s\n", rulesDir)
+	default:
+		fmt.Printf("✅ All %d rule tests passed\n", passed)
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/stackrox/sensor-metrics-analyzer/internal/analyzer"
+	"github.com/stackrox/sensor-metrics-analyzer/internal/rules"
+	"github.com/stackrox/sensor-metrics-analyzer/internal/scrape"
+	"github.com/stackrox/sensor-metrics-analyzer/internal/tui"
+)
+
+func watchCommand() {
+	fs := flag.NewFlagSet("watch", flag.ExitOnError)
+	rulesDir := fs.String("rules", ".", "Directory containing TOML rules (default: current directory)")
+	loadLevelDir := fs.String("load-level-dir", "./load-level", "Directory containing load detection rules")
+	clusterName := fs.String("cluster", "", "Cluster name (extracted from the file name or URL host if not provided)")
+	loadLevelOverride := fs.String("load-level", "", "Override detected load level (a level declared by the load detection rules, e.g. low/medium/high)")
+	loadStrategy := fs.String("load-strategy", "max", "How to combine multiple load detection rules: max, weighted_vote")
+	acsVersionOverride := fs.String("acs-version", "", "Override detected ACS version")
+	strict := fs.Bool("strict", false, "Fail on malformed metrics lines instead of skipping them")
+	interval := fs.Duration("interval", 30*time.Second, "Time between refreshes")
+	scrapeOpts := addScrapeFlags(fs)
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: metrics-analyzer watch [flags] <metrics-file>\n")
+		fmt.Fprintf(os.Stderr, "       metrics-analyzer watch [flags] --url <metrics-url>\n\n")
+		fmt.Fprintf(os.Stderr, "Re-reads a metrics file or re-scrapes a URL on an interval and shows the\n")
+		fmt.Fprintf(os.Stderr, "refreshed analysis in the TUI, marking status changes (Δ) and value history.\n\n")
+		fmt.Fprintf(os.Stderr, "Flags:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\n⚠️  Note: Flags must come BEFORE the metrics file!\n")
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  metrics-analyzer watch --rules ./automated-rules metrics.txt\n")
+		fmt.Fprintf(os.Stderr, "  metrics-analyzer watch --url http://localhost:9090/metrics --interval 15s\n")
+	}
+
+	fs.Parse(os.Args[2:])
+
+	if (*scrapeOpts.url == "") == (fs.NArg() != 1) {
+		fmt.Fprintf(os.Stderr, "Error: expected either a metrics file or --url, flags must come before the file\n")
+		fmt.Fprintf(os.Stderr, "Usage: metrics-analyzer watch [flags] <metrics-file>\n")
+		os.Exit(exitCodeAnalysisFailed)
+	}
+	if *interval <= 0 {
+		fmt.Fprintf(os.Stderr, "Error: --interval must be positive\n")
+		os.Exit(exitCodeAnalysisFailed)
+	}
+
+	opts := analyzer.Options{
+		RulesDir:           *rulesDir,
+		LoadLevelDir:       *loadLevelDir,
+		ClusterName:        *clusterName,
+		LoadLevelOverride:  *loadLevelOverride,
+		LoadStrategy:       *loadStrategy,
+		ACSVersionOverride: *acsVersionOverride,
+		StrictParse:        *strict,
+	}
+	source, refresh, err := newRefresher(fs.Arg(0), scrapeOpts.options(), opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(exitCodeAnalysisFailed)
+	}
+
+	if err := tui.RunWatch(source, *interval, refresh); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(exitCodeAnalysisFailed)
+	}
+}
+
+// newRefresher returns a function producing a fresh report from the metrics
+// file, or from a new scrape when a URL is configured, and names its source
+func newRefresher(metricsFile string, scrapeOpts scrape.Options, opts analyzer.Options) (string, func() (rules.AnalysisReport, error), error) {
+	if scrapeOpts.URL == "" {
+		return metricsFile, func() (rules.AnalysisReport, error) {
+			return analyzer.AnalyzeFile(metricsFile, opts)
+		}, nil
+	}
+
+	client, err := scrape.NewClient(scrapeOpts)
+	if err != nil {
+		return "", nil, err
+	}
+	if opts.ClusterName == "" {
+		opts.ClusterName = clusterNameFromURL(scrapeOpts.URL)
+	}
+	return scrapeOpts.URL, func() (rules.AnalysisReport, error) {
+		body, err := client.Scrape(context.Background())
+		if err != nil {
+			return rules.AnalysisReport{}, err
+		}
+		opts.ScrapeTime = time.Now()
+		return analyzer.AnalyzeReader(bytes.NewReader(body), opts)
+	}, nil
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/stackrox/sensor-metrics-analyzer/internal/evaluator"
 	"github.com/stackrox/sensor-metrics-analyzer/internal/loadlevel"
@@ -13,6 +14,10 @@ import (
 	"github.com/stackrox/sensor-metrics-analyzer/internal/rules"
 )
 
+// minMeaningfulUptime is the Sensor uptime below which a single scrape's counters
+// cover too little activity for meaningful verdicts
+const minMeaningfulUptime = 15 * time.Minute
+
 // Options controls analysis behavior and logging.
 type Options struct {
 	RulesDir           string
@@ -21,6 +26,26 @@ type Options struct {
 	LoadLevelOverride  string
 	ACSVersionOverride string
 	Logger             io.Writer
+
+	// LoadStrategy combines the levels of multiple load detection rules:
+	// "max" (default) or "weighted_vote".
+	LoadStrategy string
+
+	// BaselineFile is an earlier scrape of the same Sensor. When set, AnalyzeFile
+	// evaluates rates over the interval between the two scrapes.
+	BaselineFile string
+	// Interval is the time between the baseline and the analyzed scrape.
+	// When zero, it is derived from the files' modification times.
+	Interval time.Duration
+
+	// StrictParse fails the analysis on malformed metrics lines instead of
+	// skipping them with a warning.
+	StrictParse bool
+
+	// ScrapeTime is when the analyzed scrape was taken, to compute the Sensor
+	// uptime from process_start_time_seconds. Sample timestamps take precedence.
+	// AnalyzeFile defaults it to the file's modification time.
+	ScrapeTime time.Time
 }
 
 // AnalyzeFile parses metrics and evaluates rules, returning the analysis report.
@@ -33,11 +58,91 @@ func AnalyzeFile(metricsFile string, opts Options) (rules.AnalysisReport, error)
 		return rules.AnalysisReport{}, err
 	}
 	defer file.Close()
-	return AnalyzeReader(file, opts)
+	if opts.ScrapeTime.IsZero() {
+		if info, err := file.Stat(); err == nil {
+			opts.ScrapeTime = info.ModTime()
+		}
+	}
+
+	if opts.BaselineFile == "" {
+		return AnalyzeReader(file, opts)
+	}
+
+	baseline, err := os.Open(opts.BaselineFile)
+	if err != nil {
+		return rules.AnalysisReport{}, err
+	}
+	defer baseline.Close()
+
+	interval := opts.Interval
+	if interval == 0 {
+		interval, err = intervalFromModTimes(opts.BaselineFile, metricsFile)
+		if err != nil {
+			return rules.AnalysisReport{}, err
+		}
+	}
+	return AnalyzeSnapshots(baseline, file, interval, opts)
 }
 
 // AnalyzeReader parses metrics from a reader and evaluates rules, returning the analysis report.
 func AnalyzeReader(reader io.Reader, opts Options) (rules.AnalysisReport, error) {
+	return analyze(opts, 0, func(logOut io.Writer) (parser.MetricsData, error) {
+		fmt.Fprintf(logOut, "Parsing metrics from reader...\n")
+		return parseMetrics(reader, opts, logOut)
+	})
+}
+
+// AnalyzeSnapshots parses two scrapes of the same Sensor taken interval apart and
+// evaluates rules against per-second counter rates and interval-only histograms.
+func AnalyzeSnapshots(baseline, current io.Reader, interval time.Duration, opts Options) (rules.AnalysisReport, error) {
+	return AnalyzeSnapshotSequence([]io.Reader{baseline, current}, interval, opts)
+}
+
+// AnalyzeSnapshotSequence parses two or more scrapes of the same Sensor, each taken
+// step after the previous one, and evaluates rules against rates over the whole sequence.
+func AnalyzeSnapshotSequence(snapshots []io.Reader, step time.Duration, opts Options) (rules.AnalysisReport, error) {
+	rateInterval := step * time.Duration(len(snapshots)-1)
+	return analyze(opts, rateInterval, func(logOut io.Writer) (parser.MetricsData, error) {
+		parsed := make([]parser.MetricsData, 0, len(snapshots))
+		for i, snapshot := range snapshots {
+			fmt.Fprintf(logOut, "Parsing %s metrics from reader...\n", snapshotName(i, len(snapshots)))
+			metrics, err := parseMetrics(snapshot, opts, logOut)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", snapshotName(i, len(snapshots)), err)
+			}
+			parsed = append(parsed, metrics)
+		}
+		fmt.Fprintf(logOut, "Computing rates over %s interval...\n", rateInterval)
+		return parser.DiffSnapshotSequence(parsed, step)
+	})
+}
+
+// snapshotName names a snapshot of a sequence in log and error messages
+func snapshotName(index, count int) string {
+	switch {
+	case index == 0:
+		return "baseline"
+	case index == count-1:
+		return "current"
+	}
+	return fmt.Sprintf("snapshot %d of %d", index+1, count)
+}
+
+// parseMetrics parses a scrape, logging malformed lines skipped in lenient mode.
+func parseMetrics(reader io.Reader, opts Options, logOut io.Writer) (parser.MetricsData, error) {
+	metrics, skipped, err := parser.ParseReaderWithOptions(reader, parser.ParseOptions{Strict: opts.StrictParse})
+	if err != nil {
+		return nil, err
+	}
+	for _, parseErr := range skipped {
+		fmt.Fprintf(logOut, "Warning: Skipped malformed metrics line: %v\n", parseErr)
+	}
+	return metrics, nil
+}
+
+// analyze loads rules, obtains metrics from parse and evaluates the rules against them.
+// rateInterval is the interval counters are per-second rates over, zero for a single scrape.
+func analyze(opts Options, rateInterval time.Duration, parse func(logOut io.Writer) (parser.MetricsData, error)) (rules.AnalysisReport, error) {
 	logOut := opts.Logger
 	if logOut == nil {
 		logOut = io.Discard
@@ -48,6 +153,11 @@ func AnalyzeReader(reader io.Reader, opts Options) (rules.AnalysisReport, error)
 		return rules.AnalysisReport{}, fmt.Errorf("rules directory is required")
 	}
 
+	loadStrategy, err := rules.ParseLoadStrategy(opts.LoadStrategy)
+	if err != nil {
+		return rules.AnalysisReport{}, err
+	}
+
 	loadLevelDir := opts.LoadLevelDir
 	if loadLevelDir == "" {
 		loadLevelDir = filepath.Join(rulesDir, "load-level")
@@ -67,8 +177,7 @@ func AnalyzeReader(reader io.Reader, opts Options) (rules.AnalysisReport, error)
 	}
 	fmt.Fprintf(logOut, "Loaded %d rules\n", len(rulesList))
 
-	fmt.Fprintf(logOut, "Parsing metrics from reader...\n")
-	metrics, err := parser.ParseReader(reader)
+	metrics, err := parse(logOut)
 	if err != nil {
 		return rules.AnalysisReport{}, fmt.Errorf("failed to parse metrics: %w", err)
 	}
@@ -84,21 +193,75 @@ func AnalyzeReader(reader io.Reader, opts Options) (rules.AnalysisReport, error)
 		}
 	}
 
-	loadDetector := loadlevel.NewDetector(loadRules)
-	detectedLoadLevel, err := loadlevel.DetectWithOverride(metrics, loadDetector, rules.LoadLevel(opts.LoadLevelOverride))
+	loadDetector := loadlevel.NewDetector(loadRules, loadStrategy)
+	for _, rule := range rulesList {
+		if undeclared := rules.UndeclaredLoadLevels(rule, loadDetector.Levels()); len(undeclared) > 0 {
+			name := rule.DisplayName
+			if name == "" {
+				name = rule.MetricName
+			}
+			fmt.Fprintf(logOut, "Warning: Rule %s has load_level_thresholds for undeclared load levels %s (declared: %s)\n",
+				name, rules.FormatLoadLevels(undeclared), rules.FormatLoadLevels(loadDetector.Levels()))
+		}
+	}
+
+	detectedLoadLevel, loadDetection, err := loadlevel.DetectWithOverride(metrics, loadDetector, rules.LoadLevel(opts.LoadLevelOverride))
 	if err != nil {
 		fmt.Fprintf(logOut, "Warning: Load level detection failed: %v\n", err)
-		detectedLoadLevel = rules.LoadLevelMedium
+		detectedLoadLevel = loadDetector.DefaultLevel()
+	}
+	fmt.Fprintf(logOut, "Detected load level: %s (%d load detection rules, %s)\n", detectedLoadLevel, len(loadRules), loadStrategy)
+
+	window := rules.CounterWindow{RateInterval: rateInterval}
+	if uptime, ok := metrics.Uptime(opts.ScrapeTime); ok {
+		window.Uptime = uptime
+		fmt.Fprintf(logOut, "Sensor uptime: %s\n", uptime.Round(time.Second))
+	} else if averagesCounters(rulesList) {
+		fmt.Fprintf(logOut, "Warning: Could not determine Sensor uptime, rules with counter_average will be UNKNOWN\n")
 	}
-	fmt.Fprintf(logOut, "Detected load level: %s\n", detectedLoadLevel)
 
 	fmt.Fprintf(logOut, "Evaluating rules...\n")
-	report := evaluator.EvaluateAllRules(rulesList, metrics, detectedLoadLevel, acsVersion)
+	report := evaluator.EvaluateAllRules(rulesList, metrics, detectedLoadLevel, acsVersion, window)
 	report.ClusterName = opts.ClusterName
+	report.LoadDetection = loadDetection
+	report.RateInterval = rateInterval
+	if rateInterval == 0 && window.Uptime > 0 && window.Uptime < minMeaningfulUptime {
+		warning := fmt.Sprintf("Sensor uptime %s is shorter than %s: counters cover too little activity for meaningful verdicts",
+			window.Uptime.Round(time.Second), minMeaningfulUptime)
+		fmt.Fprintf(logOut, "Warning: %s\n", warning)
+		report.Warnings = append(report.Warnings, warning)
+	}
 
 	return report, nil
 }
 
+// averagesCounters reports whether any rule averages counters over the uptime
+func averagesCounters(rulesList []rules.Rule) bool {
+	for _, rule := range rulesList {
+		if rule.CounterAverage != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// intervalFromModTimes derives the time between two scrapes from the files' modification times.
+func intervalFromModTimes(baselineFile, metricsFile string) (time.Duration, error) {
+	before, err := os.Stat(baselineFile)
+	if err != nil {
+		return 0, err
+	}
+	after, err := os.Stat(metricsFile)
+	if err != nil {
+		return 0, err
+	}
+	interval := after.ModTime().Sub(before.ModTime())
+	if interval <= 0 {
+		return 0, fmt.Errorf("cannot derive interval: %s is not newer than %s; specify the interval explicitly", metricsFile, baselineFile)
+	}
+	return interval, nil
+}
+
 // ExtractClusterName derives a cluster name from a file name.
 func ExtractClusterName(filename string) string {
 	base := filepath.Base(filename)
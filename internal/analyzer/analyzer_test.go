@@ -2,9 +2,12 @@ package analyzer
 
 import (
 	"bytes"
+	"fmt"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -35,3 +38,168 @@ func TestAnalyzeFile(t *testing.T) {
 	statusTotal := report.Summary.RedCount + report.Summary.YellowCount + report.Summary.GreenCount
 	assert.LessOrEqual(t, statusTotal, report.Summary.TotalAnalyzed, "AnalyzeFile() summary counts exceed total")
 }
+
+func TestAnalyzeFileWithBaseline(t *testing.T) {
+	t.Parallel()
+
+	_, thisFile, _, ok := runtime.Caller(0)
+	if !ok {
+		t.Fatal("AnalyzeFile() failed to resolve test file path")
+	}
+	repoRoot := filepath.Dir(filepath.Dir(filepath.Dir(thisFile)))
+	metricsFile := filepath.Join(repoRoot, "testdata", "fixtures", "sample_metrics.txt")
+	rulesDir := filepath.Join(repoRoot, "testdata", "fixtures")
+
+	report, err := AnalyzeFile(metricsFile, Options{
+		RulesDir:     rulesDir,
+		BaselineFile: metricsFile,
+		Interval:     5 * time.Minute,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 5*time.Minute, report.RateInterval, "AnalyzeFile() rate interval mismatch")
+	assert.NotEmpty(t, report.Results, "AnalyzeFile() returned no results")
+
+	// Identical snapshots mean no queue activity during the interval
+	for _, result := range report.Results {
+		if result.RuleName == "rox_sensor_detector_network_flow_queue_operations_total" {
+			assert.Equal(t, 0.0, result.Value, "AnalyzeFile() queue diff over interval")
+		}
+	}
+}
+
+func TestAnalyzeFileACSVersion(t *testing.T) {
+	t.Parallel()
+
+	_, thisFile, _, ok := runtime.Caller(0)
+	if !ok {
+		t.Fatal("AnalyzeFile() failed to resolve test file path")
+	}
+	repoRoot := filepath.Dir(filepath.Dir(filepath.Dir(thisFile)))
+	metricsFile := filepath.Join(repoRoot, "testdata", "fixtures", "sample_metrics.txt")
+
+	tests := map[string]struct {
+		rulesDir    string
+		acsVersion  string
+		wantResults int
+	}{
+		"should evaluate every bundled rule on the detected version": {
+			rulesDir:    "automated-rules",
+			wantResults: 29,
+		},
+		"should skip only bundled rules constrained to newer versions": {
+			rulesDir:    "automated-rules",
+			acsVersion:  "4.6.0",
+			wantResults: 28,
+		},
+		"should ignore acs_versions misplaced under remediation": {
+			rulesDir:    filepath.Join("testdata", "fixtures"),
+			acsVersion:  "4.6.0",
+			wantResults: 9,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			report, err := AnalyzeFile(metricsFile, Options{
+				RulesDir:           filepath.Join(repoRoot, tt.rulesDir),
+				ACSVersionOverride: tt.acsVersion,
+			})
+			assert.NoError(t, err)
+			assert.Len(t, report.Results, tt.wantResults, "AnalyzeFile() result count")
+		})
+	}
+}
+
+func TestAnalyzeFileWithBaselineRequiresInterval(t *testing.T) {
+	t.Parallel()
+
+	_, thisFile, _, ok := runtime.Caller(0)
+	if !ok {
+		t.Fatal("AnalyzeFile() failed to resolve test file path")
+	}
+	repoRoot := filepath.Dir(filepath.Dir(filepath.Dir(thisFile)))
+	metricsFile := filepath.Join(repoRoot, "testdata", "fixtures", "sample_metrics.txt")
+
+	_, err := AnalyzeFile(metricsFile, Options{
+		RulesDir:     filepath.Join(repoRoot, "testdata", "fixtures"),
+		BaselineFile: metricsFile,
+	})
+	assert.Error(t, err, "AnalyzeFile() should fail when interval cannot be derived")
+}
+
+func TestAnalyzeReaderMalformedLines(t *testing.T) {
+	t.Parallel()
+
+	_, thisFile, _, ok := runtime.Caller(0)
+	if !ok {
+		t.Fatal("AnalyzeReader() failed to resolve test file path")
+	}
+	repoRoot := filepath.Dir(filepath.Dir(filepath.Dir(thisFile)))
+	rulesDir := filepath.Join(repoRoot, "testdata", "fixtures")
+	input := "queue_size 7\nbroken{label=\"x} 1\n"
+
+	var logs bytes.Buffer
+	_, err := AnalyzeReader(strings.NewReader(input), Options{RulesDir: rulesDir, Logger: &logs})
+	assert.NoError(t, err)
+	assert.Contains(t, logs.String(), "Warning: Skipped malformed metrics line: line 2", "AnalyzeReader() did not log the skipped line")
+
+	_, err = AnalyzeReader(strings.NewReader(input), Options{RulesDir: rulesDir, StrictParse: true})
+	assert.ErrorContains(t, err, "line 2", "AnalyzeReader() strict mode did not fail on the malformed line")
+}
+
+func TestAnalyzeReaderUptime(t *testing.T) {
+	t.Parallel()
+
+	_, thisFile, _, ok := runtime.Caller(0)
+	if !ok {
+		t.Fatal("AnalyzeReader() failed to resolve test file path")
+	}
+	repoRoot := filepath.Dir(filepath.Dir(filepath.Dir(thisFile)))
+	rulesDir := filepath.Join(repoRoot, "testdata", "fixtures")
+	scrapeTime := time.Unix(1700000000, 0)
+
+	tests := map[string]struct {
+		startTime    time.Time
+		wantUptime   time.Duration
+		wantWarnings int
+	}{
+		"should report a long uptime without warnings": {
+			startTime:  scrapeTime.Add(-48 * time.Hour),
+			wantUptime: 48 * time.Hour,
+		},
+		"should warn about a short uptime": {
+			startTime:    scrapeTime.Add(-5 * time.Minute),
+			wantUptime:   5 * time.Minute,
+			wantWarnings: 1,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			input := fmt.Sprintf("queue_size 7\nprocess_start_time_seconds %d\n", tt.startTime.Unix())
+			report, err := AnalyzeReader(strings.NewReader(input), Options{RulesDir: rulesDir, ScrapeTime: scrapeTime})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantUptime, report.Uptime, "AnalyzeReader() uptime mismatch")
+			assert.Len(t, report.Warnings, tt.wantWarnings, "AnalyzeReader() warnings mismatch")
+		})
+	}
+}
+
+func TestAnalyzeReaderWithoutHistograms(t *testing.T) {
+	t.Parallel()
+
+	_, thisFile, _, ok := runtime.Caller(0)
+	if !ok {
+		t.Fatal("AnalyzeReader() failed to resolve test file path")
+	}
+	repoRoot := filepath.Dir(filepath.Dir(filepath.Dir(thisFile)))
+	rulesDir := filepath.Join(repoRoot, "automated-rules")
+
+	report, err := AnalyzeReader(strings.NewReader("go_goroutines 5\n"), Options{RulesDir: rulesDir})
+	assert.NoError(t, err)
+	for _, result := range report.Results {
+		assert.NotContains(t, []string{"*", "Histogram +Inf overflow"}, result.RuleName,
+			"AnalyzeReader() the bundled +Inf overflow rule should report nothing without histograms")
+	}
+}
@@ -0,0 +1,246 @@
+package analyzer
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stackrox/sensor-metrics-analyzer/internal/rules"
+)
+
+// bundleMetricsSuffix identifies Sensor metrics files in diagnostic bundles
+const bundleMetricsSuffix = "-sensor-metrics.txt"
+
+// bundleFile is a metrics file discovered in a bundle
+type bundleFile struct {
+	path    string    // Slash-separated path relative to the bundle root
+	modTime time.Time // When the file was written, used as the scrape time
+	open    func() (io.ReadCloser, error)
+}
+
+// AnalyzeBundle analyzes every Sensor metrics file (*-sensor-metrics.txt) found in a
+// directory, .zip or .tar.gz diagnostic bundle. Files are analyzed concurrently;
+// a file that fails to analyze is reported in its ClusterReport instead of failing
+// the whole bundle. opts.ClusterName and opts.BaselineFile are ignored.
+func AnalyzeBundle(bundlePath string, opts Options) (rules.BundleReport, error) {
+	logOut := opts.Logger
+	if logOut == nil {
+		logOut = io.Discard
+	}
+
+	files, cleanup, err := discoverBundle(bundlePath)
+	if err != nil {
+		return rules.BundleReport{}, err
+	}
+	defer cleanup()
+	if len(files) == 0 {
+		return rules.BundleReport{}, fmt.Errorf("no *%s files found in %s", bundleMetricsSuffix, bundlePath)
+	}
+	fmt.Fprintf(logOut, "Found %d metrics files in %s\n", len(files), bundlePath)
+
+	clusters := make([]rules.ClusterReport, len(files))
+	logs := make([]bytes.Buffer, len(files))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for worker := 0; worker < min(runtime.GOMAXPROCS(0), len(files)); worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				clusters[i] = analyzeBundleFile(files[i], opts, &logs[i])
+			}
+		}()
+	}
+	for i := range files {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	// Per-file logs are buffered so concurrent analyses do not interleave
+	report := rules.BundleReport{
+		Source:    bundlePath,
+		Timestamp: time.Now(),
+		Clusters:  clusters,
+	}
+	for i, cluster := range clusters {
+		fmt.Fprintf(logOut, "==> %s (%s)\n", cluster.ClusterName, cluster.File)
+		logOut.Write(logs[i].Bytes())
+		if cluster.Error != "" {
+			fmt.Fprintf(logOut, "Warning: Failed to analyze %s: %s\n", cluster.File, cluster.Error)
+			continue
+		}
+		report.Summary.TotalAnalyzed += cluster.Report.Summary.TotalAnalyzed
+		report.Summary.RedCount += cluster.Report.Summary.RedCount
+		report.Summary.YellowCount += cluster.Report.Summary.YellowCount
+		report.Summary.GreenCount += cluster.Report.Summary.GreenCount
+		report.Summary.UnknownCount += cluster.Report.Summary.UnknownCount
+	}
+	return report, nil
+}
+
+// analyzeBundleFile analyzes one metrics file of a bundle
+func analyzeBundleFile(file bundleFile, opts Options, logOut io.Writer) rules.ClusterReport {
+	cluster := rules.ClusterReport{
+		ClusterName: ExtractClusterName(file.path),
+		File:        file.path,
+	}
+
+	reader, err := file.open()
+	if err != nil {
+		cluster.Error = err.Error()
+		return cluster
+	}
+	defer reader.Close()
+
+	opts.ClusterName = cluster.ClusterName
+	opts.BaselineFile = ""
+	opts.Logger = logOut
+	if !file.modTime.IsZero() {
+		opts.ScrapeTime = file.modTime
+	}
+	report, err := AnalyzeReader(reader, opts)
+	if err != nil {
+		cluster.Error = err.Error()
+		return cluster
+	}
+	cluster.Report = report
+	return cluster
+}
+
+// discoverBundle lists the metrics files of a directory or archive, sorted by path.
+// The returned cleanup function releases the archive and must be called once the
+// files are no longer read.
+func discoverBundle(bundlePath string) ([]bundleFile, func(), error) {
+	info, err := os.Stat(bundlePath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var files []bundleFile
+	cleanup := func() {}
+	name := strings.ToLower(bundlePath)
+	switch {
+	case info.IsDir():
+		files, err = discoverDirectory(bundlePath)
+	case strings.HasSuffix(name, ".zip"):
+		var archive *zip.ReadCloser
+		archive, err = zip.OpenReader(bundlePath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open zip bundle: %w", err)
+		}
+		cleanup = func() { archive.Close() }
+		files = discoverZip(&archive.Reader)
+	case strings.HasSuffix(name, ".tar.gz") || strings.HasSuffix(name, ".tgz"):
+		files, err = discoverTarGz(bundlePath)
+	default:
+		return nil, nil, fmt.Errorf("unsupported bundle %s: expected a directory, .zip or .tar.gz", bundlePath)
+	}
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].path < files[j].path
+	})
+	return files, cleanup, nil
+}
+
+func isBundleMetricsFile(name string) bool {
+	return strings.HasSuffix(path.Base(name), bundleMetricsSuffix)
+}
+
+func discoverDirectory(dir string) ([]bundleFile, error) {
+	var files []bundleFile
+	err := filepath.WalkDir(dir, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || !isBundleMetricsFile(entry.Name()) {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, filePath)
+		if err != nil {
+			return err
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		files = append(files, bundleFile{
+			path:    filepath.ToSlash(rel),
+			modTime: info.ModTime(),
+			open:    func() (io.ReadCloser, error) { return os.Open(filePath) },
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle directory: %w", err)
+	}
+	return files, nil
+}
+
+func discoverZip(archive *zip.Reader) []bundleFile {
+	var files []bundleFile
+	for _, entry := range archive.File {
+		if entry.FileInfo().IsDir() || !isBundleMetricsFile(entry.Name) {
+			continue
+		}
+		files = append(files, bundleFile{path: entry.Name, modTime: entry.Modified, open: entry.Open})
+	}
+	return files
+}
+
+// discoverTarGz reads the metrics files of a gzipped tarball into memory,
+// since tar entries can only be read sequentially.
+func discoverTarGz(archivePath string) ([]bundleFile, error) {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open tar.gz bundle: %w", err)
+	}
+	defer gz.Close()
+
+	var files []bundleFile
+	archive := tar.NewReader(gz)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tar.gz bundle: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg || !isBundleMetricsFile(header.Name) {
+			continue
+		}
+		data, err := io.ReadAll(archive)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s from tar.gz bundle: %w", header.Name, err)
+		}
+		files = append(files, bundleFile{
+			path:    strings.TrimPrefix(header.Name, "./"),
+			modTime: header.ModTime,
+			open:    func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(data)), nil },
+		})
+	}
+	return files, nil
+}
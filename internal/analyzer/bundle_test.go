@@ -0,0 +1,212 @@
+package analyzer
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeBundle(t *testing.T) {
+	t.Parallel()
+
+	_, thisFile, _, ok := runtime.Caller(0)
+	if !ok {
+		t.Fatal("AnalyzeBundle() failed to resolve test file path")
+	}
+	repoRoot := filepath.Dir(filepath.Dir(filepath.Dir(thisFile)))
+	rulesDir := filepath.Join(repoRoot, "testdata", "fixtures")
+	metrics, err := os.ReadFile(filepath.Join(repoRoot, "testdata", "fixtures", "sample_metrics.txt"))
+	require.NoError(t, err)
+
+	entries := map[string][]byte{
+		"clusters/prod/prod-sensor-metrics.txt":       metrics,
+		"clusters/staging/staging-sensor-metrics.txt": metrics,
+		"clusters/staging/broken-sensor-metrics.txt":  []byte("queue_size{label=\"x} 1\n"),
+		"clusters/prod/central-metrics.txt":           metrics,
+	}
+
+	tests := map[string]struct {
+		build func(t *testing.T, dir string) string
+	}{
+		"should analyze a directory": {
+			build: func(t *testing.T, dir string) string {
+				for name, data := range entries {
+					path := filepath.Join(dir, filepath.FromSlash(name))
+					require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+					require.NoError(t, os.WriteFile(path, data, 0644))
+				}
+				return dir
+			},
+		},
+		"should analyze a zip archive": {
+			build: func(t *testing.T, dir string) string {
+				path := filepath.Join(dir, "bundle.zip")
+				file, err := os.Create(path)
+				require.NoError(t, err)
+				defer file.Close()
+				archive := zip.NewWriter(file)
+				for name, data := range entries {
+					w, err := archive.Create(name)
+					require.NoError(t, err)
+					_, err = w.Write(data)
+					require.NoError(t, err)
+				}
+				require.NoError(t, archive.Close())
+				return path
+			},
+		},
+		"should analyze a tar.gz archive": {
+			build: func(t *testing.T, dir string) string {
+				path := filepath.Join(dir, "bundle.tar.gz")
+				file, err := os.Create(path)
+				require.NoError(t, err)
+				defer file.Close()
+				gz := gzip.NewWriter(file)
+				archive := tar.NewWriter(gz)
+				for name, data := range entries {
+					require.NoError(t, archive.WriteHeader(&tar.Header{
+						Name:     "./" + name,
+						Mode:     0644,
+						Size:     int64(len(data)),
+						Typeflag: tar.TypeReg,
+					}))
+					_, err := archive.Write(data)
+					require.NoError(t, err)
+				}
+				require.NoError(t, archive.Close())
+				require.NoError(t, gz.Close())
+				return path
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			bundle := tt.build(t, t.TempDir())
+			report, err := AnalyzeBundle(bundle, Options{RulesDir: rulesDir, StrictParse: true})
+			require.NoError(t, err)
+
+			require.Len(t, report.Clusters, 3, "AnalyzeBundle() should only pick up Sensor metrics files")
+			assert.Equal(t, bundle, report.Source)
+
+			// Clusters are sorted by file path
+			assert.Equal(t, "prod", report.Clusters[0].ClusterName)
+			assert.Equal(t, "clusters/prod/prod-sensor-metrics.txt", report.Clusters[0].File)
+			assert.Equal(t, "broken", report.Clusters[1].ClusterName)
+			assert.Contains(t, report.Clusters[1].Error, "line 1", "AnalyzeBundle() should report the malformed file")
+			assert.Equal(t, "staging", report.Clusters[2].ClusterName)
+
+			for _, i := range []int{0, 2} {
+				assert.Empty(t, report.Clusters[i].Error)
+				assert.Equal(t, report.Clusters[i].ClusterName, report.Clusters[i].Report.ClusterName)
+				assert.NotEmpty(t, report.Clusters[i].Report.Results)
+			}
+			assert.Equal(t, 2*report.Clusters[0].Report.Summary.TotalAnalyzed, report.Summary.TotalAnalyzed,
+				"AnalyzeBundle() summary should total the analyzed clusters")
+		})
+	}
+}
+
+func TestAnalyzeBundleCounterAverage(t *testing.T) {
+	t.Parallel()
+
+	rulesDir := t.TempDir()
+	rule := "rule_type = \"gauge_threshold\"\nmetric_name = \"events_total\"\ncounter_average = \"per_hour\"\n" +
+		"[thresholds]\nlow = 1000\nhigh = 5000\nhigher_is_worse = true\n"
+	require.NoError(t, os.WriteFile(filepath.Join(rulesDir, "events.toml"), []byte(rule), 0644))
+
+	// Two hours after the start, the file modification time is the scrape time
+	start := time.Unix(1700000000, 0)
+	modTime := start.Add(2 * time.Hour)
+	name := "prod-sensor-metrics.txt"
+	data := []byte(fmt.Sprintf("process_start_time_seconds %d\n# TYPE events_total counter\nevents_total 7200\n", start.Unix()))
+
+	tests := map[string]struct {
+		build func(t *testing.T, dir string) string
+	}{
+		"should use the modification time of directory entries": {
+			build: func(t *testing.T, dir string) string {
+				path := filepath.Join(dir, name)
+				require.NoError(t, os.WriteFile(path, data, 0644))
+				require.NoError(t, os.Chtimes(path, modTime, modTime))
+				return dir
+			},
+		},
+		"should use the modification time of zip entries": {
+			build: func(t *testing.T, dir string) string {
+				path := filepath.Join(dir, "bundle.zip")
+				file, err := os.Create(path)
+				require.NoError(t, err)
+				defer file.Close()
+				archive := zip.NewWriter(file)
+				w, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: modTime})
+				require.NoError(t, err)
+				_, err = w.Write(data)
+				require.NoError(t, err)
+				require.NoError(t, archive.Close())
+				return path
+			},
+		},
+		"should use the modification time of tar.gz entries": {
+			build: func(t *testing.T, dir string) string {
+				path := filepath.Join(dir, "bundle.tar.gz")
+				file, err := os.Create(path)
+				require.NoError(t, err)
+				defer file.Close()
+				gz := gzip.NewWriter(file)
+				archive := tar.NewWriter(gz)
+				require.NoError(t, archive.WriteHeader(&tar.Header{
+					Name:     name,
+					Mode:     0644,
+					Size:     int64(len(data)),
+					ModTime:  modTime,
+					Typeflag: tar.TypeReg,
+				}))
+				_, err = archive.Write(data)
+				require.NoError(t, err)
+				require.NoError(t, archive.Close())
+				require.NoError(t, gz.Close())
+				return path
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			report, err := AnalyzeBundle(tt.build(t, t.TempDir()), Options{RulesDir: rulesDir})
+			require.NoError(t, err)
+			require.Len(t, report.Clusters, 1)
+
+			cluster := report.Clusters[0].Report
+			assert.Equal(t, 2*time.Hour, cluster.Uptime, "AnalyzeBundle() uptime")
+			require.Len(t, cluster.Results, 1)
+			assert.Equal(t, 3600.0, cluster.Results[0].Value, "AnalyzeBundle() should average the counter over the uptime")
+		})
+	}
+}
+
+func TestAnalyzeBundleErrors(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	_, err := AnalyzeBundle(dir, Options{RulesDir: dir})
+	assert.ErrorContains(t, err, "no *-sensor-metrics.txt files found")
+
+	unsupported := filepath.Join(dir, "bundle.rar")
+	require.NoError(t, os.WriteFile(unsupported, nil, 0644))
+	_, err = AnalyzeBundle(unsupported, Options{RulesDir: dir})
+	assert.ErrorContains(t, err, "unsupported bundle")
+}
@@ -0,0 +1,259 @@
+package analyzer
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/stackrox/sensor-metrics-analyzer/internal/evaluator"
+	"github.com/stackrox/sensor-metrics-analyzer/internal/expr"
+	"github.com/stackrox/sensor-metrics-analyzer/internal/parser"
+	"github.com/stackrox/sensor-metrics-analyzer/internal/rules"
+)
+
+// sensorMetricPrefix selects the metrics a coverage report expects rules for
+const sensorMetricPrefix = "rox_sensor_"
+
+// typeOrder ranks uncovered metric types: distributions first, as they carry
+// latency information no other metric replaces, then gauges and counters
+var typeOrder = map[string]int{
+	"histogram":      0,
+	"gaugehistogram": 0,
+	"summary":        1,
+	"gauge":          2,
+	"counter":        3,
+	"untyped":        4,
+}
+
+// CoverageFile parses a metrics file and checks which rules reference absent
+// metrics and which Sensor metrics no rule references.
+func CoverageFile(metricsFile string, opts Options) (rules.CoverageReport, error) {
+	logOut := opts.Logger
+	if logOut == nil {
+		logOut = io.Discard
+	}
+	if opts.RulesDir == "" {
+		return rules.CoverageReport{}, fmt.Errorf("rules directory is required")
+	}
+	loadLevelDir := opts.LoadLevelDir
+	if loadLevelDir == "" {
+		loadLevelDir = filepath.Join(opts.RulesDir, "load-level")
+	}
+
+	fmt.Fprintf(logOut, "Loading load detection rules from %s...\n", loadLevelDir)
+	loadRules, err := rules.LoadLoadDetectionRules(loadLevelDir)
+	if err != nil {
+		fmt.Fprintf(logOut, "Warning: Failed to load load detection rules: %v\n", err)
+		loadRules = nil
+	}
+
+	fmt.Fprintf(logOut, "Loading rules from %s...\n", opts.RulesDir)
+	rulesList, err := rules.LoadRules(opts.RulesDir)
+	if err != nil {
+		return rules.CoverageReport{}, fmt.Errorf("failed to load rules: %w", err)
+	}
+
+	file, err := os.Open(metricsFile)
+	if err != nil {
+		return rules.CoverageReport{}, err
+	}
+	defer file.Close()
+	metrics, err := parseMetrics(file, opts, logOut)
+	if err != nil {
+		return rules.CoverageReport{}, fmt.Errorf("failed to parse metrics: %w", err)
+	}
+
+	acsVersion := opts.ACSVersionOverride
+	if acsVersion == "" {
+		if detected, ok := metrics.DetectACSVersion(); ok {
+			acsVersion = detected
+			fmt.Fprintf(logOut, "Detected ACS version: %s\n", acsVersion)
+		} else {
+			fmt.Fprintf(logOut, "Warning: Could not detect ACS version, checking all rules\n")
+		}
+	}
+
+	report := CheckCoverage(rulesList, loadRules, metrics, acsVersion)
+	report.Source = metricsFile
+	return report, nil
+}
+
+// CheckCoverage compares the metrics referenced by rules and load detection
+// rules with a scrape. Rules not applicable to acsVersion are skipped; an empty
+// version checks every rule.
+func CheckCoverage(ruleList []rules.Rule, loadRules []rules.LoadDetectionRule, metrics parser.MetricsData, acsVersion string) rules.CoverageReport {
+	report := rules.CoverageReport{ACSVersion: acsVersion}
+	families := metricFamilies(metrics)
+	covered := make(map[string]bool)
+
+	check := func(name string, referenced []string) {
+		report.Summary.Rules++
+		coverage := rules.RuleCoverage{RuleName: name}
+		for _, metric := range referenced {
+			family, present := resolveFamily(metrics, families, metric)
+			covered[family] = true
+			if present {
+				coverage.Matched++
+			} else {
+				coverage.Missing = append(coverage.Missing, metric)
+			}
+		}
+		if len(coverage.Missing) > 0 {
+			report.Rules = append(report.Rules, coverage)
+		}
+	}
+
+	for _, rule := range ruleList {
+		if acsVersion != "" && !evaluator.IsRuleApplicable(rule, acsVersion) {
+			report.Summary.SkippedRules++
+			continue
+		}
+		name := rule.DisplayName
+		if name == "" {
+			name = rule.MetricName
+		}
+		referenced := referencedMetrics(rule)
+		if rules.IsMetricPattern(rule.MetricName) {
+			referenced = expandPattern(rule, metrics, referenced)
+		}
+		check(name, referenced)
+	}
+	for _, rule := range loadRules {
+		var referenced []string
+		for _, metric := range rule.Metrics {
+			referenced = appendUnique(referenced, metric.Source)
+		}
+		check(rule.DisplayName, referenced)
+	}
+
+	for name, family := range families {
+		if !strings.HasPrefix(name, sensorMetricPrefix) {
+			continue
+		}
+		report.Summary.SensorMetrics++
+		if !covered[name] {
+			report.Uncovered = append(report.Uncovered, *family)
+		}
+	}
+
+	sort.Slice(report.Rules, func(i, j int) bool {
+		return report.Rules[i].RuleName < report.Rules[j].RuleName
+	})
+	sort.Slice(report.Uncovered, func(i, j int) bool {
+		a, b := report.Uncovered[i], report.Uncovered[j]
+		if typeOrder[a.Type] != typeOrder[b.Type] {
+			return typeOrder[a.Type] < typeOrder[b.Type]
+		}
+		return a.Name < b.Name
+	})
+	report.Summary.RulesWithMissing = len(report.Rules)
+	report.Summary.UncoveredMetrics = len(report.Uncovered)
+	return report
+}
+
+// referencedMetrics lists the metrics a rule reads, in rule order without duplicates
+func referencedMetrics(rule rules.Rule) []string {
+	var names []string
+	names = appendUnique(names, rule.MetricName)
+	if rule.PercentageConfig != nil {
+		names = appendUnique(names, rule.PercentageConfig.Numerator, rule.PercentageConfig.Denominator)
+	}
+	if rule.CacheConfig != nil {
+		names = appendUnique(names, rule.CacheConfig.HitsMetric, rule.CacheConfig.MissesMetric)
+	}
+	if rule.CompositeConfig != nil {
+		for _, metric := range rule.CompositeConfig.Metrics {
+			names = appendUnique(names, metric.Source)
+		}
+	}
+	if rule.ExpressionConfig != nil {
+		if e, err := expr.Parse(rule.ExpressionConfig.Expression); err == nil {
+			names = appendUnique(names, e.MetricNames()...)
+		}
+	}
+	if rule.Correlation != nil {
+		for _, cond := range rule.Correlation.SuppressIf {
+			names = appendUnique(names, cond.MetricName)
+		}
+		for _, cond := range rule.Correlation.ElevateIf {
+			names = appendUnique(names, cond.MetricName)
+		}
+	}
+	return names
+}
+
+// expandPattern replaces the metric_name pattern of a rule by the metrics it
+// matches. A pattern matching nothing is kept and reported as missing, unless
+// the rule allows it to be empty.
+func expandPattern(rule rules.Rule, metrics parser.MetricsData, referenced []string) []string {
+	matches, err := evaluator.MatchMetricPattern(rule, metrics)
+	if err != nil || (len(matches) == 0 && !rule.AllowEmpty) {
+		return referenced
+	}
+	expanded := appendUnique(nil, matches...)
+	for _, name := range referenced {
+		if name != rule.MetricName {
+			expanded = appendUnique(expanded, name)
+		}
+	}
+	return expanded
+}
+
+func appendUnique(names []string, add ...string) []string {
+	for _, name := range add {
+		if name == "" {
+			continue
+		}
+		duplicate := false
+		for _, existing := range names {
+			if existing == name {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// metricFamilies groups the series of a scrape into metric families. The
+// _bucket, _sum and _count series of a histogram belong to the family
+// declared by its # TYPE line.
+func metricFamilies(metrics parser.MetricsData) map[string]*rules.UncoveredMetric {
+	families := make(map[string]*rules.UncoveredMetric)
+	for name, metric := range metrics {
+		if len(metric.Values) == 0 {
+			continue
+		}
+		familyName := metrics.FamilyOf(name)
+		family := families[familyName]
+		if family == nil {
+			family = &rules.UncoveredMetric{Name: familyName, Type: "untyped"}
+			if declared, ok := metrics[familyName]; ok {
+				if declared.Type != "" {
+					family.Type = declared.Type
+				}
+				family.Help = declared.Help
+			}
+			families[familyName] = family
+		}
+		family.Series += len(metric.Values)
+	}
+	return families
+}
+
+// resolveFamily returns the family a referenced metric belongs to and whether
+// the scrape has series for it. Histogram rules reference the family name,
+// other rules usually a series name.
+func resolveFamily(metrics parser.MetricsData, families map[string]*rules.UncoveredMetric, name string) (string, bool) {
+	if metric, ok := metrics[name]; ok && len(metric.Values) > 0 {
+		return metrics.FamilyOf(name), true
+	}
+	_, present := families[name]
+	return name, present
+}
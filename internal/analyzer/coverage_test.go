@@ -0,0 +1,138 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stackrox/sensor-metrics-analyzer/internal/parser"
+	"github.com/stackrox/sensor-metrics-analyzer/internal/rules"
+)
+
+const coverageMetrics = `# TYPE rox_sensor_info gauge
+rox_sensor_info{version="4.8.0"} 1
+# TYPE rox_sensor_queue_size gauge
+rox_sensor_queue_size{component="a"} 3
+rox_sensor_queue_size{component="b"} 4
+# TYPE rox_sensor_event_duration histogram
+rox_sensor_event_duration_bucket{le="1"} 2
+rox_sensor_event_duration_bucket{le="+Inf"} 3
+rox_sensor_event_duration_sum 2.5
+rox_sensor_event_duration_count 3
+# TYPE rox_sensor_events_total counter
+rox_sensor_events_total 10
+# TYPE rox_sensor_latency summary
+rox_sensor_latency{quantile="0.5"} 1
+rox_sensor_latency_sum 5
+rox_sensor_latency_count 5
+# TYPE go_goroutines gauge
+go_goroutines 42
+`
+
+func TestCheckCoverage(t *testing.T) {
+	t.Parallel()
+
+	metrics, err := parser.ParseReader(strings.NewReader(coverageMetrics))
+	require.NoError(t, err)
+
+	ruleList := []rules.Rule{
+		{DisplayName: "queue", RuleType: rules.RuleTypeGauge, MetricName: "rox_sensor_queue_size"},
+		{DisplayName: "duration", RuleType: rules.RuleTypeHistogram, MetricName: "rox_sensor_event_duration"},
+		{DisplayName: "cache", RuleType: rules.RuleTypeCacheHit, CacheConfig: &rules.CacheConfig{HitsMetric: "rox_sensor_hits", MissesMetric: "rox_sensor_misses"}},
+		{
+			DisplayName: "correlated", RuleType: rules.RuleTypeGauge, MetricName: "go_goroutines",
+			Correlation: &rules.CorrelationConfig{SuppressIf: []rules.CorrelationCondition{{MetricName: "rox_sensor_gone"}}},
+		},
+		{DisplayName: "legacy", RuleType: rules.RuleTypeGauge, MetricName: "rox_sensor_legacy", MaxACSVersion: "4.6"},
+	}
+	loadRules := []rules.LoadDetectionRule{
+		{DisplayName: "load", Metrics: []rules.LoadDetectionMetric{{Source: "rox_sensor_events_total"}}},
+	}
+
+	report := CheckCoverage(ruleList, loadRules, metrics, "4.8.0")
+
+	tests := map[string]struct {
+		got  interface{}
+		want interface{}
+	}{
+		"should list rules with absent metrics by name": {
+			got: report.Rules,
+			want: []rules.RuleCoverage{
+				{RuleName: "cache", Missing: []string{"rox_sensor_hits", "rox_sensor_misses"}},
+				{RuleName: "correlated", Missing: []string{"rox_sensor_gone"}, Matched: 1},
+			},
+		},
+		"should rank uncovered sensor metrics by type": {
+			got: report.Uncovered,
+			want: []rules.UncoveredMetric{
+				{Name: "rox_sensor_latency", Type: "summary", Series: 3},
+				{Name: "rox_sensor_info", Type: "gauge", Series: 1},
+			},
+		},
+		"should count rules and metrics": {
+			got:  report.Summary,
+			want: rules.CoverageSummary{Rules: 5, SkippedRules: 1, RulesWithMissing: 2, SensorMetrics: 5, UncoveredMetrics: 2},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.got)
+		})
+	}
+}
+
+func TestCheckCoveragePatterns(t *testing.T) {
+	t.Parallel()
+
+	metrics, err := parser.ParseReader(strings.NewReader(coverageMetrics))
+	require.NoError(t, err)
+
+	ruleList := []rules.Rule{
+		{DisplayName: "sensor gauges", RuleType: rules.RuleTypeGauge, MetricName: "rox_sensor_*"},
+		{DisplayName: "distributions", RuleType: rules.RuleTypeSummary, MetricName: "/rox_sensor_(latency|duration)/"},
+		{DisplayName: "absent", RuleType: rules.RuleTypeGauge, MetricName: "rox_collector_*"},
+		{DisplayName: "optional", RuleType: rules.RuleTypeGauge, MetricName: "rox_compliance_*", AllowEmpty: true},
+	}
+
+	report := CheckCoverage(ruleList, nil, metrics, "")
+
+	assert.Equal(t, []rules.RuleCoverage{{RuleName: "absent", Missing: []string{"rox_collector_*"}}}, report.Rules,
+		"CheckCoverage() should report patterns matching nothing")
+	assert.Equal(t, []rules.UncoveredMetric{{Name: "rox_sensor_event_duration", Type: "histogram", Series: 4}}, report.Uncovered,
+		"CheckCoverage() should count metrics matched by patterns as covered")
+}
+
+func TestReferencedMetrics(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		rule rules.Rule
+		want []string
+	}{
+		"should read percentage metrics": {
+			rule: rules.Rule{PercentageConfig: &rules.PercentageConfig{Numerator: "a", Denominator: "b"}},
+			want: []string{"a", "b"},
+		},
+		"should read composite sources without duplicates": {
+			rule: rules.Rule{CompositeConfig: &rules.CompositeConfig{Metrics: []rules.CompositeMetric{{Source: "a"}, {Source: "b"}, {Source: "a"}}}},
+			want: []string{"a", "b"},
+		},
+		"should read the metrics of an expression": {
+			rule: rules.Rule{ExpressionConfig: &rules.ExpressionConfig{Expression: "sum(b) / a"}},
+			want: []string{"a", "b"},
+		},
+		"should read correlation metrics after the rule metric": {
+			rule: rules.Rule{MetricName: "a", Correlation: &rules.CorrelationConfig{ElevateIf: []rules.CorrelationCondition{{MetricName: "c"}}}},
+			want: []string{"a", "c"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, referencedMetrics(tt.rule))
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package analyzer
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/stackrox/sensor-metrics-analyzer/internal/rules"
+)
+
+// changeOrder sorts diff results so the most actionable changes come first
+var changeOrder = map[rules.ChangeKind]int{
+	rules.ChangeRegressed: 0,
+	rules.ChangeImproved:  1,
+	rules.ChangeAdded:     2,
+	rules.ChangeRemoved:   3,
+	rules.ChangeUnchanged: 4,
+}
+
+// DiffReports compares two analyses result by result. Results are matched by
+// rule name; when a name occurs several times, occurrences are paired in order.
+func DiffReports(before, after rules.AnalysisReport) rules.ReportDiff {
+	diff := rules.ReportDiff{Before: before, After: after}
+
+	remaining := make(map[string][]*rules.EvaluationResult)
+	for i := range before.Results {
+		r := &before.Results[i]
+		remaining[r.RuleName] = append(remaining[r.RuleName], r)
+	}
+
+	for i := range after.Results {
+		r := &after.Results[i]
+		entry := rules.ResultDiff{RuleName: r.RuleName, After: r, Change: rules.ChangeAdded}
+		if matches := remaining[r.RuleName]; len(matches) > 0 {
+			entry.Before = matches[0]
+			remaining[r.RuleName] = matches[1:]
+			entry.Change = compareStatus(entry.Before.Status, r.Status)
+		}
+		diff.Results = append(diff.Results, entry)
+	}
+	for _, unmatched := range remaining {
+		for _, r := range unmatched {
+			diff.Results = append(diff.Results, rules.ResultDiff{RuleName: r.RuleName, Before: r, Change: rules.ChangeRemoved})
+		}
+	}
+
+	sort.SliceStable(diff.Results, func(i, j int) bool {
+		a, b := diff.Results[i], diff.Results[j]
+		if changeOrder[a.Change] != changeOrder[b.Change] {
+			return changeOrder[a.Change] < changeOrder[b.Change]
+		}
+		return a.RuleName < b.RuleName
+	})
+
+	for _, r := range diff.Results {
+		switch r.Change {
+		case rules.ChangeRegressed:
+			diff.Summary.Regressed++
+		case rules.ChangeImproved:
+			diff.Summary.Improved++
+		case rules.ChangeAdded:
+			diff.Summary.Added++
+		case rules.ChangeRemoved:
+			diff.Summary.Removed++
+		case rules.ChangeUnchanged:
+			diff.Summary.Unchanged++
+		}
+	}
+	return diff
+}
+
+// compareStatus classifies a status transition by severity. A rule that stopped
+// evaluating regressed; one that evaluates again improved unless it is not GREEN.
+func compareStatus(before, after rules.Status) rules.ChangeKind {
+	beforeUnknown, afterUnknown := isUnknown(before), isUnknown(after)
+	switch {
+	case beforeUnknown && afterUnknown:
+		return rules.ChangeUnchanged
+	case afterUnknown:
+		return rules.ChangeRegressed
+	case beforeUnknown && statusSeverity(after) > 0:
+		return rules.ChangeRegressed
+	case beforeUnknown:
+		return rules.ChangeImproved
+	case statusSeverity(after) > statusSeverity(before):
+		return rules.ChangeRegressed
+	case statusSeverity(after) < statusSeverity(before):
+		return rules.ChangeImproved
+	}
+	return rules.ChangeUnchanged
+}
+
+// statusSeverity ranks a status; correlation rules may produce lower-case statuses
+func statusSeverity(status rules.Status) int {
+	switch rules.Status(strings.ToUpper(string(status))) {
+	case rules.StatusRed:
+		return 2
+	case rules.StatusYellow:
+		return 1
+	}
+	return 0
+}
+
+// isUnknown reports whether a status marks a rule that could not be evaluated
+func isUnknown(status rules.Status) bool {
+	return rules.Status(strings.ToUpper(string(status))) == rules.StatusUnknown
+}
@@ -0,0 +1,90 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stackrox/sensor-metrics-analyzer/internal/rules"
+)
+
+func TestDiffReports(t *testing.T) {
+	t.Parallel()
+
+	before := rules.AnalysisReport{Results: []rules.EvaluationResult{
+		{RuleName: "queue", Status: rules.StatusRed, Value: 120},
+		{RuleName: "cache", Status: rules.StatusGreen, Value: 0.9},
+		{RuleName: "buffer", Status: rules.StatusYellow, Value: 50},
+		{RuleName: "legacy", Status: rules.StatusGreen},
+	}}
+	after := rules.AnalysisReport{Results: []rules.EvaluationResult{
+		{RuleName: "cache", Status: rules.StatusYellow, Value: 0.6},
+		{RuleName: "queue", Status: rules.StatusGreen, Value: 15},
+		{RuleName: "buffer", Status: rules.StatusYellow, Value: 60},
+		{RuleName: "latency", Status: rules.StatusYellow, Value: 2},
+	}}
+
+	diff := DiffReports(before, after)
+
+	tests := map[string]struct {
+		index      int
+		wantName   string
+		wantChange rules.ChangeKind
+		wantDelta  float64
+	}{
+		"should list regressions first":                {index: 0, wantName: "cache", wantChange: rules.ChangeRegressed, wantDelta: 0.6 - 0.9},
+		"should detect improvements":                   {index: 1, wantName: "queue", wantChange: rules.ChangeImproved, wantDelta: -105},
+		"should report results only in the second one": {index: 2, wantName: "latency", wantChange: rules.ChangeAdded},
+		"should report results only in the first one":  {index: 3, wantName: "legacy", wantChange: rules.ChangeRemoved},
+		"should keep value deltas of unchanged status": {index: 4, wantName: "buffer", wantChange: rules.ChangeUnchanged, wantDelta: 10},
+	}
+
+	assert.Len(t, diff.Results, len(tests))
+	assert.Equal(t, rules.DiffSummary{Regressed: 1, Improved: 1, Added: 1, Removed: 1, Unchanged: 1}, diff.Summary)
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			d := diff.Results[tt.index]
+			assert.Equal(t, tt.wantName, d.RuleName)
+			assert.Equal(t, tt.wantChange, d.Change)
+			assert.InDelta(t, tt.wantDelta, d.Delta(), 1e-9)
+		})
+	}
+}
+
+func TestDiffReportsDuplicateNames(t *testing.T) {
+	t.Parallel()
+
+	before := rules.AnalysisReport{Results: []rules.EvaluationResult{
+		{RuleName: "queue", Status: rules.StatusRed, Value: 1},
+		{RuleName: "queue", Status: rules.StatusGreen, Value: 2},
+	}}
+	after := rules.AnalysisReport{Results: []rules.EvaluationResult{
+		{RuleName: "queue", Status: rules.StatusRed, Value: 3},
+	}}
+
+	diff := DiffReports(before, after)
+	assert.Equal(t, rules.DiffSummary{Removed: 1, Unchanged: 1}, diff.Summary, "DiffReports() should pair duplicate names in order")
+	assert.Equal(t, 2.0, diff.Results[1].Delta(), "DiffReports() should pair the first occurrences")
+}
+
+func TestCompareStatus(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		before rules.Status
+		after  rules.Status
+		want   rules.ChangeKind
+	}{
+		"should regress when a rule stops evaluating": {before: rules.StatusGreen, after: rules.StatusUnknown, want: rules.ChangeRegressed},
+		"should improve when a rule evaluates GREEN":  {before: rules.StatusUnknown, after: rules.StatusGreen, want: rules.ChangeImproved},
+		"should regress when a rule evaluates RED":    {before: rules.StatusUnknown, after: rules.StatusRed, want: rules.ChangeRegressed},
+		"should keep unknown results unchanged":       {before: rules.StatusUnknown, after: rules.StatusUnknown, want: rules.ChangeUnchanged},
+		"should rank lower-case statuses":             {before: "yellow", after: rules.StatusRed, want: rules.ChangeRegressed},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, compareStatus(tt.before, tt.after))
+		})
+	}
+}
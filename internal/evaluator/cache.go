@@ -18,26 +18,35 @@ func EvaluateCacheHit(rule rules.Rule, metrics parser.MetricsData, loadLevel rul
 	}
 
 	if rule.CacheConfig == nil {
-		result.Message = "Cache config not specified"
-		return result
+		return unknownResult(result, rules.ReasonInvalidConfig, "Cache config not specified")
 	}
 
 	// Get hits metric
 	hitsMetric, exists := metrics.GetMetric(rule.CacheConfig.HitsMetric)
 	if !exists || len(hitsMetric.Values) == 0 {
-		result.Message = fmt.Sprintf("Hits metric %s not found", rule.CacheConfig.HitsMetric)
-		return result
+		return unknownResult(result, rules.ReasonMetricNotFound, fmt.Sprintf("Hits metric %s not found", rule.CacheConfig.HitsMetric))
 	}
 
 	// Get misses metric
 	missesMetric, exists := metrics.GetMetric(rule.CacheConfig.MissesMetric)
 	if !exists || len(missesMetric.Values) == 0 {
-		result.Message = fmt.Sprintf("Misses metric %s not found", rule.CacheConfig.MissesMetric)
-		return result
+		return unknownResult(result, rules.ReasonMetricNotFound, fmt.Sprintf("Misses metric %s not found", rule.CacheConfig.MissesMetric))
 	}
 
-	hits, _ := hitsMetric.GetSingleValue()
-	misses, _ := missesMetric.GetSingleValue()
+	hits, hitsMatched := selectSeriesValue(rule, hitsMetric)
+	if hitsMatched == 0 {
+		return missingSeries(result, rule, "Hits metric", rule.CacheConfig.HitsMetric)
+	}
+	misses, missesMatched := selectSeriesValue(rule, missesMetric)
+	if missesMatched == 0 {
+		return missingSeries(result, rule, "Misses metric", rule.CacheConfig.MissesMetric)
+	}
+	if detail := aggregationDetail(rule, rule.CacheConfig.HitsMetric, hitsMatched); detail != "" {
+		result.Details = append(result.Details, detail)
+	}
+	if detail := aggregationDetail(rule, rule.CacheConfig.MissesMetric, missesMatched); detail != "" {
+		result.Details = append(result.Details, detail)
+	}
 	total := hits + misses
 
 	if total == 0 {
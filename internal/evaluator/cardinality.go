@@ -0,0 +1,120 @@
+package evaluator
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/stackrox/sensor-metrics-analyzer/internal/parser"
+	"github.com/stackrox/sensor-metrics-analyzer/internal/rules"
+)
+
+const (
+	cardinalityYellowThresholdSeries = 1000
+	cardinalityRedThresholdSeries    = 10000
+
+	// reportedCardinalityLabels is how many labels with the most distinct values are listed
+	reportedCardinalityLabels = 5
+)
+
+// EvaluateCardinality grades the number of series of a metric family, or the
+// number of distinct values of one of its labels, against low/high. More is always worse.
+func EvaluateCardinality(rule rules.Rule, metrics parser.MetricsData, loadLevel rules.LoadLevel) rules.EvaluationResult {
+	result := rules.EvaluationResult{
+		RuleName:  rule.MetricName,
+		Status:    rules.StatusGreen,
+		Details:   []string{},
+		Timestamp: time.Now(),
+	}
+
+	fc, exists := metrics.CardinalityOf(rule.MetricName)
+	if !exists {
+		return unknownResult(result, rules.ReasonMetricNotFound, fmt.Sprintf("Metric %s not found", rule.MetricName))
+	}
+
+	value := float64(fc.Series)
+	if rule.CardinalityConfig != nil && rule.CardinalityConfig.Label != "" {
+		label := rule.CardinalityConfig.Label
+		distinct, hasLabel := fc.LabelValues[label]
+		if !hasLabel {
+			return unknownResult(result, rules.ReasonSeriesNotFound, fmt.Sprintf("No series of %s has the label %s", rule.MetricName, label))
+		}
+		value = float64(distinct)
+		result.Details = append(result.Details, fmt.Sprintf("status based on: distinct values of %s", label))
+	}
+	result.Value = value
+	result.Details = append(result.Details, cardinalityDetails(fc)...)
+
+	thresholds := selectThresholds(rule, loadLevel)
+	thresholds.HigherIsWorse = true
+
+	result.Status = gaugeStatus(thresholds, value)
+	result.Message = statusMessage(rule.Messages, result.Status, value, cardinalityExtras(fc))
+	return result
+}
+
+// EvaluateSeriesCardinality flags metric families with more series than
+// Prometheus handles comfortably. This is a general rule that applies to every
+// metric, emitting one result per offending family, worst first, and none when
+// every family is small.
+func EvaluateSeriesCardinality(metrics parser.MetricsData) []rules.EvaluationResult {
+	var results []rules.EvaluationResult
+	for _, fc := range metrics.Cardinality() {
+		if fc.Series <= cardinalityYellowThresholdSeries {
+			// Families are sorted by series count, the rest are smaller
+			break
+		}
+		status := rules.StatusYellow
+		if fc.Series > cardinalityRedThresholdSeries {
+			status = rules.StatusRed
+		}
+		result := rules.EvaluationResult{
+			RuleName:     fc.Family + " (cardinality check)",
+			Status:       status,
+			Value:        float64(fc.Series),
+			MetricHelp:   resolveMetricHelp(fc.Family, metrics),
+			Details:      cardinalityDetails(fc),
+			Timestamp:    time.Now(),
+			ReviewStatus: "Automatically generated rule; reviewed by the code author at the time of implementation.",
+			PotentialActionUser: "Check whether the Prometheus scraping Sensor struggles with memory; " +
+				"consider dropping the offending labels with metric_relabel_configs.",
+			PotentialActionDeveloper: "Review the metric's labels: values such as IDs, names or paths grow with the cluster " +
+				"and should be aggregated or removed.",
+		}
+		result.Message = fmt.Sprintf("%s series%s. High cardinality increases the memory use of Prometheus and of Sensor itself.",
+			formatHumanInteger(float64(fc.Series)), worstLabelSuffix(fc))
+		results = append(results, result)
+	}
+	return results
+}
+
+// cardinalityDetails lists the series count and the labels with the most distinct values
+func cardinalityDetails(fc parser.FamilyCardinality) []string {
+	details := []string{fmt.Sprintf("series: %s", formatHumanInteger(float64(fc.Series)))}
+	for _, label := range fc.TopLabels(reportedCardinalityLabels) {
+		details = append(details, fmt.Sprintf("label %s: %s distinct values", label, formatHumanInteger(float64(fc.LabelValues[label]))))
+	}
+	return details
+}
+
+// cardinalityExtras are the message placeholders of cardinality rules
+func cardinalityExtras(fc parser.FamilyCardinality) map[string]interface{} {
+	extras := map[string]interface{}{
+		"series":           fc.Series,
+		"top_label":        "",
+		"top_label_values": 0,
+	}
+	if top := fc.TopLabels(1); len(top) > 0 {
+		extras["top_label"] = top[0]
+		extras["top_label_values"] = fc.LabelValues[top[0]]
+	}
+	return extras
+}
+
+// worstLabelSuffix names the label with the most distinct values, if any
+func worstLabelSuffix(fc parser.FamilyCardinality) string {
+	top := fc.TopLabels(1)
+	if len(top) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(", driven by label %s with %s distinct values", top[0], formatHumanInteger(float64(fc.LabelValues[top[0]])))
+}
@@ -0,0 +1,120 @@
+package evaluator
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stackrox/sensor-metrics-analyzer/internal/parser"
+	"github.com/stackrox/sensor-metrics-analyzer/internal/rules"
+)
+
+// podMetric builds a metric with one series per pod and namespace
+func podMetric(name string, pods, namespaces int) *parser.Metric {
+	metric := &parser.Metric{Name: name, Type: "gauge"}
+	for p := 0; p < pods; p++ {
+		for n := 0; n < namespaces; n++ {
+			metric.Values = append(metric.Values, parser.MetricValue{
+				Labels: map[string]string{"pod": fmt.Sprintf("pod-%d", p), "namespace": fmt.Sprintf("ns-%d", n)},
+				Value:  1,
+			})
+		}
+	}
+	return metric
+}
+
+func TestEvaluateCardinality(t *testing.T) {
+	metrics := parser.MetricsData{
+		"pod_info": podMetric("pod_info", 60, 2),
+	}
+
+	tests := map[string]struct {
+		metric     string
+		label      string
+		wantValue  float64
+		wantStatus rules.Status
+		wantReason rules.UnknownReason
+	}{
+		"should grade the number of series": {
+			metric:     "pod_info",
+			wantValue:  120,
+			wantStatus: rules.StatusRed,
+		},
+		"should grade the distinct values of a label": {
+			metric:     "pod_info",
+			label:      "pod",
+			wantValue:  60,
+			wantStatus: rules.StatusYellow,
+		},
+		"should be unknown for a label no series has": {
+			metric:     "pod_info",
+			label:      "container",
+			wantStatus: rules.StatusUnknown,
+			wantReason: rules.ReasonSeriesNotFound,
+		},
+		"should be unknown for a missing metric": {
+			metric:     "missing_info",
+			wantStatus: rules.StatusUnknown,
+			wantReason: rules.ReasonMetricNotFound,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			rule := rules.Rule{
+				RuleType:          rules.RuleTypeCardinality,
+				MetricName:        tt.metric,
+				CardinalityConfig: &rules.CardinalityConfig{Label: tt.label},
+				Thresholds:        rules.Thresholds{Low: 50, High: 100},
+				Messages:          rules.Messages{Red: "{series} series, {top_label} has {top_label_values} values"},
+			}
+			result := EvaluateCardinality(rule, metrics, rules.LoadLevelMedium)
+			if result.Status != tt.wantStatus || result.Reason != tt.wantReason {
+				t.Fatalf("EvaluateCardinality() = %v/%v, want %v/%v (%s)", result.Status, result.Reason, tt.wantStatus, tt.wantReason, result.Message)
+			}
+			if tt.wantStatus == rules.StatusUnknown {
+				return
+			}
+			if result.Value != tt.wantValue {
+				t.Errorf("EvaluateCardinality() value = %g, want %g", result.Value, tt.wantValue)
+			}
+			if tt.wantStatus == rules.StatusRed && result.Message != "120 series, pod has 60 values" {
+				t.Errorf("EvaluateCardinality() message = %q", result.Message)
+			}
+		})
+	}
+}
+
+func TestEvaluateSeriesCardinality(t *testing.T) {
+	metrics := parser.MetricsData{
+		"huge_info":  podMetric("huge_info", 5001, 3),
+		"large_info": podMetric("large_info", 1001, 1),
+		"small_info": podMetric("small_info", 10, 1),
+	}
+
+	results := EvaluateSeriesCardinality(metrics)
+	if len(results) != 2 {
+		t.Fatalf("EvaluateSeriesCardinality() got %d results, want 2", len(results))
+	}
+	want := []struct {
+		name   string
+		status rules.Status
+		value  float64
+	}{
+		{"huge_info (cardinality check)", rules.StatusRed, 15003},
+		{"large_info (cardinality check)", rules.StatusYellow, 1001},
+	}
+	for i, w := range want {
+		r := results[i]
+		if r.RuleName != w.name || r.Status != w.status || r.Value != w.value {
+			t.Errorf("EvaluateSeriesCardinality()[%d] = %s %v %g, want %s %v %g", i, r.RuleName, r.Status, r.Value, w.name, w.status, w.value)
+		}
+	}
+	if !strings.Contains(results[0].Message, "label pod with 5 001 distinct values") {
+		t.Errorf("EvaluateSeriesCardinality() message = %q, want it to name the pod label", results[0].Message)
+	}
+
+	if results := EvaluateSeriesCardinality(parser.MetricsData{"small_info": podMetric("small_info", 10, 1)}); len(results) != 0 {
+		t.Errorf("EvaluateSeriesCardinality() got %d results for small metrics, want none", len(results))
+	}
+}
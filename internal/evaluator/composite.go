@@ -19,8 +19,7 @@ func EvaluateComposite(rule rules.Rule, metrics parser.MetricsData, loadLevel ru
 	}
 
 	if rule.CompositeConfig == nil {
-		result.Message = "Composite config not specified"
-		return result
+		return unknownResult(result, rules.ReasonInvalidConfig, "Composite config not specified")
 	}
 
 	// Collect metric values
@@ -28,8 +27,7 @@ func EvaluateComposite(rule rules.Rule, metrics parser.MetricsData, loadLevel ru
 	for _, metricDef := range rule.CompositeConfig.Metrics {
 		metric, exists := metrics.GetMetric(metricDef.Source)
 		if !exists || len(metric.Values) == 0 {
-			result.Message = fmt.Sprintf("Metric %s not found", metricDef.Source)
-			return result
+			return unknownResult(result, rules.ReasonMetricNotFound, fmt.Sprintf("Metric %s not found", metricDef.Source))
 		}
 		value, _ := metric.GetSingleValue()
 		metricValues[metricDef.Name] = value
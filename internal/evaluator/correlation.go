@@ -7,7 +7,8 @@ import (
 
 // EvaluateCorrelation evaluates correlation conditions and modifies status if needed
 func EvaluateCorrelation(rule rules.Rule, metrics parser.MetricsData, result rules.EvaluationResult) rules.EvaluationResult {
-	if rule.Correlation == nil {
+	// An unknown result has no status to adjust
+	if rule.Correlation == nil || result.Status == rules.StatusUnknown {
 		return result
 	}
 
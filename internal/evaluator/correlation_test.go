@@ -160,7 +160,11 @@ func TestEvaluateHistogram(t *testing.T) {
 
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
-			result := EvaluateHistogram(tt.rule, tt.metrics, tt.loadLevel)
+			results := EvaluateHistogram(tt.rule, tt.metrics, tt.loadLevel)
+			if len(results) != 1 {
+				t.Fatalf("EvaluateHistogram() got %d results, want 1", len(results))
+			}
+			result := results[0]
 
 			if result.Status != tt.wantStatus {
 				t.Errorf("EvaluateHistogram() status = %v, want %v", result.Status, tt.wantStatus)
@@ -0,0 +1,29 @@
+package evaluator
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/stackrox/sensor-metrics-analyzer/internal/parser"
+	"github.com/stackrox/sensor-metrics-analyzer/internal/rules"
+)
+
+// averageCounters returns the metrics a rule with counter_average is evaluated
+// against: every counter averaged over the window, in the rule's unit, and a
+// detail naming the window. ok is false when the window is unknown.
+func averageCounters(rule rules.Rule, metrics parser.MetricsData, window rules.CounterWindow) (averaged parser.MetricsData, detail string, ok bool) {
+	unit, unitSeconds := "second", 1.0
+	if rule.CounterAverage == rules.CounterAveragePerHour {
+		unit, unitSeconds = "hour", time.Hour.Seconds()
+	}
+
+	switch {
+	case window.RateInterval > 0:
+		// Counters already are per-second rates over the interval
+		return metrics.ScaleCounters(unitSeconds), fmt.Sprintf("counters: average per %s over the %s rate interval", unit, window.RateInterval), true
+	case window.Uptime > 0:
+		return metrics.ScaleCounters(unitSeconds / window.Uptime.Seconds()),
+			fmt.Sprintf("counters: average per %s over %s of uptime", unit, window.Uptime.Round(time.Second)), true
+	}
+	return metrics, "", false
+}
@@ -10,22 +10,8 @@ import (
 
 // selectThresholds selects appropriate thresholds based on load level
 func selectThresholds(rule rules.Rule, loadLevel rules.LoadLevel) rules.Thresholds {
-	// If load level thresholds are not configured, use default thresholds
-	if rule.LoadLevelThresholds == nil {
-		return rule.Thresholds
-	}
-
-	var selected *rules.Thresholds
-	switch loadLevel {
-	case rules.LoadLevelLow:
-		selected = rule.LoadLevelThresholds.Low
-	case rules.LoadLevelMedium:
-		selected = rule.LoadLevelThresholds.Medium
-	case rules.LoadLevelHigh:
-		selected = rule.LoadLevelThresholds.High
-	}
-
-	// If no threshold for this load level, fall back to default
+	// Without thresholds for this load level, fall back to default thresholds
+	selected := rule.LoadLevelThresholds[loadLevel]
 	if selected == nil {
 		return rule.Thresholds
 	}
@@ -54,6 +40,14 @@ func selectThresholds(rule rules.Rule, loadLevel rules.LoadLevel) rules.Threshol
 	return result
 }
 
+// unknownResult marks a result as UNKNOWN: the rule could not be evaluated for reason
+func unknownResult(result rules.EvaluationResult, reason rules.UnknownReason, message string) rules.EvaluationResult {
+	result.Status = rules.StatusUnknown
+	result.Reason = reason
+	result.Message = message
+	return result
+}
+
 // getRemediation returns the remediation message for a given status
 func getRemediation(rule rules.Rule, status rules.Status) string {
 	if rule.Remediation == nil {
@@ -97,36 +91,68 @@ func applyReviewMetadata(rule rules.Rule) string {
 	return "review status unavailable"
 }
 
-// EvaluateAllRules evaluates all rules against metrics
-func EvaluateAllRules(rulesList []rules.Rule, metrics parser.MetricsData, loadLevel rules.LoadLevel, acsVersion string) rules.AnalysisReport {
-	report := rules.AnalysisReport{
-		ACSVersion: acsVersion,
-		LoadLevel:  loadLevel,
-		Timestamp:  time.Now(),
+// evaluateSeriesRule evaluates a rule that supports series selection, emitting one
+// result per label set for per_series aggregation and a single result otherwise
+func evaluateSeriesRule(rule rules.Rule, metrics parser.MetricsData, loadLevel rules.LoadLevel, evaluate ruleEvaluator) []rules.EvaluationResult {
+	if rule.Aggregation == rules.AggregationPerSeries {
+		return evaluatePerSeries(rule, metrics, loadLevel, evaluate)
 	}
+	return []rules.EvaluationResult{evaluate(rule, metrics, loadLevel)}
+}
 
-	// Filter rules by ACS version
-	filteredRules := FilterRulesByVersion(rulesList, acsVersion)
+// EvaluateRule evaluates a single rule against metrics, applying counter averaging,
+// correlation, review metadata and potential actions. ACS version constraints are
+// not checked. window is only used by rules with counter_average. A rule whose
+// metric_name is a pattern is evaluated once per matching metric.
+func EvaluateRule(rule rules.Rule, metrics parser.MetricsData, loadLevel rules.LoadLevel, window rules.CounterWindow) []rules.EvaluationResult {
+	if rules.IsMetricPattern(rule.MetricName) {
+		return evaluatePattern(rule, metrics, loadLevel, window)
+	}
 
-	for _, rule := range filteredRules {
-		var result rules.EvaluationResult
-
-		// Evaluate based on rule type
-		switch rule.RuleType {
-		case rules.RuleTypeGauge:
-			result = EvaluateGauge(rule, metrics, loadLevel)
-		case rules.RuleTypePercentage:
-			result = EvaluatePercentage(rule, metrics, loadLevel)
-		case rules.RuleTypeQueue:
-			result = EvaluateQueue(rule, metrics, loadLevel)
-		case rules.RuleTypeHistogram:
-			result = EvaluateHistogram(rule, metrics, loadLevel)
-		case rules.RuleTypeCacheHit:
-			result = EvaluateCacheHit(rule, metrics, loadLevel)
-		case rules.RuleTypeComposite:
-			result = EvaluateComposite(rule, metrics, loadLevel)
-		default:
-			continue // Skip unknown rule types
+	var results []rules.EvaluationResult
+
+	// Counter averaging replaces the counters the rule reads; correlation conditions
+	// are still checked against the scraped values
+	evaluated := metrics
+	averagedOver, averaged := "", true
+	if rule.CounterAverage != "" {
+		evaluated, averagedOver, averaged = averageCounters(rule, metrics, window)
+	}
+
+	// Evaluate based on rule type
+	switch rule.RuleType {
+	case rules.RuleTypeGauge:
+		results = evaluateSeriesRule(rule, evaluated, loadLevel, EvaluateGauge)
+	case rules.RuleTypePercentage:
+		results = evaluateSeriesRule(rule, evaluated, loadLevel, EvaluatePercentage)
+	case rules.RuleTypeQueue:
+		results = []rules.EvaluationResult{EvaluateQueue(rule, evaluated, loadLevel)}
+	case rules.RuleTypeHistogram:
+		results = EvaluateHistogram(rule, evaluated, loadLevel)
+	case rules.RuleTypeHistogramInfOverflow:
+		results = EvaluateHistogramInfOverflow(rule, evaluated, loadLevel)
+	case rules.RuleTypeSummary:
+		results = EvaluateSummary(rule, evaluated, loadLevel)
+	case rules.RuleTypeCacheHit:
+		results = evaluateSeriesRule(rule, evaluated, loadLevel, EvaluateCacheHit)
+	case rules.RuleTypeComposite:
+		results = []rules.EvaluationResult{EvaluateComposite(rule, evaluated, loadLevel)}
+	case rules.RuleTypeExpression:
+		results = EvaluateExpression(rule, evaluated, loadLevel)
+	case rules.RuleTypeCardinality:
+		results = []rules.EvaluationResult{EvaluateCardinality(rule, evaluated, loadLevel)}
+	default:
+		return nil // Skip unknown rule types
+	}
+
+	for i, result := range results {
+		if result.Status != rules.StatusUnknown {
+			if !averaged {
+				result = unknownResult(rules.EvaluationResult{RuleName: result.RuleName, Details: []string{}}, rules.ReasonUptimeUnknown,
+					"Sensor uptime is unknown (no process_start_time_seconds or scrape time), cannot average counters")
+			} else if averagedOver != "" {
+				result.Details = append(result.Details, averagedOver)
+			}
 		}
 
 		// Apply correlation if configured
@@ -136,41 +162,60 @@ func EvaluateAllRules(rulesList []rules.Rule, metrics parser.MetricsData, loadLe
 
 		result.ReviewStatus = applyReviewMetadata(rule)
 
-		// Add potential actions (user-facing)
+		// Add potential actions (user-facing); the rule's remediation replaces
+		// actions suggested by the evaluator
 		result.Remediation = getRemediation(rule, result.Status)
-		result.PotentialActionUser = result.Remediation
+		if result.Remediation != "" {
+			result.PotentialActionUser = result.Remediation
+		}
 		result.Timestamp = time.Now()
 
-		report.Results = append(report.Results, result)
+		results[i] = result
+	}
+	return results
+}
 
-		// Update summary
-		switch result.Status {
-		case rules.StatusRed:
-			report.Summary.RedCount++
-		case rules.StatusYellow:
-			report.Summary.YellowCount++
-		case rules.StatusGreen:
-			report.Summary.GreenCount++
-		}
+// EvaluateAllRules evaluates all rules against metrics. window is the time the
+// counters accumulated over, for rules with counter_average.
+func EvaluateAllRules(rulesList []rules.Rule, metrics parser.MetricsData, loadLevel rules.LoadLevel, acsVersion string, window rules.CounterWindow) rules.AnalysisReport {
+	report := rules.AnalysisReport{
+		ACSVersion: acsVersion,
+		LoadLevel:  loadLevel,
+		Uptime:     window.Uptime,
+		Timestamp:  time.Now(),
 	}
 
-	// Apply general histogram +Inf overflow rule to all histogram metrics
-	infOverflowResults := EvaluateHistogramInfOverflow(metrics)
-	for _, result := range infOverflowResults {
-		report.Results = append(report.Results, result)
+	// Filter rules by ACS version
+	filteredRules := FilterRulesByVersion(rulesList, acsVersion)
 
-		// Update summary
-		switch result.Status {
-		case rules.StatusRed:
-			report.Summary.RedCount++
-		case rules.StatusYellow:
-			report.Summary.YellowCount++
-		case rules.StatusGreen:
-			report.Summary.GreenCount++
+	for _, rule := range filteredRules {
+		for _, result := range EvaluateRule(rule, metrics, loadLevel, window) {
+			report.Results = append(report.Results, result)
+			countStatus(&report.Summary, result.Status)
 		}
 	}
 
+	// Apply general series cardinality rule to all metric families
+	for _, result := range EvaluateSeriesCardinality(metrics) {
+		report.Results = append(report.Results, result)
+		countStatus(&report.Summary, result.Status)
+	}
+
 	report.Summary.TotalAnalyzed = len(report.Results)
 
 	return report
 }
+
+// countStatus adds a result's status to the summary counts
+func countStatus(summary *rules.Summary, status rules.Status) {
+	switch status {
+	case rules.StatusRed:
+		summary.RedCount++
+	case rules.StatusYellow:
+		summary.YellowCount++
+	case rules.StatusGreen:
+		summary.GreenCount++
+	case rules.StatusUnknown:
+		summary.UnknownCount++
+	}
+}
@@ -97,8 +97,8 @@ func TestEvaluateGauge(t *testing.T) {
 					High:          100,
 					HigherIsWorse: true,
 				},
-				LoadLevelThresholds: &rules.LoadLevelThresholds{
-					High: &rules.Thresholds{
+				LoadLevelThresholds: rules.LoadLevelThresholds{
+					rules.LoadLevelHigh: &rules.Thresholds{
 						Low:           100,
 						High:          200,
 						HigherIsWorse: true,
@@ -144,6 +144,16 @@ func TestEvaluateGauge(t *testing.T) {
 			loadLevel:  rules.LoadLevelMedium,
 			wantStatus: rules.StatusGreen,
 		},
+		"should return unknown status when metric is missing": {
+			rule: rules.Rule{
+				RuleType:   rules.RuleTypeGauge,
+				MetricName: "test_metric",
+				Thresholds: rules.Thresholds{Low: 50, High: 100, HigherIsWorse: true},
+			},
+			metrics:    parser.MetricsData{},
+			loadLevel:  rules.LoadLevelMedium,
+			wantStatus: rules.StatusUnknown,
+		},
 	}
 
 	for name, tt := range tests {
@@ -405,6 +415,16 @@ func TestIsRuleApplicable(t *testing.T) {
 	}
 }
 
+// evaluateInfOverflow evaluates a +Inf overflow rule on every histogram
+func evaluateInfOverflow(metrics parser.MetricsData, low, high float64) []rules.EvaluationResult {
+	rule := rules.Rule{
+		RuleType:   rules.RuleTypeHistogramInfOverflow,
+		MetricName: "*",
+		Thresholds: rules.Thresholds{Low: low, High: high},
+	}
+	return EvaluateRule(rule, metrics, rules.LoadLevelMedium, rules.CounterWindow{})
+}
+
 func TestEvaluateHistogramInfOverflow(t *testing.T) {
 	t.Run("splits red and yellow by label set with label-aware titles", func(t *testing.T) {
 		metrics := parser.MetricsData{
@@ -426,7 +446,7 @@ func TestEvaluateHistogramInfOverflow(t *testing.T) {
 			},
 		}
 
-		results := EvaluateHistogramInfOverflow(metrics)
+		results := evaluateInfOverflow(metrics, 25, 50)
 		if len(results) != 2 {
 			t.Fatalf("expected 2 problematic series results, got %d", len(results))
 		}
@@ -474,7 +494,7 @@ func TestEvaluateHistogramInfOverflow(t *testing.T) {
 			},
 		}
 
-		results := EvaluateHistogramInfOverflow(metrics)
+		results := evaluateInfOverflow(metrics, 25, 50)
 		if len(results) != 1 {
 			t.Fatalf("expected 1 green summary result, got %d", len(results))
 		}
@@ -503,7 +523,7 @@ func TestEvaluateHistogramInfOverflow(t *testing.T) {
 			},
 		}
 
-		results := EvaluateHistogramInfOverflow(metrics)
+		results := evaluateInfOverflow(metrics, 25, 50)
 		if len(results) != 1 {
 			t.Fatalf("expected 1 result, got %d", len(results))
 		}
@@ -524,7 +544,7 @@ func TestEvaluateHistogramInfOverflow(t *testing.T) {
 			},
 		}
 
-		results := EvaluateHistogramInfOverflow(metrics)
+		results := evaluateInfOverflow(metrics, 25, 50)
 		if len(results) != 1 {
 			t.Fatalf("expected 1 result, got %d", len(results))
 		}
@@ -549,7 +569,7 @@ func TestEvaluateHistogramInfOverflow(t *testing.T) {
 			},
 		}
 
-		results := EvaluateHistogramInfOverflow(metrics)
+		results := evaluateInfOverflow(metrics, 25, 50)
 		if len(results) != 0 {
 			t.Fatalf("expected 0 results, got %d", len(results))
 		}
@@ -567,7 +587,7 @@ func TestEvaluateHistogramInfOverflow(t *testing.T) {
 			},
 		}
 
-		results := EvaluateHistogramInfOverflow(metrics)
+		results := evaluateInfOverflow(metrics, 25, 50)
 		if len(results) != 1 {
 			t.Fatalf("expected 1 result, got %d", len(results))
 		}
@@ -596,7 +616,7 @@ func TestEvaluateHistogramInfOverflow(t *testing.T) {
 				},
 			},
 		}
-		results := EvaluateHistogramInfOverflow(metrics)
+		results := evaluateInfOverflow(metrics, 25, 50)
 		if len(results) != 1 {
 			t.Fatalf("expected 1 result, got %d", len(results))
 		}
@@ -621,7 +641,7 @@ func TestEvaluateHistogramInfOverflow(t *testing.T) {
 				},
 			},
 		}
-		results := EvaluateHistogramInfOverflow(metrics)
+		results := evaluateInfOverflow(metrics, 25, 50)
 		if len(results) != 1 {
 			t.Fatalf("expected 1 result, got %d", len(results))
 		}
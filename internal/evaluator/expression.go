@@ -0,0 +1,78 @@
+package evaluator
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/stackrox/sensor-metrics-analyzer/internal/expr"
+	"github.com/stackrox/sensor-metrics-analyzer/internal/parser"
+	"github.com/stackrox/sensor-metrics-analyzer/internal/rules"
+)
+
+// EvaluateExpression evaluates an expression rule. A scalar or single-series
+// result produces one result; a vector produces one result per series.
+// Values are graded with the same thresholds as gauge rules.
+func EvaluateExpression(rule rules.Rule, metrics parser.MetricsData, loadLevel rules.LoadLevel) []rules.EvaluationResult {
+	newResult := func() rules.EvaluationResult {
+		name := rule.DisplayName
+		if name == "" && rule.ExpressionConfig != nil {
+			name = rule.ExpressionConfig.Expression
+		}
+		return rules.EvaluationResult{
+			RuleName:  name,
+			Status:    rules.StatusGreen,
+			Details:   []string{},
+			Timestamp: time.Now(),
+		}
+	}
+
+	if rule.ExpressionConfig == nil {
+		return []rules.EvaluationResult{unknownResult(newResult(), rules.ReasonInvalidConfig, "Expression config not specified")}
+	}
+
+	expression, err := expr.Parse(rule.ExpressionConfig.Expression)
+	if err != nil {
+		return []rules.EvaluationResult{unknownResult(newResult(), rules.ReasonInvalidConfig, fmt.Sprintf("Invalid expression: %v", err))}
+	}
+
+	value, err := expression.Eval(metrics)
+	if err != nil {
+		return []rules.EvaluationResult{unknownResult(newResult(), rules.ReasonEvaluationError, fmt.Sprintf("Expression evaluation failed: %v", err))}
+	}
+
+	if len(value.Samples) == 0 {
+		result := unknownResult(newResult(), rules.ReasonNoData, "Expression returned no data")
+		result.Details = append(result.Details, "expression: "+expression.String())
+		return []rules.EvaluationResult{result}
+	}
+
+	thresholds := selectThresholds(rule, loadLevel)
+	results := make([]rules.EvaluationResult, 0, len(value.Samples))
+	for _, sample := range value.Samples {
+		result := newResult()
+		if len(value.Samples) > 1 {
+			result.RuleName += formatLabelSet(sample.Labels)
+		}
+		result.Details = append(result.Details, "expression: "+expression.String())
+
+		if math.IsNaN(sample.Value) {
+			results = append(results, unknownResult(result, rules.ReasonEvaluationError,
+				"Expression value is not a number (for example, a division by zero)"))
+			continue
+		}
+
+		extras := map[string]interface{}{
+			"value_human": formatHumanNumberGauge(sample.Value),
+		}
+		for key, labelValue := range sample.Labels {
+			extras[key] = labelValue
+		}
+
+		result.Value = sample.Value
+		result.Status = gaugeStatus(thresholds, sample.Value)
+		result.Message = statusMessage(rule.Messages, result.Status, sample.Value, extras)
+		results = append(results, result)
+	}
+	return results
+}
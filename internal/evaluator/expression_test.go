@@ -0,0 +1,90 @@
+package evaluator
+
+import (
+	"testing"
+
+	"github.com/stackrox/sensor-metrics-analyzer/internal/parser"
+	"github.com/stackrox/sensor-metrics-analyzer/internal/rules"
+)
+
+func TestEvaluateExpression(t *testing.T) {
+	metrics := parser.MetricsData{
+		"queue_size": componentMetric("queue_size", map[string]float64{
+			"enricher": 300,
+			"detector": 50,
+		}),
+		"received_total": componentMetric("received_total", map[string]float64{
+			"enricher": 0,
+			"detector": 100,
+		}),
+	}
+
+	tests := map[string]struct {
+		expression  string
+		wantResults map[string]rules.Status
+		wantValues  map[string]float64
+		wantMessage string
+	}{
+		"should grade a scalar aggregation as one result": {
+			expression:  "sum(queue_size)",
+			wantResults: map[string]rules.Status{"Queues": rules.StatusRed},
+			wantValues:  map[string]float64{"Queues": 350},
+			wantMessage: "total 350",
+		},
+		"should grade each series of a vector": {
+			expression: "max by (component) (queue_size)",
+			wantResults: map[string]rules.Status{
+				`Queues{component="detector"}`: rules.StatusGreen,
+				`Queues{component="enricher"}`: rules.StatusRed,
+			},
+			wantValues: map[string]float64{
+				`Queues{component="detector"}`: 50,
+				`Queues{component="enricher"}`: 300,
+			},
+		},
+		"should report NaN values as unknown": {
+			expression:  `received_total{component="enricher"} / received_total`,
+			wantResults: map[string]rules.Status{"Queues": rules.StatusUnknown},
+			wantValues:  map[string]float64{"Queues": 0},
+		},
+		"should report empty results as unknown": {
+			expression:  "missing_metric",
+			wantResults: map[string]rules.Status{"Queues": rules.StatusUnknown},
+			wantValues:  map[string]float64{"Queues": 0},
+			wantMessage: "Expression returned no data",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			rule := rules.Rule{
+				RuleType:         rules.RuleTypeExpression,
+				DisplayName:      "Queues",
+				ExpressionConfig: &rules.ExpressionConfig{Expression: tt.expression},
+				Thresholds:       rules.Thresholds{Low: 100, High: 200, HigherIsWorse: true},
+				Messages:         rules.Messages{Green: "total {value}", Red: "total {value}"},
+			}
+
+			results := EvaluateExpression(rule, metrics, rules.LoadLevelMedium)
+			if len(results) != len(tt.wantResults) {
+				t.Fatalf("EvaluateExpression() got %d results, want %d", len(results), len(tt.wantResults))
+			}
+			for _, result := range results {
+				wantStatus, ok := tt.wantResults[result.RuleName]
+				if !ok {
+					t.Errorf("EvaluateExpression() unexpected result %q", result.RuleName)
+					continue
+				}
+				if result.Status != wantStatus {
+					t.Errorf("EvaluateExpression() %s status = %v, want %v", result.RuleName, result.Status, wantStatus)
+				}
+				if result.Value != tt.wantValues[result.RuleName] {
+					t.Errorf("EvaluateExpression() %s value = %v, want %v", result.RuleName, result.Value, tt.wantValues[result.RuleName])
+				}
+				if tt.wantMessage != "" && result.Message != tt.wantMessage {
+					t.Errorf("EvaluateExpression() message = %q, want %q", result.Message, tt.wantMessage)
+				}
+			}
+		})
+	}
+}
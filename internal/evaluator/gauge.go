@@ -21,18 +21,22 @@ func EvaluateGauge(rule rules.Rule, metrics parser.MetricsData, loadLevel rules.
 	}
 
 	if rule.MetricName == "" {
-		result.Message = "Metric name not specified"
-		return result
+		return unknownResult(result, rules.ReasonInvalidConfig, "Metric name not specified")
 	}
 
 	metric, exists := metrics.GetMetric(rule.MetricName)
 	if !exists || len(metric.Values) == 0 {
-		result.Message = fmt.Sprintf("Metric %s not found", rule.MetricName)
-		return result
+		return unknownResult(result, rules.ReasonMetricNotFound, fmt.Sprintf("Metric %s not found", rule.MetricName))
 	}
 
-	value, _ := metric.GetSingleValue()
+	value, matched := selectSeriesValue(rule, metric)
+	if matched == 0 {
+		return missingSeries(result, rule, "Metric", rule.MetricName)
+	}
 	result.Value = value
+	if detail := aggregationDetail(rule, rule.MetricName, matched); detail != "" {
+		result.Details = append(result.Details, detail)
+	}
 
 	// Select thresholds based on load level
 	thresholds := selectThresholds(rule, loadLevel)
@@ -41,45 +45,51 @@ func EvaluateGauge(rule rules.Rule, metrics parser.MetricsData, loadLevel rules.
 		"value_human": formatHumanNumberGauge(value),
 	}
 
-	// Evaluate thresholds
+	result.Status = gaugeStatus(thresholds, value)
+	result.Message = statusMessage(rule.Messages, result.Status, value, extras)
+
+	return result
+}
+
+// gaugeStatus grades a value against gauge-style thresholds
+func gaugeStatus(thresholds rules.Thresholds, value float64) rules.Status {
 	if thresholds.HigherIsWorse {
 		if value < thresholds.Low {
-			result.Status = rules.StatusGreen
-			result.Message = interpolate(rule.Messages.Green, value, extras)
+			return rules.StatusGreen
 		} else if value < thresholds.High {
-			result.Status = rules.StatusYellow
-			result.Message = interpolate(rule.Messages.Yellow, value, extras)
-		} else {
-			result.Status = rules.StatusRed
-			result.Message = interpolate(rule.Messages.Red, value, extras)
+			return rules.StatusYellow
 		}
-	} else {
-		// Lower is worse (inverted) - special case for zero checks
-		if thresholds.Low == 0 && thresholds.High == 0 {
-			// Zero check: > 0 is good, == 0 is bad
-			if value > 0 {
-				result.Status = rules.StatusGreen
-				result.Message = interpolate(rule.Messages.Green, value, extras)
-			} else {
-				result.Status = rules.StatusRed
-				result.Message = interpolate(rule.Messages.Red, value, extras)
-			}
-		} else {
-			// Normal inverted logic
-			if value >= thresholds.High {
-				result.Status = rules.StatusGreen
-				result.Message = interpolate(rule.Messages.Green, value, extras)
-			} else if value >= thresholds.Low {
-				result.Status = rules.StatusYellow
-				result.Message = interpolate(rule.Messages.Yellow, value, extras)
-			} else {
-				result.Status = rules.StatusRed
-				result.Message = interpolate(rule.Messages.Red, value, extras)
-			}
+		return rules.StatusRed
+	}
+
+	// Lower is worse (inverted) - special case for zero checks
+	if thresholds.Low == 0 && thresholds.High == 0 {
+		// Zero check: > 0 is good, == 0 is bad
+		if value > 0 {
+			return rules.StatusGreen
 		}
+		return rules.StatusRed
 	}
 
-	return result
+	// Normal inverted logic
+	if value >= thresholds.High {
+		return rules.StatusGreen
+	} else if value >= thresholds.Low {
+		return rules.StatusYellow
+	}
+	return rules.StatusRed
+}
+
+// statusMessage interpolates the message template for status
+func statusMessage(messages rules.Messages, status rules.Status, value float64, extras map[string]interface{}) string {
+	switch status {
+	case rules.StatusRed:
+		return interpolate(messages.Red, value, extras)
+	case rules.StatusYellow:
+		return interpolate(messages.Yellow, value, extras)
+	default:
+		return interpolate(messages.Green, value, extras)
+	}
 }
 
 // interpolate replaces placeholders in template with actual values
@@ -12,138 +12,274 @@ import (
 	"github.com/stackrox/sensor-metrics-analyzer/internal/rules"
 )
 
-const (
-	infOverflowYellowThresholdPercent = 25.0
-	infOverflowRedThresholdPercent    = 50.0
-)
+// reportedQuantiles are always listed in histogram result details
+var reportedQuantiles = []struct {
+	name     string
+	quantile float64
+}{
+	{"p50", 0.50},
+	{"p75", 0.75},
+	{"p95", 0.95},
+	{"p99", 0.99},
+}
+
+// histogramSeries holds the buckets of one label set of a histogram (all labels except "le")
+type histogramSeries struct {
+	key      string
+	labels   map[string]string
+	buckets  []parser.HistogramBucket // Finite buckets sorted by le
+	infCount float64
+	hasInf   bool
+}
 
-// EvaluateHistogram evaluates a histogram rule
-func EvaluateHistogram(rule rules.Rule, metrics parser.MetricsData, loadLevel rules.LoadLevel) rules.EvaluationResult {
-	result := rules.EvaluationResult{
-		RuleName:  rule.MetricName,
-		Status:    rules.StatusGreen,
-		Details:   []string{},
-		Timestamp: time.Now(),
+// total returns the number of observations: the +Inf bucket, or the highest finite bucket without one
+func (s histogramSeries) total() float64 {
+	if s.hasInf {
+		return s.infCount
+	}
+	if len(s.buckets) == 0 {
+		return 0
+	}
+	return s.buckets[len(s.buckets)-1].Count
+}
+
+// EvaluateHistogram evaluates a histogram rule. Each label set of a labeled
+// histogram is evaluated separately and produces its own result.
+func EvaluateHistogram(rule rules.Rule, metrics parser.MetricsData, loadLevel rules.LoadLevel) []rules.EvaluationResult {
+	newResult := func() rules.EvaluationResult {
+		return rules.EvaluationResult{
+			RuleName:  rule.MetricName,
+			Status:    rules.StatusGreen,
+			Details:   []string{},
+			Timestamp: time.Now(),
+		}
 	}
 
 	// Get histogram buckets
 	bucketMetricName := rule.MetricName + "_bucket"
 	bucketMetric, exists := metrics.GetMetric(bucketMetricName)
 	if !exists || len(bucketMetric.Values) == 0 {
-		result.Message = fmt.Sprintf("Histogram buckets for %s not found", rule.MetricName)
-		return result
+		return []rules.EvaluationResult{unknownResult(newResult(), rules.ReasonMetricNotFound,
+			fmt.Sprintf("Histogram buckets for %s not found", rule.MetricName))}
 	}
 
-	buckets := bucketMetric.GetHistogramBuckets()
-	if len(buckets) == 0 {
-		result.Message = "No histogram buckets found"
-		return result
+	configured := ""
+	if rule.HistogramConfig != nil {
+		configured = rule.HistogramConfig.Statistic
+	}
+	statistic, err := rules.ParseHistogramStatistic(configured)
+	if err != nil {
+		return []rules.EvaluationResult{unknownResult(newResult(), rules.ReasonInvalidConfig, err.Error())}
 	}
 
-	// Sort buckets by le value
-	sort.Slice(buckets, func(i, j int) bool {
-		return buckets[i].Le < buckets[j].Le
-	})
+	series := groupHistogramSeries(bucketMetric)
+	if len(series) == 0 {
+		return []rules.EvaluationResult{unknownResult(newResult(), rules.ReasonNoData, "No histogram buckets found")}
+	}
+
+	results := make([]rules.EvaluationResult, 0, len(series))
+	for _, s := range series {
+		result := newResult()
+		if len(series) > 1 {
+			result.RuleName += formatLabelSet(s.labels)
+		}
+		evaluateHistogramSeries(&result, rule, s, metrics, statistic, configured != "", loadLevel)
+		results = append(results, result)
+	}
+	return results
+}
 
-	// Get total count (from last bucket before +Inf)
-	totalCount := buckets[len(buckets)-1].Count
+// evaluateHistogramSeries grades one histogram series on the rule's statistic
+func evaluateHistogramSeries(result *rules.EvaluationResult, rule rules.Rule, s histogramSeries, metrics parser.MetricsData,
+	statistic rules.HistogramStatistic, statisticConfigured bool, loadLevel rules.LoadLevel) {
+	totalCount := s.total()
 	if totalCount == 0 {
 		result.Status = rules.StatusGreen
 		result.Message = "No histogram data yet"
-		return result
-	}
-
-	// Calculate P50, P75, P95 and P99
-	p50Threshold := totalCount * 0.50
-	p75Threshold := totalCount * 0.75
-	p95Threshold := totalCount * 0.95
-	p99Threshold := totalCount * 0.99
-
-	var p50, p75, p95, p99 float64
-	for _, bucket := range buckets {
-		if p50 == 0 && bucket.Count >= p50Threshold {
-			p50 = bucket.Le
-		}
-		if p75 == 0 && bucket.Count >= p75Threshold {
-			p75 = bucket.Le
-		}
-		if p95 == 0 && bucket.Count >= p95Threshold {
-			p95 = bucket.Le
-		}
-		if p99 == 0 && bucket.Count >= p99Threshold {
-			p99 = bucket.Le
-		}
-		if p50 > 0 && p75 > 0 && p95 > 0 && p99 > 0 {
-			break
-		}
+		return
 	}
 
-	result.Value = p95
 	unit := ""
 	if rule.HistogramConfig != nil {
 		unit = strings.TrimSpace(rule.HistogramConfig.Unit)
 	}
-	result.Details = append(result.Details,
-		fmt.Sprintf("p50: %s (i.e., 50%% of the observations are below this value)", formatHistogramValue(p50, unit)),
-		fmt.Sprintf("p75: %s (i.e., 75%% of the observations are below this value)", formatHistogramValue(p75, unit)),
-		fmt.Sprintf("p95: %s (i.e., 95%% of the observations are below this value)", formatHistogramValue(p95, unit)),
-		fmt.Sprintf("p99: %s (i.e., 99%% of the observations are below this value)", formatHistogramValue(p99, unit)),
-		fmt.Sprintf("count: %s", formatHumanInteger(totalCount)),
-	)
+
+	extras := map[string]interface{}{
+		"count": totalCount,
+	}
+	for _, q := range reportedQuantiles {
+		value := histogramQuantile(q.quantile, s.buckets, totalCount)
+		extras[q.name] = value
+		result.Details = append(result.Details,
+			fmt.Sprintf("%s: %s (i.e., %s%% of the observations are below this value)",
+				q.name, formatHistogramValue(value, unit), strconv.FormatFloat(q.quantile*100, 'f', -1, 64)))
+	}
+
+	mean, hasMean := histogramSeriesMean(rule.MetricName, s.key, metrics)
+	if hasMean {
+		extras["mean"] = mean
+		result.Details = append(result.Details, fmt.Sprintf("mean: %s", formatHistogramValue(mean, unit)))
+	}
+	result.Details = append(result.Details, fmt.Sprintf("count: %s", formatHumanInteger(totalCount)))
+
+	var graded float64
+	switch {
+	case statistic.Mean && !hasMean:
+		*result = unknownResult(*result, rules.ReasonMetricNotFound,
+			fmt.Sprintf("Histogram %s_sum/%s_count not found, cannot compute the mean", rule.MetricName, rule.MetricName))
+		return
+	case statistic.Mean:
+		graded = mean
+	default:
+		graded = histogramQuantile(statistic.Quantile, s.buckets, totalCount)
+		if _, reported := extras[statistic.Name]; !reported {
+			extras[statistic.Name] = graded
+			result.Details = append(result.Details, fmt.Sprintf("%s: %s", statistic.Name, formatHistogramValue(graded, unit)))
+		}
+	}
+	if statisticConfigured {
+		result.Details = append(result.Details, fmt.Sprintf("status based on: %s", statistic.Name))
+	}
+	gradeDistribution(result, rule, graded, extras, loadLevel)
+}
+
+// gradeDistribution grades the statistic of a histogram or summary series
+// against p95_good/p95_warn, which apply to any configured statistic
+func gradeDistribution(result *rules.EvaluationResult, rule rules.Rule, graded float64, extras map[string]interface{}, loadLevel rules.LoadLevel) {
+	result.Value = graded
 
 	// Select thresholds based on load level
 	thresholds := selectThresholds(rule, loadLevel)
 
-	// Evaluate thresholds based on P95
-	if p95 < thresholds.P95Good {
+	if graded < thresholds.P95Good {
 		result.Status = rules.StatusGreen
-		result.Message = interpolate(rule.Messages.Green, p95, map[string]interface{}{
-			"p95": p95,
-			"p99": p99,
-		})
-	} else if p95 < thresholds.P95Warn {
+		result.Message = interpolate(rule.Messages.Green, graded, extras)
+	} else if graded < thresholds.P95Warn {
 		result.Status = rules.StatusYellow
-		result.Message = interpolate(rule.Messages.Yellow, p95, map[string]interface{}{
-			"p95": p95,
-			"p99": p99,
-		})
+		result.Message = interpolate(rule.Messages.Yellow, graded, extras)
 	} else {
 		result.Status = rules.StatusRed
-		result.Message = interpolate(rule.Messages.Red, p95, map[string]interface{}{
-			"p95": p95,
-			"p99": p99,
-		})
+		result.Message = interpolate(rule.Messages.Red, graded, extras)
 	}
+}
 
-	return result
+// groupHistogramSeries splits bucket samples into series by their labels excluding "le", sorted by series key
+func groupHistogramSeries(bucketMetric *parser.Metric) []histogramSeries {
+	byKey := make(map[string]*histogramSeries)
+	var keys []string
+	for _, v := range bucketMetric.Values {
+		leStr, exists := v.Labels["le"]
+		if !exists {
+			continue
+		}
+		key := getSeriesKey(v.Labels)
+		s, seen := byKey[key]
+		if !seen {
+			s = &histogramSeries{key: key, labels: extractSeriesLabels(v.Labels)}
+			byKey[key] = s
+			keys = append(keys, key)
+		}
+		if leStr == "+Inf" {
+			s.infCount = v.Value
+			s.hasInf = true
+			continue
+		}
+		if le, err := strconv.ParseFloat(leStr, 64); err == nil {
+			s.buckets = append(s.buckets, parser.HistogramBucket{Le: le, Count: v.Value})
+		}
+	}
+
+	sort.Strings(keys)
+	series := make([]histogramSeries, 0, len(keys))
+	for _, key := range keys {
+		s := byKey[key]
+		if len(s.buckets) == 0 && !s.hasInf {
+			continue
+		}
+		sort.Slice(s.buckets, func(i, j int) bool {
+			return s.buckets[i].Le < s.buckets[j].Le
+		})
+		series = append(series, *s)
+	}
+	return series
 }
 
-// EvaluateHistogramInfOverflow evaluates all histogram metrics for +Inf bucket overflow
-// This is a general rule that applies to any histogram metric
-func EvaluateHistogramInfOverflow(metrics parser.MetricsData) []rules.EvaluationResult {
-	var results []rules.EvaluationResult
+// histogramQuantile estimates the q-quantile from cumulative finite buckets sorted
+// by le, interpolating linearly within the bucket that contains the rank like
+// PromQL's histogram_quantile. Ranks falling into the +Inf bucket return the
+// highest finite bound, and the lowest bucket is assumed to start at zero.
+func histogramQuantile(q float64, buckets []parser.HistogramBucket, total float64) float64 {
+	if len(buckets) == 0 || total == 0 {
+		return 0
+	}
+	rank := q * total
 
-	// Get all histogram base names
-	histogramBases := metrics.GetHistogramBaseNames()
+	b := sort.Search(len(buckets), func(i int) bool {
+		return buckets[i].Count >= rank
+	})
+	if b == len(buckets) {
+		return buckets[len(buckets)-1].Le
+	}
+	if b == 0 && buckets[0].Le <= 0 {
+		return buckets[0].Le
+	}
 
-	for _, baseName := range histogramBases {
-		results = append(results, evaluateSingleHistogramInfOverflow(baseName, metrics)...)
+	bucketStart := 0.0
+	bucketEnd := buckets[b].Le
+	count := buckets[b].Count
+	if b > 0 {
+		bucketStart = buckets[b-1].Le
+		count -= buckets[b-1].Count
+		rank -= buckets[b-1].Count
+	}
+	if count <= 0 {
+		return bucketEnd
 	}
+	return bucketStart + (bucketEnd-bucketStart)*(rank/count)
+}
 
-	return results
+// histogramSeriesMean computes _sum / _count for the series with the given key
+func histogramSeriesMean(baseName, seriesKey string, metrics parser.MetricsData) (float64, bool) {
+	sum, hasSum := histogramComponentValue(metrics, baseName+"_sum", seriesKey)
+	count, hasCount := histogramComponentValue(metrics, baseName+"_count", seriesKey)
+	if !hasSum || !hasCount || count == 0 {
+		return 0, false
+	}
+	return sum / count, true
+}
+
+func histogramComponentValue(metrics parser.MetricsData, name, seriesKey string) (float64, bool) {
+	metric, exists := metrics.GetMetric(name)
+	if !exists {
+		return 0, false
+	}
+	for _, v := range metric.Values {
+		if getSeriesKey(v.Labels) == seriesKey {
+			return v.Value, true
+		}
+	}
+	return 0, false
 }
 
-// evaluateSingleHistogramInfOverflow evaluates a single histogram metric for +Inf overflow
-// Handles multiple label combinations (series) by emitting one result per problematic series.
-func evaluateSingleHistogramInfOverflow(baseName string, metrics parser.MetricsData) []rules.EvaluationResult {
+// EvaluateHistogramInfOverflow grades the share of a histogram's observations
+// that are in the +Inf bucket, i.e. above the highest finite bucket, per series.
+// Series above thresholds.low percent are yellow and above thresholds.high red;
+// when every series is green a single result reports the worst one. Series
+// without a +Inf bucket, a finite bucket or observations are skipped.
+func EvaluateHistogramInfOverflow(rule rules.Rule, metrics parser.MetricsData, loadLevel rules.LoadLevel) []rules.EvaluationResult {
+	baseName := rule.MetricName
+	thresholds := selectThresholds(rule, loadLevel)
+
 	// Get histogram buckets
 	bucketMetricName := baseName + "_bucket"
 	bucketMetric, exists := metrics.GetMetric(bucketMetricName)
 	if !exists || len(bucketMetric.Values) == 0 {
-		return nil
+		result := rules.EvaluationResult{RuleName: baseName + " (+Inf overflow check)", Details: []string{}, Timestamp: time.Now()}
+		return []rules.EvaluationResult{unknownResult(result, rules.ReasonMetricNotFound,
+			fmt.Sprintf("Histogram buckets for %s not found", baseName))}
 	}
 	metricHelp := resolveMetricHelp(baseName, metrics)
-	guessedUnit := guessMetricUnit(baseName, metricHelp)
+	guessedUnit := GuessMetricUnit(baseName, metricHelp)
 
 	// Group buckets by label combination (excluding "le" label)
 	// Each label combination represents a separate time series
@@ -212,9 +348,9 @@ func evaluateSingleHistogramInfOverflow(baseName string, metrics parser.MetricsD
 		infPercentage := (infObservations / infCount) * 100.0
 
 		status := rules.StatusGreen
-		if infPercentage > infOverflowRedThresholdPercent {
+		if infPercentage > thresholds.High {
 			status = rules.StatusRed
-		} else if infPercentage > infOverflowYellowThresholdPercent {
+		} else if infPercentage > thresholds.Low {
 			status = rules.StatusYellow
 		}
 
@@ -246,12 +382,12 @@ func evaluateSingleHistogramInfOverflow(baseName string, metrics parser.MetricsD
 			continue
 		}
 		result := rules.EvaluationResult{
-			RuleName:     formatSeriesRuleName(baseName, eval.labels),
-			Status:       eval.status,
-			MetricHelp:   metricHelp,
-			Details:      []string{},
-			Timestamp:    time.Now(),
-			ReviewStatus: "Automatically generated rule; reviewed by the code author at the time of implementation.",
+			RuleName:   formatSeriesRuleName(baseName, eval.labels),
+			Status:     eval.status,
+			Value:      eval.infPercentage,
+			MetricHelp: metricHelp,
+			Details:    []string{},
+			Timestamp:  time.Now(),
 			PotentialActionUser: fmt.Sprintf("Further investigation is required to understand why values exceed %s. "+
 				"Check if there are other alerts for this specific metric with more precise context.", formatHistogramValue(eval.highestFiniteLe, guessedUnit)),
 			PotentialActionDeveloper: "Review code paths and metric instrumentation to confirm whether observed latencies are expected.",
@@ -284,12 +420,12 @@ func evaluateSingleHistogramInfOverflow(baseName string, metrics parser.MetricsD
 	}
 
 	greenResult := rules.EvaluationResult{
-		RuleName:     baseName + " (+Inf overflow check)",
-		Status:       rules.StatusGreen,
-		MetricHelp:   metricHelp,
-		Details:      []string{},
-		Timestamp:    time.Now(),
-		ReviewStatus: "Automatically generated rule; reviewed by the code author at the time of implementation.",
+		RuleName:   baseName + " (+Inf overflow check)",
+		Status:     rules.StatusGreen,
+		Value:      worstOverall.infPercentage,
+		MetricHelp: metricHelp,
+		Details:    []string{},
+		Timestamp:  time.Now(),
 	}
 	greenResult.Details = append(greenResult.Details,
 		"Total Number of Observations: "+formatHumanNumber(worstOverall.totalCount),
@@ -326,19 +462,7 @@ func extractSeriesLabels(labels map[string]string) map[string]string {
 }
 
 func formatSeriesRuleName(baseName string, labels map[string]string) string {
-	if len(labels) == 0 {
-		return baseName + " (+Inf overflow check)"
-	}
-	var keys []string
-	for key := range labels {
-		keys = append(keys, key)
-	}
-	sort.Strings(keys)
-	var parts []string
-	for _, key := range keys {
-		parts = append(parts, key+"=\""+labels[key]+"\"")
-	}
-	return baseName + "{" + strings.Join(parts, ",") + "} (+Inf overflow check)"
+	return baseName + formatLabelSet(labels) + " (+Inf overflow check)"
 }
 
 func resolveMetricHelp(baseName string, metrics parser.MetricsData) string {
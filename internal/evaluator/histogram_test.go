@@ -0,0 +1,151 @@
+package evaluator
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stackrox/sensor-metrics-analyzer/internal/parser"
+	"github.com/stackrox/sensor-metrics-analyzer/internal/rules"
+)
+
+func TestHistogramQuantile(t *testing.T) {
+	buckets := []parser.HistogramBucket{
+		{Le: 0.1, Count: 50},
+		{Le: 0.5, Count: 90},
+		{Le: 1, Count: 100},
+	}
+
+	tests := map[string]struct {
+		quantile float64
+		total    float64
+		want     float64
+	}{
+		"should interpolate within the first bucket starting at zero": {
+			quantile: 0.25,
+			total:    100,
+			want:     0.05,
+		},
+		"should interpolate within a middle bucket": {
+			quantile: 0.7,
+			total:    100,
+			want:     0.3,
+		},
+		"should return the bucket bound on an exact boundary": {
+			quantile: 0.9,
+			total:    100,
+			want:     0.5,
+		},
+		"should return the highest finite bound when the rank is in +Inf": {
+			quantile: 0.99,
+			total:    200,
+			want:     1,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := histogramQuantile(tt.quantile, buckets, tt.total)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("histogramQuantile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateHistogramPerSeries(t *testing.T) {
+	metrics := parser.MetricsData{
+		"latency_bucket": &parser.Metric{
+			Name: "latency_bucket",
+			Values: []parser.MetricValue{
+				{Labels: map[string]string{"le": "0.1", "path": "/fast"}, Value: 100},
+				{Labels: map[string]string{"le": "1", "path": "/fast"}, Value: 100},
+				{Labels: map[string]string{"le": "+Inf", "path": "/fast"}, Value: 100},
+				{Labels: map[string]string{"le": "0.1", "path": "/slow"}, Value: 0},
+				{Labels: map[string]string{"le": "1", "path": "/slow"}, Value: 10},
+				{Labels: map[string]string{"le": "+Inf", "path": "/slow"}, Value: 100},
+			},
+		},
+		"latency_sum": &parser.Metric{
+			Name: "latency_sum",
+			Values: []parser.MetricValue{
+				{Labels: map[string]string{"path": "/fast"}, Value: 2},
+				{Labels: map[string]string{"path": "/slow"}, Value: 300},
+			},
+		},
+		"latency_count": &parser.Metric{
+			Name: "latency_count",
+			Values: []parser.MetricValue{
+				{Labels: map[string]string{"path": "/fast"}, Value: 100},
+				{Labels: map[string]string{"path": "/slow"}, Value: 100},
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		statistic string
+		want      map[string]float64
+		wantState map[string]rules.Status
+	}{
+		"should grade each series on p95 by default": {
+			want: map[string]float64{
+				`latency{path="/fast"}`: 0.095,
+				`latency{path="/slow"}`: 1,
+			},
+			wantState: map[string]rules.Status{
+				`latency{path="/fast"}`: rules.StatusGreen,
+				`latency{path="/slow"}`: rules.StatusRed,
+			},
+		},
+		"should grade on the mean from _sum and _count": {
+			statistic: "mean",
+			want: map[string]float64{
+				`latency{path="/fast"}`: 0.02,
+				`latency{path="/slow"}`: 3,
+			},
+			wantState: map[string]rules.Status{
+				`latency{path="/fast"}`: rules.StatusGreen,
+				`latency{path="/slow"}`: rules.StatusRed,
+			},
+		},
+		"should grade on a configured quantile": {
+			statistic: "p50",
+			want: map[string]float64{
+				`latency{path="/fast"}`: 0.05,
+				`latency{path="/slow"}`: 1,
+			},
+			wantState: map[string]rules.Status{
+				`latency{path="/fast"}`: rules.StatusGreen,
+				`latency{path="/slow"}`: rules.StatusRed,
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			rule := rules.Rule{
+				RuleType:        rules.RuleTypeHistogram,
+				MetricName:      "latency",
+				HistogramConfig: &rules.HistogramConfig{Unit: "seconds", Statistic: tt.statistic},
+				Thresholds:      rules.Thresholds{P95Good: 0.2, P95Warn: 0.5},
+			}
+
+			results := EvaluateHistogram(rule, metrics, rules.LoadLevelMedium)
+			if len(results) != len(tt.want) {
+				t.Fatalf("EvaluateHistogram() got %d results, want %d", len(results), len(tt.want))
+			}
+			for _, result := range results {
+				want, ok := tt.want[result.RuleName]
+				if !ok {
+					t.Errorf("EvaluateHistogram() unexpected result %q", result.RuleName)
+					continue
+				}
+				if math.Abs(result.Value-want) > 1e-9 {
+					t.Errorf("EvaluateHistogram() %s value = %v, want %v", result.RuleName, result.Value, want)
+				}
+				if result.Status != tt.wantState[result.RuleName] {
+					t.Errorf("EvaluateHistogram() %s status = %v, want %v", result.RuleName, result.Status, tt.wantState[result.RuleName])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package evaluator
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/stackrox/sensor-metrics-analyzer/internal/parser"
+	"github.com/stackrox/sensor-metrics-analyzer/internal/rules"
+)
+
+// evaluatePattern evaluates a rule whose metric_name is a pattern once per
+// matching metric, in name order. A pattern matching nothing yields a single
+// UNKNOWN result, or none with allow_empty.
+func evaluatePattern(rule rules.Rule, metrics parser.MetricsData, loadLevel rules.LoadLevel, window rules.CounterWindow) []rules.EvaluationResult {
+	matches, err := MatchMetricPattern(rule, metrics)
+	if err != nil {
+		return []rules.EvaluationResult{patternResult(rule, rules.ReasonInvalidConfig, err.Error())}
+	}
+	if len(matches) == 0 {
+		if rule.AllowEmpty {
+			return nil
+		}
+		return []rules.EvaluationResult{patternResult(rule, rules.ReasonMetricNotFound,
+			fmt.Sprintf("No metric matches %s", rule.MetricName))}
+	}
+
+	var results []rules.EvaluationResult
+	for _, name := range matches {
+		matched := rule
+		matched.MetricName = name
+		results = append(results, EvaluateRule(matched, metrics, loadLevel, window)...)
+	}
+	return results
+}
+
+// MatchMetricPattern returns the metrics a rule whose metric_name is a pattern
+// evaluates, sorted by name
+func MatchMetricPattern(rule rules.Rule, metrics parser.MetricsData) ([]string, error) {
+	// The pattern was checked when the rule was loaded
+	pattern, err := rules.CompileMetricPattern(rule.MetricName)
+	if err != nil {
+		return nil, err
+	}
+	return rules.MatchMetricNames(pattern, patternCandidates(rule.RuleType, metrics)), nil
+}
+
+func patternResult(rule rules.Rule, reason rules.UnknownReason, message string) rules.EvaluationResult {
+	name := rule.DisplayName
+	if name == "" {
+		name = rule.MetricName
+	}
+	result := unknownResult(rules.EvaluationResult{
+		RuleName:  name,
+		Details:   []string{},
+		Timestamp: time.Now(),
+	}, reason, message)
+	result.ReviewStatus = applyReviewMetadata(rule)
+	return result
+}
+
+// patternCandidates lists the names a rule type can evaluate: histogram and
+// summary base names for distribution rules, metric families for cardinality
+// rules, and series names outside distributions otherwise
+func patternCandidates(ruleType rules.RuleType, metrics parser.MetricsData) []string {
+	var candidates []string
+	switch ruleType {
+	case rules.RuleTypeHistogram, rules.RuleTypeHistogramInfOverflow:
+		for _, baseName := range metrics.GetHistogramBaseNames() {
+			if bucket, ok := metrics.GetMetric(baseName + "_bucket"); ok && len(bucket.Values) > 0 {
+				candidates = append(candidates, baseName)
+			}
+		}
+	case rules.RuleTypeSummary:
+		candidates = metrics.GetSummaryBaseNames()
+	case rules.RuleTypeCardinality:
+		for _, family := range metrics.Cardinality() {
+			candidates = append(candidates, family.Family)
+		}
+	default:
+		for name, metric := range metrics {
+			if len(metric.Values) == 0 {
+				continue
+			}
+			// _sum and _count series are often untyped, their family is not
+			switch metrics[metrics.FamilyOf(name)].Type {
+			case "histogram", "gaugehistogram", "summary":
+				continue
+			}
+			candidates = append(candidates, name)
+		}
+	}
+	return candidates
+}
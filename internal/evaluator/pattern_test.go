@@ -0,0 +1,120 @@
+package evaluator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stackrox/sensor-metrics-analyzer/internal/parser"
+	"github.com/stackrox/sensor-metrics-analyzer/internal/rules"
+)
+
+const patternMetrics = `# TYPE rox_sensor_resolver_queue_size gauge
+rox_sensor_resolver_queue_size 40
+# TYPE rox_sensor_deployment_enhancement_queue_size gauge
+rox_sensor_deployment_enhancement_queue_size 700
+# TYPE rox_sensor_output_channel_size gauge
+rox_sensor_output_channel_size 5
+# TYPE rox_sensor_event_duration histogram
+rox_sensor_event_duration_bucket{le="10"} 90
+rox_sensor_event_duration_bucket{le="+Inf"} 100
+rox_sensor_event_duration_sum 500
+rox_sensor_event_duration_count 100
+# TYPE rox_sensor_sync_duration histogram
+rox_sensor_sync_duration_bucket{le="10"} 30
+rox_sensor_sync_duration_bucket{le="+Inf"} 100
+rox_sensor_sync_duration_sum 5000
+rox_sensor_sync_duration_count 100
+`
+
+func TestEvaluateRulePattern(t *testing.T) {
+	metrics, err := parser.ParseReader(strings.NewReader(patternMetrics))
+	if err != nil {
+		t.Fatalf("ParseReader() error = %v", err)
+	}
+
+	tests := map[string]struct {
+		rule       rules.Rule
+		wantNames  []string
+		wantStatus []rules.Status
+		wantReason rules.UnknownReason
+	}{
+		"should evaluate a glob once per matching metric": {
+			rule: rules.Rule{
+				RuleType:   rules.RuleTypeGauge,
+				MetricName: "rox_sensor_*_queue_size",
+				Thresholds: rules.Thresholds{Low: 100, High: 500, HigherIsWorse: true},
+			},
+			wantNames:  []string{"rox_sensor_deployment_enhancement_queue_size", "rox_sensor_resolver_queue_size"},
+			wantStatus: []rules.Status{rules.StatusRed, rules.StatusGreen},
+		},
+		"should not match histogram series with gauge rules": {
+			rule: rules.Rule{
+				RuleType:   rules.RuleTypeGauge,
+				MetricName: "/rox_sensor_(event|output)_.*/",
+				Thresholds: rules.Thresholds{Low: 100, High: 500, HigherIsWorse: true},
+			},
+			wantNames:  []string{"rox_sensor_output_channel_size"},
+			wantStatus: []rules.Status{rules.StatusGreen},
+		},
+		"should match histogram base names with +Inf overflow rules": {
+			rule: rules.Rule{
+				RuleType:   rules.RuleTypeHistogramInfOverflow,
+				MetricName: "*",
+				Thresholds: rules.Thresholds{Low: 25, High: 50},
+			},
+			wantNames: []string{
+				"rox_sensor_event_duration (+Inf overflow check)",
+				"rox_sensor_sync_duration (+Inf overflow check)",
+			},
+			wantStatus: []rules.Status{rules.StatusGreen, rules.StatusRed},
+		},
+		"should grade +Inf overflow against the rule thresholds": {
+			rule: rules.Rule{
+				RuleType:   rules.RuleTypeHistogramInfOverflow,
+				MetricName: "rox_sensor_*_duration",
+				Thresholds: rules.Thresholds{Low: 5, High: 90},
+			},
+			wantNames: []string{
+				"rox_sensor_event_duration (+Inf overflow check)",
+				"rox_sensor_sync_duration (+Inf overflow check)",
+			},
+			wantStatus: []rules.Status{rules.StatusYellow, rules.StatusYellow},
+		},
+		"should be unknown when nothing matches": {
+			rule: rules.Rule{
+				RuleType:    rules.RuleTypeGauge,
+				MetricName:  "rox_collector_*",
+				DisplayName: "Collector gauges",
+				Thresholds:  rules.Thresholds{Low: 100, High: 500, HigherIsWorse: true},
+			},
+			wantNames:  []string{"Collector gauges"},
+			wantStatus: []rules.Status{rules.StatusUnknown},
+			wantReason: rules.ReasonMetricNotFound,
+		},
+		"should report nothing when nothing matches and empty is allowed": {
+			rule: rules.Rule{
+				RuleType:   rules.RuleTypeHistogramInfOverflow,
+				MetricName: "rox_collector_*",
+				AllowEmpty: true,
+				Thresholds: rules.Thresholds{Low: 25, High: 50},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			results := EvaluateRule(tt.rule, metrics, rules.LoadLevelMedium, rules.CounterWindow{})
+			if len(results) != len(tt.wantNames) {
+				t.Fatalf("EvaluateRule() returned %d results, want %d: %+v", len(results), len(tt.wantNames), results)
+			}
+			for i, result := range results {
+				if result.RuleName != tt.wantNames[i] || result.Status != tt.wantStatus[i] {
+					t.Errorf("result %d = %s %s, want %s %s", i, result.RuleName, result.Status, tt.wantNames[i], tt.wantStatus[i])
+				}
+				if result.Reason != tt.wantReason {
+					t.Errorf("result %d reason = %q, want %q", i, result.Reason, tt.wantReason)
+				}
+			}
+		})
+	}
+}
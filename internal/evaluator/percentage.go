@@ -18,26 +18,35 @@ func EvaluatePercentage(rule rules.Rule, metrics parser.MetricsData, loadLevel r
 	}
 
 	if rule.PercentageConfig == nil {
-		result.Message = "Percentage config not specified"
-		return result
+		return unknownResult(result, rules.ReasonInvalidConfig, "Percentage config not specified")
 	}
 
 	// Get numerator metric
 	numeratorMetric, exists := metrics.GetMetric(rule.PercentageConfig.Numerator)
 	if !exists || len(numeratorMetric.Values) == 0 {
-		result.Message = fmt.Sprintf("Numerator metric %s not found", rule.PercentageConfig.Numerator)
-		return result
+		return unknownResult(result, rules.ReasonMetricNotFound, fmt.Sprintf("Numerator metric %s not found", rule.PercentageConfig.Numerator))
 	}
 
 	// Get denominator metric
 	denominatorMetric, exists := metrics.GetMetric(rule.PercentageConfig.Denominator)
 	if !exists || len(denominatorMetric.Values) == 0 {
-		result.Message = fmt.Sprintf("Denominator metric %s not found", rule.PercentageConfig.Denominator)
-		return result
+		return unknownResult(result, rules.ReasonMetricNotFound, fmt.Sprintf("Denominator metric %s not found", rule.PercentageConfig.Denominator))
 	}
 
-	numerator, _ := numeratorMetric.GetSingleValue()
-	denominator, _ := denominatorMetric.GetSingleValue()
+	numerator, numeratorMatched := selectSeriesValue(rule, numeratorMetric)
+	if numeratorMatched == 0 {
+		return missingSeries(result, rule, "Numerator metric", rule.PercentageConfig.Numerator)
+	}
+	denominator, denominatorMatched := selectSeriesValue(rule, denominatorMetric)
+	if denominatorMatched == 0 {
+		return missingSeries(result, rule, "Denominator metric", rule.PercentageConfig.Denominator)
+	}
+	if detail := aggregationDetail(rule, rule.PercentageConfig.Numerator, numeratorMatched); detail != "" {
+		result.Details = append(result.Details, detail)
+	}
+	if detail := aggregationDetail(rule, rule.PercentageConfig.Denominator, denominatorMatched); detail != "" {
+		result.Details = append(result.Details, detail)
+	}
 
 	if denominator == 0 {
 		result.Status = rules.StatusGreen
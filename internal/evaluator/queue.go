@@ -18,14 +18,12 @@ func EvaluateQueue(rule rules.Rule, metrics parser.MetricsData, loadLevel rules.
 	}
 
 	if rule.QueueConfig == nil {
-		result.Message = "Queue config not specified"
-		return result
+		return unknownResult(result, rules.ReasonInvalidConfig, "Queue config not specified")
 	}
 
 	metric, exists := metrics.GetMetric(rule.MetricName)
 	if !exists || len(metric.Values) == 0 {
-		result.Message = fmt.Sprintf("Metric %s not found", rule.MetricName)
-		return result
+		return unknownResult(result, rules.ReasonMetricNotFound, fmt.Sprintf("Metric %s not found", rule.MetricName))
 	}
 
 	// Group values by operation label
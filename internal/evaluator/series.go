@@ -0,0 +1,181 @@
+package evaluator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/stackrox/sensor-metrics-analyzer/internal/parser"
+	"github.com/stackrox/sensor-metrics-analyzer/internal/rules"
+)
+
+// ruleEvaluator is the signature shared by the single-result rule evaluators
+type ruleEvaluator func(rule rules.Rule, metrics parser.MetricsData, loadLevel rules.LoadLevel) rules.EvaluationResult
+
+// selectSeriesValue applies the rule's label selector to metric and combines the
+// matching series with the rule's aggregation. Without an aggregation the first
+// matching series is used. It returns the value and the number of matching series.
+func selectSeriesValue(rule rules.Rule, metric *parser.Metric) (float64, int) {
+	selector, err := parser.ParseLabelSelector(rule.LabelSelector)
+	if err != nil {
+		return 0, 0
+	}
+	values := metric.SelectValues(selector)
+	if len(values) == 0 {
+		return 0, 0
+	}
+
+	switch rule.Aggregation {
+	case rules.AggregationSum, rules.AggregationAvg:
+		sum := 0.0
+		for _, v := range values {
+			sum += v.Value
+		}
+		if rule.Aggregation == rules.AggregationAvg {
+			return sum / float64(len(values)), len(values)
+		}
+		return sum, len(values)
+	case rules.AggregationMax:
+		highest := values[0].Value
+		for _, v := range values[1:] {
+			if v.Value > highest {
+				highest = v.Value
+			}
+		}
+		return highest, len(values)
+	case rules.AggregationMin:
+		lowest := values[0].Value
+		for _, v := range values[1:] {
+			if v.Value < lowest {
+				lowest = v.Value
+			}
+		}
+		return lowest, len(values)
+	default:
+		return values[0].Value, len(values)
+	}
+}
+
+// missingSeries marks a result as UNKNOWN because a metric yielded no value for the rule
+func missingSeries(result rules.EvaluationResult, rule rules.Rule, kind, metricName string) rules.EvaluationResult {
+	if rule.LabelSelector != "" {
+		return unknownResult(result, rules.ReasonSeriesNotFound,
+			fmt.Sprintf("%s %s has no series matching %s", kind, metricName, rule.LabelSelector))
+	}
+	return unknownResult(result, rules.ReasonMetricNotFound, fmt.Sprintf("%s %s not found", kind, metricName))
+}
+
+// aggregationDetail describes how multiple series were combined, or "" when nothing was aggregated
+func aggregationDetail(rule rules.Rule, metricName string, matched int) string {
+	if rule.Aggregation == "" || rule.Aggregation == rules.AggregationPerSeries {
+		return ""
+	}
+	if rule.LabelSelector != "" {
+		return fmt.Sprintf("%s: %s of %d series matching %s", metricName, rule.Aggregation, matched, rule.LabelSelector)
+	}
+	return fmt.Sprintf("%s: %s of %d series", metricName, rule.Aggregation, matched)
+}
+
+// evaluatePerSeries evaluates the rule once per label set of its metrics, the way
+// EvaluateHistogramInfOverflow emits one result per histogram series.
+// Each evaluation sees only the samples of one label set; metrics that share
+// label sets (numerator/denominator, hits/misses) are paired by identical labels.
+func evaluatePerSeries(rule rules.Rule, metrics parser.MetricsData, loadLevel rules.LoadLevel, evaluate ruleEvaluator) []rules.EvaluationResult {
+	metricNames := seriesMetricNames(rule)
+	// The selector was checked when the rule was loaded
+	selector, _ := parser.ParseLabelSelector(rule.LabelSelector)
+
+	seriesLabels := make(map[string]map[string]string)
+	for _, name := range metricNames {
+		metric, exists := metrics.GetMetric(name)
+		if !exists {
+			continue
+		}
+		for _, v := range metric.SelectValues(selector) {
+			seriesLabels[parser.SeriesKey(v.Labels)] = v.Labels
+		}
+	}
+
+	if len(seriesLabels) == 0 {
+		// Let the evaluator explain what is missing
+		single := rule
+		single.Aggregation = ""
+		return []rules.EvaluationResult{evaluate(single, metrics, loadLevel)}
+	}
+
+	keys := make([]string, 0, len(seriesLabels))
+	for key := range seriesLabels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	seriesRule := rule
+	seriesRule.Aggregation = ""
+	seriesRule.LabelSelector = ""
+
+	results := make([]rules.EvaluationResult, 0, len(keys))
+	for _, key := range keys {
+		view := make(parser.MetricsData, len(metrics))
+		for name, metric := range metrics {
+			view[name] = metric
+		}
+		for _, name := range metricNames {
+			metric, exists := metrics.GetMetric(name)
+			if !exists {
+				continue
+			}
+			var values []parser.MetricValue
+			for _, v := range metric.Values {
+				if parser.SeriesKey(v.Labels) == key {
+					values = append(values, v)
+				}
+			}
+			if len(values) == 0 {
+				delete(view, name)
+				continue
+			}
+			filtered := *metric
+			filtered.Values = values
+			view[name] = &filtered
+		}
+
+		result := evaluate(seriesRule, view, loadLevel)
+		result.RuleName += formatLabelSet(seriesLabels[key])
+		results = append(results, result)
+	}
+	return results
+}
+
+// seriesMetricNames returns the metrics whose series a rule evaluates
+func seriesMetricNames(rule rules.Rule) []string {
+	switch rule.RuleType {
+	case rules.RuleTypePercentage:
+		if rule.PercentageConfig != nil {
+			return []string{rule.PercentageConfig.Numerator, rule.PercentageConfig.Denominator}
+		}
+	case rules.RuleTypeCacheHit:
+		if rule.CacheConfig != nil {
+			return []string{rule.CacheConfig.HitsMetric, rule.CacheConfig.MissesMetric}
+		}
+	default:
+		return []string{rule.MetricName}
+	}
+	return nil
+}
+
+// formatLabelSet formats labels as {key="value",...} sorted by key, or "" for no labels
+func formatLabelSet(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, key+"=\""+labels[key]+"\"")
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
@@ -0,0 +1,274 @@
+package evaluator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stackrox/sensor-metrics-analyzer/internal/parser"
+	"github.com/stackrox/sensor-metrics-analyzer/internal/rules"
+)
+
+func componentMetric(name string, values map[string]float64) *parser.Metric {
+	metric := &parser.Metric{Name: name, Type: "gauge"}
+	for component, value := range values {
+		metric.Values = append(metric.Values, parser.MetricValue{
+			Labels: map[string]string{"component": component},
+			Value:  value,
+		})
+	}
+	return metric
+}
+
+func TestEvaluateGaugeAggregation(t *testing.T) {
+	metrics := parser.MetricsData{
+		"queue_size": componentMetric("queue_size", map[string]float64{
+			"enricher": 300,
+			"detector": 50,
+			"resolver": 10,
+		}),
+	}
+
+	tests := map[string]struct {
+		selector    string
+		aggregation rules.Aggregation
+		wantValue   float64
+		wantStatus  rules.Status
+	}{
+		"should sum all series": {
+			aggregation: rules.AggregationSum,
+			wantValue:   360,
+			wantStatus:  rules.StatusRed,
+		},
+		"should take the max series": {
+			aggregation: rules.AggregationMax,
+			wantValue:   300,
+			wantStatus:  rules.StatusRed,
+		},
+		"should take the min series": {
+			aggregation: rules.AggregationMin,
+			wantValue:   10,
+			wantStatus:  rules.StatusGreen,
+		},
+		"should average all series": {
+			aggregation: rules.AggregationAvg,
+			wantValue:   120,
+			wantStatus:  rules.StatusYellow,
+		},
+		"should only consider series matching the selector": {
+			selector:    `{component="detector"}`,
+			aggregation: rules.AggregationSum,
+			wantValue:   50,
+			wantStatus:  rules.StatusGreen,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			rule := rules.Rule{
+				RuleType:      rules.RuleTypeGauge,
+				MetricName:    "queue_size",
+				LabelSelector: tt.selector,
+				Aggregation:   tt.aggregation,
+				Thresholds:    rules.Thresholds{Low: 100, High: 200, HigherIsWorse: true},
+			}
+			result := EvaluateGauge(rule, metrics, rules.LoadLevelMedium)
+			if result.Value != tt.wantValue {
+				t.Errorf("EvaluateGauge() value = %v, want %v", result.Value, tt.wantValue)
+			}
+			if result.Status != tt.wantStatus {
+				t.Errorf("EvaluateGauge() status = %v, want %v", result.Status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestEvaluateGaugeNoMatchingSeries(t *testing.T) {
+	metrics := parser.MetricsData{
+		"queue_size": componentMetric("queue_size", map[string]float64{"enricher": 300}),
+	}
+	rule := rules.Rule{
+		RuleType:      rules.RuleTypeGauge,
+		MetricName:    "queue_size",
+		LabelSelector: `{component="detector"}`,
+		Thresholds:    rules.Thresholds{Low: 100, High: 200, HigherIsWorse: true},
+	}
+
+	result := EvaluateGauge(rule, metrics, rules.LoadLevelMedium)
+	want := `Metric queue_size has no series matching {component="detector"}`
+	if result.Message != want {
+		t.Errorf("EvaluateGauge() message = %q, want %q", result.Message, want)
+	}
+	if result.Status != rules.StatusUnknown || result.Reason != rules.ReasonSeriesNotFound {
+		t.Errorf("EvaluateGauge() = %v/%v, want %v/%v", result.Status, result.Reason, rules.StatusUnknown, rules.ReasonSeriesNotFound)
+	}
+}
+
+func TestEvaluateAllRulesPerSeries(t *testing.T) {
+	metrics := parser.MetricsData{
+		"queue_size": componentMetric("queue_size", map[string]float64{
+			"enricher": 300,
+			"detector": 50,
+		}),
+		"dropped_total": componentMetric("dropped_total", map[string]float64{
+			"enricher": 30,
+			"detector": 1,
+		}),
+		"received_total": componentMetric("received_total", map[string]float64{
+			"enricher": 100,
+			"detector": 100,
+		}),
+	}
+	rulesList := []rules.Rule{
+		{
+			RuleType:    rules.RuleTypeGauge,
+			MetricName:  "queue_size",
+			Aggregation: rules.AggregationPerSeries,
+			Thresholds:  rules.Thresholds{Low: 100, High: 200, HigherIsWorse: true},
+		},
+		{
+			RuleType:    rules.RuleTypePercentage,
+			DisplayName: "Drop rate",
+			Aggregation: rules.AggregationPerSeries,
+			PercentageConfig: &rules.PercentageConfig{
+				Numerator:   "dropped_total",
+				Denominator: "received_total",
+			},
+			Thresholds: rules.Thresholds{Low: 5, High: 20},
+		},
+	}
+
+	report := EvaluateAllRules(rulesList, metrics, rules.LoadLevelMedium, "", rules.CounterWindow{})
+
+	want := map[string]struct {
+		value  float64
+		status rules.Status
+	}{
+		`queue_size{component="detector"}`: {value: 50, status: rules.StatusGreen},
+		`queue_size{component="enricher"}`: {value: 300, status: rules.StatusRed},
+		`Drop rate{component="detector"}`:  {value: 1, status: rules.StatusGreen},
+		`Drop rate{component="enricher"}`:  {value: 30, status: rules.StatusRed},
+	}
+	if len(report.Results) != len(want) {
+		t.Fatalf("EvaluateAllRules() got %d results, want %d", len(report.Results), len(want))
+	}
+	for _, result := range report.Results {
+		expected, ok := want[result.RuleName]
+		if !ok {
+			t.Errorf("EvaluateAllRules() unexpected result %q", result.RuleName)
+			continue
+		}
+		if result.Value != expected.value || result.Status != expected.status {
+			t.Errorf("EvaluateAllRules() %s = %v/%v, want %v/%v", result.RuleName, result.Value, result.Status, expected.value, expected.status)
+		}
+	}
+	if report.Summary.RedCount != 2 || report.Summary.GreenCount != 2 {
+		t.Errorf("EvaluateAllRules() summary = %+v, want 2 red and 2 green", report.Summary)
+	}
+}
+
+func TestEvaluateAllRulesUnknown(t *testing.T) {
+	metrics := parser.MetricsData{
+		"queue_size": componentMetric("queue_size", map[string]float64{"enricher": 50}),
+	}
+	rulesList := []rules.Rule{
+		{
+			RuleType:   rules.RuleTypeGauge,
+			MetricName: "queue_size",
+			Thresholds: rules.Thresholds{Low: 100, High: 200, HigherIsWorse: true},
+		},
+		{
+			RuleType:   rules.RuleTypeGauge,
+			MetricName: "renamed_queue_size",
+			Thresholds: rules.Thresholds{Low: 100, High: 200, HigherIsWorse: true},
+			Correlation: &rules.CorrelationConfig{
+				ElevateIf: []rules.CorrelationCondition{{MetricName: "queue_size", Operator: "gt", Value: 0, Status: rules.StatusRed}},
+			},
+			Remediation: &rules.Remediation{Green: "Nothing to do"},
+		},
+	}
+
+	report := EvaluateAllRules(rulesList, metrics, rules.LoadLevelMedium, "", rules.CounterWindow{})
+
+	if len(report.Results) != 2 {
+		t.Fatalf("EvaluateAllRules() got %d results, want 2", len(report.Results))
+	}
+	unknown := report.Results[1]
+	if unknown.Status != rules.StatusUnknown || unknown.Reason != rules.ReasonMetricNotFound {
+		t.Errorf("EvaluateAllRules() %s = %v/%v, want %v/%v", unknown.RuleName, unknown.Status, unknown.Reason, rules.StatusUnknown, rules.ReasonMetricNotFound)
+	}
+	if unknown.Remediation != "" {
+		t.Errorf("EvaluateAllRules() %s remediation = %q, want none", unknown.RuleName, unknown.Remediation)
+	}
+	if report.Summary.GreenCount != 1 || report.Summary.UnknownCount != 1 {
+		t.Errorf("EvaluateAllRules() summary = %+v, want 1 green and 1 unknown", report.Summary)
+	}
+}
+
+func TestEvaluateRuleCounterAverage(t *testing.T) {
+	metrics := parser.MetricsData{
+		"dropped_total": &parser.Metric{
+			Name:   "dropped_total",
+			Type:   "counter",
+			Values: []parser.MetricValue{{Value: 7200}},
+		},
+	}
+
+	tests := map[string]struct {
+		average    rules.CounterAverage
+		window     rules.CounterWindow
+		wantValue  float64
+		wantStatus rules.Status
+		wantReason rules.UnknownReason
+	}{
+		"should grade the raw counter without averaging": {
+			window:     rules.CounterWindow{Uptime: 2 * time.Hour},
+			wantValue:  7200,
+			wantStatus: rules.StatusRed,
+		},
+		"should average per hour over the uptime": {
+			average:    rules.CounterAveragePerHour,
+			window:     rules.CounterWindow{Uptime: 2 * time.Hour},
+			wantValue:  3600,
+			wantStatus: rules.StatusYellow,
+		},
+		"should average per second over the uptime": {
+			average:    rules.CounterAveragePerSecond,
+			window:     rules.CounterWindow{Uptime: 2 * time.Hour},
+			wantValue:  1,
+			wantStatus: rules.StatusGreen,
+		},
+		"should convert per-second rates to per hour": {
+			average:    rules.CounterAveragePerHour,
+			window:     rules.CounterWindow{Uptime: 2 * time.Hour, RateInterval: 5 * time.Minute},
+			wantValue:  7200 * 3600,
+			wantStatus: rules.StatusRed,
+		},
+		"should be unknown without uptime": {
+			average:    rules.CounterAveragePerHour,
+			wantStatus: rules.StatusUnknown,
+			wantReason: rules.ReasonUptimeUnknown,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			rule := rules.Rule{
+				RuleType:       rules.RuleTypeGauge,
+				MetricName:     "dropped_total",
+				CounterAverage: tt.average,
+				Thresholds:     rules.Thresholds{Low: 1000, High: 5000, HigherIsWorse: true},
+			}
+			results := EvaluateRule(rule, metrics, rules.LoadLevelMedium, tt.window)
+			if len(results) != 1 {
+				t.Fatalf("EvaluateRule() got %d results, want 1", len(results))
+			}
+			result := results[0]
+			if result.Status != tt.wantStatus || result.Reason != tt.wantReason {
+				t.Fatalf("EvaluateRule() status = %v/%v, want %v/%v (%s)", result.Status, result.Reason, tt.wantStatus, tt.wantReason, result.Message)
+			}
+			if tt.wantStatus != rules.StatusUnknown && result.Value != tt.wantValue {
+				t.Errorf("EvaluateRule() value = %g, want %g", result.Value, tt.wantValue)
+			}
+		})
+	}
+}
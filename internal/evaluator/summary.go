@@ -0,0 +1,146 @@
+package evaluator
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stackrox/sensor-metrics-analyzer/internal/parser"
+	"github.com/stackrox/sensor-metrics-analyzer/internal/rules"
+)
+
+// EvaluateSummary evaluates a summary rule on one of the quantiles the summary
+// exposes, or on its mean. Each label set of a labeled summary is evaluated
+// separately and produces its own result.
+func EvaluateSummary(rule rules.Rule, metrics parser.MetricsData, loadLevel rules.LoadLevel) []rules.EvaluationResult {
+	newResult := func() rules.EvaluationResult {
+		return rules.EvaluationResult{
+			RuleName:  rule.MetricName,
+			Status:    rules.StatusGreen,
+			Details:   []string{},
+			Timestamp: time.Now(),
+		}
+	}
+
+	configured, unit := "", ""
+	if rule.SummaryConfig != nil {
+		configured = rule.SummaryConfig.Statistic
+		unit = strings.TrimSpace(rule.SummaryConfig.Unit)
+	}
+	statistic, err := rules.ParseSummaryStatistic(configured)
+	if err != nil {
+		return []rules.EvaluationResult{unknownResult(newResult(), rules.ReasonInvalidConfig, err.Error())}
+	}
+
+	series := summarySeries(rule.MetricName, metrics)
+	if len(series) == 0 {
+		return []rules.EvaluationResult{unknownResult(newResult(), rules.ReasonMetricNotFound,
+			fmt.Sprintf("Summary %s not found", rule.MetricName))}
+	}
+
+	results := make([]rules.EvaluationResult, 0, len(series))
+	for _, s := range series {
+		result := newResult()
+		if len(series) > 1 {
+			result.RuleName += formatLabelSet(s.Labels)
+		}
+		evaluateSummarySeries(&result, rule, s, metrics, statistic, configured != "", unit, loadLevel)
+		results = append(results, result)
+	}
+	return results
+}
+
+// summarySeries returns the series of a summary. Summaries without quantiles
+// only expose _sum and _count, whose label sets are used instead.
+func summarySeries(baseName string, metrics parser.MetricsData) []parser.SummarySeries {
+	if metric, exists := metrics.GetMetric(baseName); exists {
+		if series := metric.GetSummarySeries(); len(series) > 0 {
+			return series
+		}
+	}
+	countMetric, exists := metrics.GetMetric(baseName + "_count")
+	if !exists {
+		return nil
+	}
+	series := make([]parser.SummarySeries, 0, len(countMetric.Values))
+	for _, v := range countMetric.Values {
+		series = append(series, parser.SummarySeries{Key: getSeriesKey(v.Labels), Labels: v.Labels})
+	}
+	return series
+}
+
+// evaluateSummarySeries grades one summary series on the rule's statistic
+func evaluateSummarySeries(result *rules.EvaluationResult, rule rules.Rule, s parser.SummarySeries, metrics parser.MetricsData,
+	statistic rules.HistogramStatistic, statisticConfigured bool, unit string, loadLevel rules.LoadLevel) {
+	count, hasCount := histogramComponentValue(metrics, rule.MetricName+"_count", s.Key)
+	if hasCount && count == 0 {
+		result.Status = rules.StatusGreen
+		result.Message = "No summary data yet"
+		return
+	}
+
+	extras := map[string]interface{}{}
+	var graded float64
+	found := false
+	exposed := make([]string, 0, len(s.Quantiles))
+	for _, q := range s.Quantiles {
+		name := SummaryQuantileName(q.Quantile)
+		exposed = append(exposed, name)
+		extras[name] = q.Value
+		result.Details = append(result.Details, fmt.Sprintf("%s: %s", name, formatHistogramValue(q.Value, unit)))
+		if !statistic.Mean && math.Abs(q.Quantile-statistic.Quantile) < 1e-9 {
+			graded, found = q.Value, true
+		}
+	}
+
+	mean, hasMean := histogramSeriesMean(rule.MetricName, s.Key, metrics)
+	if hasMean {
+		extras["mean"] = mean
+		result.Details = append(result.Details, fmt.Sprintf("mean: %s", formatHistogramValue(mean, unit)))
+	}
+	if hasCount {
+		extras["count"] = count
+		result.Details = append(result.Details, fmt.Sprintf("count: %s", formatHumanInteger(count)))
+	}
+
+	switch {
+	case statistic.Mean && !hasMean:
+		*result = unknownResult(*result, rules.ReasonMetricNotFound,
+			fmt.Sprintf("Summary %s_sum/%s_count not found, cannot compute the mean", rule.MetricName, rule.MetricName))
+		return
+	case statistic.Mean:
+		graded = mean
+	case !found:
+		available := "none"
+		if len(exposed) > 0 {
+			available = strings.Join(exposed, ", ")
+		}
+		*result = unknownResult(*result, rules.ReasonSeriesNotFound,
+			fmt.Sprintf("Summary %s does not expose %s (exposed: %s)", rule.MetricName, statistic.Name, available))
+		return
+	case math.IsNaN(graded):
+		// Quantiles are NaN while the summary's sliding window holds no observations
+		result.Status = rules.StatusGreen
+		result.Message = fmt.Sprintf("No %s observations in the summary's current window", statistic.Name)
+		return
+	}
+	if statisticConfigured {
+		result.Details = append(result.Details, fmt.Sprintf("status based on: %s", statistic.Name))
+	}
+	gradeDistribution(result, rule, graded, extras, loadLevel)
+}
+
+// SummaryQuantileName names a quantile like the statistics of summary rules,
+// e.g. "p99.9", "min" for quantile 0 and "max" for quantile 1
+func SummaryQuantileName(q float64) string {
+	switch q {
+	case 0:
+		return "min"
+	case 1:
+		return "max"
+	}
+	// Rounded to absorb floating point error, e.g. 0.999 * 100
+	return "p" + strconv.FormatFloat(math.Round(q*1e6)/1e4, 'f', -1, 64)
+}
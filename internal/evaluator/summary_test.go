@@ -0,0 +1,164 @@
+package evaluator
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stackrox/sensor-metrics-analyzer/internal/parser"
+	"github.com/stackrox/sensor-metrics-analyzer/internal/rules"
+)
+
+func TestEvaluateSummary(t *testing.T) {
+	metrics, err := parser.ParseReader(strings.NewReader(`# TYPE rpc_duration_seconds summary
+rpc_duration_seconds{path="/fast",quantile="0.5"} 0.01
+rpc_duration_seconds{path="/fast",quantile="0.99"} 0.1
+rpc_duration_seconds{path="/fast",quantile="1"} 0.3
+rpc_duration_seconds_sum{path="/fast"} 2
+rpc_duration_seconds_count{path="/fast"} 100
+rpc_duration_seconds{path="/slow",quantile="0.5"} 0.4
+rpc_duration_seconds{path="/slow",quantile="0.99"} 0.9
+rpc_duration_seconds{path="/slow",quantile="1"} NaN
+rpc_duration_seconds_sum{path="/slow"} 50
+rpc_duration_seconds_count{path="/slow"} 100
+# TYPE flush_seconds summary
+flush_seconds_sum 12
+flush_seconds_count 4
+# TYPE idle_seconds summary
+idle_seconds{quantile="0.99"} NaN
+idle_seconds_sum 0
+idle_seconds_count 0
+`))
+	if err != nil {
+		t.Fatalf("ParseReader() error = %v", err)
+	}
+
+	tests := map[string]struct {
+		metric     string
+		statistic  string
+		want       map[string]float64
+		wantState  map[string]rules.Status
+		wantReason rules.UnknownReason
+	}{
+		"should grade each series on a configured quantile": {
+			metric:    "rpc_duration_seconds",
+			statistic: "p99",
+			want: map[string]float64{
+				`rpc_duration_seconds{path="/fast"}`: 0.1,
+				`rpc_duration_seconds{path="/slow"}`: 0.9,
+			},
+			wantState: map[string]rules.Status{
+				`rpc_duration_seconds{path="/fast"}`: rules.StatusGreen,
+				`rpc_duration_seconds{path="/slow"}`: rules.StatusRed,
+			},
+		},
+		"should grade on the mean from _sum and _count": {
+			metric:    "rpc_duration_seconds",
+			statistic: "mean",
+			want: map[string]float64{
+				`rpc_duration_seconds{path="/fast"}`: 0.02,
+				`rpc_duration_seconds{path="/slow"}`: 0.5,
+			},
+			wantState: map[string]rules.Status{
+				`rpc_duration_seconds{path="/fast"}`: rules.StatusGreen,
+				`rpc_duration_seconds{path="/slow"}`: rules.StatusRed,
+			},
+		},
+		"should grade on quantile 1 and skip NaN quantiles": {
+			metric:    "rpc_duration_seconds",
+			statistic: "max",
+			want: map[string]float64{
+				`rpc_duration_seconds{path="/fast"}`: 0.3,
+				`rpc_duration_seconds{path="/slow"}`: 0,
+			},
+			wantState: map[string]rules.Status{
+				`rpc_duration_seconds{path="/fast"}`: rules.StatusYellow,
+				`rpc_duration_seconds{path="/slow"}`: rules.StatusGreen,
+			},
+		},
+		"should grade the mean of a summary without quantiles": {
+			metric:    "flush_seconds",
+			statistic: "mean",
+			want:      map[string]float64{"flush_seconds": 3},
+			wantState: map[string]rules.Status{"flush_seconds": rules.StatusRed},
+		},
+		"should be green without observations": {
+			metric:    "idle_seconds",
+			statistic: "p99",
+			want:      map[string]float64{"idle_seconds": 0},
+			wantState: map[string]rules.Status{"idle_seconds": rules.StatusGreen},
+		},
+		"should be unknown for a quantile the summary does not expose": {
+			metric:     "rpc_duration_seconds",
+			wantReason: rules.ReasonSeriesNotFound,
+		},
+		"should be unknown for a missing summary": {
+			metric:     "missing_seconds",
+			statistic:  "p99",
+			wantReason: rules.ReasonMetricNotFound,
+		},
+		"should be unknown for an invalid statistic": {
+			metric:     "rpc_duration_seconds",
+			statistic:  "p100",
+			wantReason: rules.ReasonInvalidConfig,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			rule := rules.Rule{
+				RuleType:      rules.RuleTypeSummary,
+				MetricName:    tt.metric,
+				SummaryConfig: &rules.SummaryConfig{Unit: "seconds", Statistic: tt.statistic},
+				Thresholds:    rules.Thresholds{P95Good: 0.2, P95Warn: 0.5},
+			}
+
+			results := EvaluateSummary(rule, metrics, rules.LoadLevelMedium)
+			if tt.wantReason != "" {
+				for _, result := range results {
+					if result.Status != rules.StatusUnknown || result.Reason != tt.wantReason {
+						t.Errorf("EvaluateSummary() %s = %v/%v, want %v/%v (%s)",
+							result.RuleName, result.Status, result.Reason, rules.StatusUnknown, tt.wantReason, result.Message)
+					}
+				}
+				return
+			}
+			if len(results) != len(tt.want) {
+				t.Fatalf("EvaluateSummary() got %d results, want %d", len(results), len(tt.want))
+			}
+			for _, result := range results {
+				want, ok := tt.want[result.RuleName]
+				if !ok {
+					t.Errorf("EvaluateSummary() unexpected result %q", result.RuleName)
+					continue
+				}
+				if math.Abs(result.Value-want) > 1e-9 {
+					t.Errorf("EvaluateSummary() %s value = %v, want %v", result.RuleName, result.Value, want)
+				}
+				if result.Status != tt.wantState[result.RuleName] {
+					t.Errorf("EvaluateSummary() %s status = %v, want %v (%s)", result.RuleName, result.Status, tt.wantState[result.RuleName], result.Message)
+				}
+			}
+		})
+	}
+}
+
+func TestSummaryQuantileName(t *testing.T) {
+	tests := map[string]struct {
+		quantile float64
+		want     string
+	}{
+		"should name the median":        {quantile: 0.5, want: "p50"},
+		"should keep fractional digits": {quantile: 0.999, want: "p99.9"},
+		"should name quantile 0 min":    {quantile: 0, want: "min"},
+		"should name quantile 1 max":    {quantile: 1, want: "max"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := SummaryQuantileName(tt.quantile); got != tt.want {
+				t.Errorf("SummaryQuantileName(%g) = %q, want %q", tt.quantile, got, tt.want)
+			}
+		})
+	}
+}
@@ -29,9 +29,10 @@ var (
 	}
 )
 
-// guessMetricUnit infers unit from metric name first, then HELP text.
+// GuessMetricUnit infers unit from metric name first, then HELP text: "seconds",
+// "milliseconds", "bytes", or "" when unknown.
 // HELP text is only used when exactly one unit candidate is detected.
-func guessMetricUnit(metricName, helpText string) string {
+func GuessMetricUnit(metricName, helpText string) string {
 	if unit := guessUnitFromMetricName(metricName); unit != "" {
 		return unit
 	}
@@ -103,9 +103,9 @@ func TestGuessMetricUnit(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := guessMetricUnit(tt.metricName, tt.helpText)
+			got := GuessMetricUnit(tt.metricName, tt.helpText)
 			if got != tt.want {
-				t.Fatalf("guessMetricUnit(%q, %q) = %q, want %q", tt.metricName, tt.helpText, got, tt.want)
+				t.Fatalf("GuessMetricUnit(%q, %q) = %q, want %q", tt.metricName, tt.helpText, got, tt.want)
 			}
 		})
 	}
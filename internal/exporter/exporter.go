@@ -0,0 +1,219 @@
+package exporter
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stackrox/sensor-metrics-analyzer/internal/rules"
+)
+
+// namespace prefixes every exported series
+const namespace = "sensor_metrics_analyzer"
+
+// contentType is the Prometheus text exposition format
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Exporter exposes the latest analysis report as Prometheus metrics
+type Exporter struct {
+	cluster string // Cluster label used until a report names the cluster
+
+	mu          sync.RWMutex
+	report      *rules.AnalysisReport
+	lastSuccess time.Time
+	lastFailed  bool
+	failures    int
+}
+
+// New creates an exporter without a report. cluster labels the refresh
+// metrics until the first report arrives.
+func New(cluster string) *Exporter {
+	return &Exporter{cluster: cluster}
+}
+
+// Update replaces the exported report
+func (e *Exporter) Update(report rules.AnalysisReport) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.report = &report
+	e.lastSuccess = time.Now()
+	e.lastFailed = false
+}
+
+// RecordFailure counts a failed refresh; the previous report stays exported
+// so that alerts do not resolve while the analyzer cannot reach Sensor
+func (e *Exporter) RecordFailure() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.lastFailed = true
+	e.failures++
+}
+
+// Run refreshes the report every interval until ctx is done. The first
+// refresh happens immediately. Failures are logged to logOut, which may be nil.
+func (e *Exporter) Run(ctx context.Context, interval time.Duration, refresh func() (rules.AnalysisReport, error), logOut io.Writer) {
+	if logOut == nil {
+		logOut = io.Discard
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		report, err := refresh()
+		if err != nil {
+			fmt.Fprintf(logOut, "Refresh failed: %v\n", err)
+			e.RecordFailure()
+		} else {
+			e.Update(report)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ServeHTTP writes the metrics in the Prometheus text format
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", contentType)
+	if err := e.Write(w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// Write writes the metrics in the Prometheus text format:
+//
+//	sensor_metrics_analyzer_rule_status{cluster,rule}   0 GREEN, 1 YELLOW, 2 RED, -1 UNKNOWN
+//	sensor_metrics_analyzer_rule_value{cluster,rule}    EvaluationResult.Value, except for UNKNOWN
+//	sensor_metrics_analyzer_results{cluster,status}     Number of results per status
+//	sensor_metrics_analyzer_info{cluster,acs_version,load_level}
+//	sensor_metrics_analyzer_last_refresh_success{cluster}
+//	sensor_metrics_analyzer_last_success_timestamp_seconds{cluster}
+//	sensor_metrics_analyzer_refresh_failures_total{cluster}
+//
+// Rules that produce several results with the same name are numbered
+// "name#2", "name#3", ... in report order to keep the series unique.
+func (e *Exporter) Write(w io.Writer) error {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	bw := bufio.NewWriter(w)
+	cluster := e.cluster
+
+	if e.report != nil {
+		report := e.report
+		if report.ClusterName != "" {
+			cluster = report.ClusterName
+		}
+		names := ruleNames(report.Results)
+
+		writeHeader(bw, "rule_status", "gauge", "Status of each analysis rule: 0 GREEN, 1 YELLOW, 2 RED, -1 UNKNOWN.")
+		for i, r := range report.Results {
+			if value, ok := statusValue(r.Status); ok {
+				writeSample(bw, "rule_status", value, "cluster", cluster, "rule", names[i])
+			}
+		}
+
+		writeHeader(bw, "rule_value", "gauge", "Value each analysis rule evaluated, in the rule's unit.")
+		for i, r := range report.Results {
+			// An UNKNOWN result has no value; exporting 0 would look like a reading
+			if rules.Status(strings.ToUpper(string(r.Status))) == rules.StatusUnknown {
+				continue
+			}
+			writeSample(bw, "rule_value", r.Value, "cluster", cluster, "rule", names[i])
+		}
+
+		writeHeader(bw, "results", "gauge", "Number of analysis results by status.")
+		counts := map[rules.Status]int{rules.StatusRed: 0, rules.StatusYellow: 0, rules.StatusGreen: 0, rules.StatusUnknown: 0}
+		for _, r := range report.Results {
+			counts[rules.Status(strings.ToUpper(string(r.Status)))]++
+		}
+		statuses := make([]string, 0, len(counts))
+		for status := range counts {
+			statuses = append(statuses, string(status))
+		}
+		sort.Strings(statuses)
+		for _, status := range statuses {
+			writeSample(bw, "results", float64(counts[rules.Status(status)]), "cluster", cluster, "status", status)
+		}
+
+		writeHeader(bw, "info", "gauge", "Detected ACS version and load level of the analyzed cluster.")
+		writeSample(bw, "info", 1, "cluster", cluster, "acs_version", report.ACSVersion, "load_level", string(report.LoadLevel))
+	}
+
+	success := 1.0
+	if e.report == nil || e.lastFailed {
+		success = 0
+	}
+	writeHeader(bw, "last_refresh_success", "gauge", "Whether the last refresh of the analysis succeeded.")
+	writeSample(bw, "last_refresh_success", success, "cluster", cluster)
+
+	if !e.lastSuccess.IsZero() {
+		writeHeader(bw, "last_success_timestamp_seconds", "gauge", "Unix time of the last successful refresh.")
+		writeSample(bw, "last_success_timestamp_seconds", float64(e.lastSuccess.UnixMilli())/1000, "cluster", cluster)
+	}
+
+	writeHeader(bw, "refresh_failures_total", "counter", "Number of failed refreshes of the analysis.")
+	writeSample(bw, "refresh_failures_total", float64(e.failures), "cluster", cluster)
+
+	return bw.Flush()
+}
+
+// statusValue maps a status to its exported value; statuses are compared
+// case-insensitively because correlation rules may report them in lower case.
+// UNKNOWN is exported as -1 so that alerts on rule_status > 0 ignore it.
+func statusValue(status rules.Status) (float64, bool) {
+	switch rules.Status(strings.ToUpper(string(status))) {
+	case rules.StatusUnknown:
+		return -1, true
+	case rules.StatusGreen:
+		return 0, true
+	case rules.StatusYellow:
+		return 1, true
+	case rules.StatusRed:
+		return 2, true
+	}
+	return 0, false
+}
+
+// ruleNames returns the rule label of each result, numbering repeated names
+func ruleNames(results []rules.EvaluationResult) []string {
+	seen := make(map[string]int, len(results))
+	names := make([]string, len(results))
+	for i, r := range results {
+		seen[r.RuleName]++
+		names[i] = r.RuleName
+		if n := seen[r.RuleName]; n > 1 {
+			names[i] = fmt.Sprintf("%s#%d", r.RuleName, n)
+		}
+	}
+	return names
+}
+
+func writeHeader(w io.Writer, name, metricType, help string) {
+	fmt.Fprintf(w, "# HELP %s_%s %s\n", namespace, name, help)
+	fmt.Fprintf(w, "# TYPE %s_%s %s\n", namespace, name, metricType)
+}
+
+// writeSample writes one sample; labels are name/value pairs
+func writeSample(w io.Writer, name string, value float64, labels ...string) {
+	pairs := make([]string, 0, len(labels)/2)
+	for i := 0; i+1 < len(labels); i += 2 {
+		pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", labels[i], escapeLabelValue(labels[i+1])))
+	}
+	fmt.Fprintf(w, "%s_%s{%s} %s\n", namespace, name, strings.Join(pairs, ","), strconv.FormatFloat(value, 'g', -1, 64))
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabelValue(value string) string {
+	return labelEscaper.Replace(value)
+}
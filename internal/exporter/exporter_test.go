@@ -0,0 +1,191 @@
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stackrox/sensor-metrics-analyzer/internal/parser"
+	"github.com/stackrox/sensor-metrics-analyzer/internal/rules"
+)
+
+func testReport() rules.AnalysisReport {
+	return rules.AnalysisReport{
+		ClusterName: "prod",
+		ACSVersion:  "4.7.0",
+		LoadLevel:   rules.LoadLevelHigh,
+		Results: []rules.EvaluationResult{
+			{RuleName: "queue_size", Status: rules.StatusRed, Value: 1200},
+			{RuleName: "goroutines", Status: rules.StatusGreen, Value: 300},
+			{RuleName: "goroutines", Status: "yellow", Value: 900},
+			{RuleName: `path "a\b"`, Status: rules.StatusGreen, Value: 0.5},
+			{RuleName: "renamed_metric", Status: rules.StatusUnknown, Reason: rules.ReasonMetricNotFound},
+		},
+	}
+}
+
+// sample returns the value of the series of name with the given labels
+func sample(t *testing.T, data parser.MetricsData, name string, labels map[string]string) (float64, bool) {
+	t.Helper()
+	metric, ok := data[name]
+	if !ok {
+		return 0, false
+	}
+	for _, v := range metric.Values {
+		if parser.MatchesLabels(v.Labels, labels) && len(v.Labels) == len(labels) {
+			return v.Value, true
+		}
+	}
+	return 0, false
+}
+
+func TestWrite(t *testing.T) {
+	e := New("sensor.stackrox")
+	e.Update(testReport())
+
+	var buf bytes.Buffer
+	if err := e.Write(&buf); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	data, _, err := parser.ParseReaderWithOptions(&buf, parser.ParseOptions{Strict: true})
+	if err != nil {
+		t.Fatalf("exported metrics do not parse: %v\n%s", err, buf.String())
+	}
+
+	tests := map[string]struct {
+		metric string
+		labels map[string]string
+		want   float64
+	}{
+		"should export RED as 2": {
+			metric: "sensor_metrics_analyzer_rule_status",
+			labels: map[string]string{"cluster": "prod", "rule": "queue_size"},
+			want:   2,
+		},
+		"should number repeated rule names": {
+			metric: "sensor_metrics_analyzer_rule_status",
+			labels: map[string]string{"cluster": "prod", "rule": "goroutines#2"},
+			want:   1,
+		},
+		"should export UNKNOWN as -1": {
+			metric: "sensor_metrics_analyzer_rule_status",
+			labels: map[string]string{"cluster": "prod", "rule": "renamed_metric"},
+			want:   -1,
+		},
+		"should count unknown results": {
+			metric: "sensor_metrics_analyzer_results",
+			labels: map[string]string{"cluster": "prod", "status": "UNKNOWN"},
+			want:   1,
+		},
+		"should escape label values": {
+			metric: "sensor_metrics_analyzer_rule_value",
+			labels: map[string]string{"cluster": "prod", "rule": `path "a\b"`},
+			want:   0.5,
+		},
+		"should export rule values": {
+			metric: "sensor_metrics_analyzer_rule_value",
+			labels: map[string]string{"cluster": "prod", "rule": "queue_size"},
+			want:   1200,
+		},
+		"should count results by normalized status": {
+			metric: "sensor_metrics_analyzer_results",
+			labels: map[string]string{"cluster": "prod", "status": "GREEN"},
+			want:   2,
+		},
+		"should count lower-case statuses": {
+			metric: "sensor_metrics_analyzer_results",
+			labels: map[string]string{"cluster": "prod", "status": "YELLOW"},
+			want:   1,
+		},
+		"should export cluster info": {
+			metric: "sensor_metrics_analyzer_info",
+			labels: map[string]string{"cluster": "prod", "acs_version": "4.7.0", "load_level": "high"},
+			want:   1,
+		},
+		"should report a successful refresh": {
+			metric: "sensor_metrics_analyzer_last_refresh_success",
+			labels: map[string]string{"cluster": "prod"},
+			want:   1,
+		},
+	}
+
+	if _, ok := sample(t, data, "sensor_metrics_analyzer_rule_value", map[string]string{"cluster": "prod", "rule": "renamed_metric"}); ok {
+		t.Errorf("rule_value exported for an UNKNOWN result:\n%s", buf.String())
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, ok := sample(t, data, tt.metric, tt.labels)
+			if !ok {
+				t.Fatalf("series %s%v not found in\n%s", tt.metric, tt.labels, buf.String())
+			}
+			if got != tt.want {
+				t.Errorf("%s%v = %v, want %v", tt.metric, tt.labels, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteWithoutReport(t *testing.T) {
+	e := New("sensor.stackrox")
+	e.RecordFailure()
+
+	var buf bytes.Buffer
+	if err := e.Write(&buf); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		`sensor_metrics_analyzer_last_refresh_success{cluster="sensor.stackrox"} 0`,
+		`sensor_metrics_analyzer_refresh_failures_total{cluster="sensor.stackrox"} 1`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "rule_status") || strings.Contains(out, "last_success_timestamp") {
+		t.Errorf("output should not contain results before the first report:\n%s", out)
+	}
+}
+
+func TestRun(t *testing.T) {
+	e := New("sensor.stackrox")
+	ctx, cancel := context.WithCancel(context.Background())
+
+	refreshes := 0
+	refresh := func() (rules.AnalysisReport, error) {
+		refreshes++
+		if refreshes == 2 {
+			return rules.AnalysisReport{}, errors.New("connection refused")
+		}
+		if refreshes == 3 {
+			cancel()
+		}
+		return testReport(), nil
+	}
+	e.Run(ctx, time.Millisecond, refresh, nil)
+
+	if refreshes != 3 {
+		t.Errorf("refreshes = %d, want 3", refreshes)
+	}
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("ServeHTTP() status = %d", rec.Code)
+	}
+	for _, want := range []string{
+		`sensor_metrics_analyzer_last_refresh_success{cluster="prod"} 1`,
+		`sensor_metrics_analyzer_refresh_failures_total{cluster="prod"} 1`,
+	} {
+		if !strings.Contains(rec.Body.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, rec.Body.String())
+		}
+	}
+}
@@ -0,0 +1,431 @@
+package expr
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/stackrox/sensor-metrics-analyzer/internal/parser"
+)
+
+// Expression is a parsed PromQL-like expression evaluated against a single scrape.
+//
+// Supported syntax:
+//   - vector selectors with =, !=, =~ and !~ label matchers: queue_size{component=~"enricher|detector"}
+//   - number literals, unary minus and parentheses
+//   - arithmetic (+ - * / % ^) and comparisons (== != > < >= <=, optionally with bool)
+//     between scalars and vectors, with on(...) / ignoring(...) for one-to-one matching
+//   - aggregations sum, avg, min, max, count, topk and bottomk with by (...) or without (...)
+//   - abs()
+//
+// There are no range vectors or rate functions: every expression sees one snapshot.
+type Expression struct {
+	source string
+	root   node
+}
+
+// Sample is one series of an expression result
+type Sample struct {
+	Labels map[string]string
+	Value  float64
+}
+
+// Result is the value of an evaluated expression: a scalar or a vector of samples
+type Result struct {
+	Scalar  bool
+	Samples []Sample // A single unlabeled sample for scalars; sorted by labels for vectors
+}
+
+// value is the intermediate result of evaluating a node
+type value struct {
+	scalar  bool
+	samples []Sample
+}
+
+// Parse parses an expression
+func Parse(input string) (*Expression, error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 1 {
+		return nil, fmt.Errorf("empty expression")
+	}
+	p := &exprParser{tokens: tokens}
+	root, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, p.errorf(t, "unexpected %s", t)
+	}
+	return &Expression{source: input, root: root}, nil
+}
+
+// String returns the expression source
+func (e *Expression) String() string {
+	return e.source
+}
+
+// MetricNames returns the names of the metrics the expression selects, sorted
+func (e *Expression) MetricNames() []string {
+	seen := make(map[string]bool)
+	collectMetricNames(e.root, seen)
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func collectMetricNames(n node, seen map[string]bool) {
+	switch n := n.(type) {
+	case *vectorSelector:
+		seen[n.name] = true
+	case *binaryExpr:
+		collectMetricNames(n.lhs, seen)
+		collectMetricNames(n.rhs, seen)
+	case *unaryMinus:
+		collectMetricNames(n.expr, seen)
+	case *aggregateExpr:
+		if n.param != nil {
+			collectMetricNames(n.param, seen)
+		}
+		collectMetricNames(n.expr, seen)
+	case *callExpr:
+		collectMetricNames(n.arg, seen)
+	}
+}
+
+// Eval evaluates the expression against a metrics snapshot
+func (e *Expression) Eval(metrics parser.MetricsData) (Result, error) {
+	v, err := e.root.eval(metrics)
+	if err != nil {
+		return Result{}, err
+	}
+	if !v.scalar {
+		sort.Slice(v.samples, func(i, j int) bool {
+			return parser.SeriesKey(v.samples[i].Labels) < parser.SeriesKey(v.samples[j].Labels)
+		})
+	}
+	return Result{Scalar: v.scalar, Samples: v.samples}, nil
+}
+
+func scalarValue(f float64) value {
+	return value{scalar: true, samples: []Sample{{Labels: map[string]string{}, Value: f}}}
+}
+
+func (n *numberLiteral) eval(parser.MetricsData) (value, error) {
+	return scalarValue(n.value), nil
+}
+
+func (n *vectorSelector) eval(metrics parser.MetricsData) (value, error) {
+	result := value{samples: []Sample{}}
+	metric, exists := metrics.GetMetric(n.name)
+	if !exists {
+		return result, nil
+	}
+	for _, v := range metric.Values {
+		if n.matches(v.Labels) {
+			result.samples = append(result.samples, Sample{Labels: v.Labels, Value: v.Value})
+		}
+	}
+	return result, nil
+}
+
+func (n *vectorSelector) matches(labels map[string]string) bool {
+	for _, m := range n.matchers {
+		actual := labels[m.name]
+		var ok bool
+		switch m.op {
+		case matchEqual:
+			ok = actual == m.value
+		case matchNotEqual:
+			ok = actual != m.value
+		case matchRegexp:
+			ok = m.re.MatchString(actual)
+		case matchNotRegexp:
+			ok = !m.re.MatchString(actual)
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *unaryMinus) eval(metrics parser.MetricsData) (value, error) {
+	v, err := n.expr.eval(metrics)
+	if err != nil {
+		return value{}, err
+	}
+	out := value{scalar: v.scalar, samples: make([]Sample, len(v.samples))}
+	for i, s := range v.samples {
+		out.samples[i] = Sample{Labels: s.Labels, Value: -s.Value}
+	}
+	return out, nil
+}
+
+func (n *callExpr) eval(metrics parser.MetricsData) (value, error) {
+	v, err := n.arg.eval(metrics)
+	if err != nil {
+		return value{}, err
+	}
+	out := value{scalar: v.scalar, samples: make([]Sample, len(v.samples))}
+	for i, s := range v.samples {
+		switch n.fn {
+		case "abs":
+			out.samples[i] = Sample{Labels: s.Labels, Value: math.Abs(s.Value)}
+		default:
+			return value{}, fmt.Errorf("unknown function %s", n.fn)
+		}
+	}
+	return out, nil
+}
+
+func (n *aggregateExpr) eval(metrics parser.MetricsData) (value, error) {
+	v, err := n.expr.eval(metrics)
+	if err != nil {
+		return value{}, err
+	}
+	if v.scalar {
+		return value{}, fmt.Errorf("%s expects a vector, got a scalar", n.op)
+	}
+
+	k := 0
+	if n.param != nil {
+		param, err := n.param.eval(metrics)
+		if err != nil {
+			return value{}, err
+		}
+		if !param.scalar {
+			return value{}, fmt.Errorf("%s expects a scalar parameter", n.op)
+		}
+		k = int(param.samples[0].Value)
+	}
+
+	// Group samples, keeping first-seen order for deterministic output
+	groups := make(map[string][]Sample)
+	groupLabels := make(map[string]map[string]string)
+	var order []string
+	for _, s := range v.samples {
+		labels := n.groupLabels(s.Labels)
+		key := parser.SeriesKey(labels)
+		if _, seen := groups[key]; !seen {
+			order = append(order, key)
+			groupLabels[key] = labels
+		}
+		groups[key] = append(groups[key], s)
+	}
+
+	result := value{samples: []Sample{}}
+	for _, key := range order {
+		samples := groups[key]
+		switch n.op {
+		case "topk", "bottomk":
+			sorted := append([]Sample(nil), samples...)
+			sort.SliceStable(sorted, func(i, j int) bool {
+				if n.op == "topk" {
+					return sorted[i].Value > sorted[j].Value
+				}
+				return sorted[i].Value < sorted[j].Value
+			})
+			if k < len(sorted) {
+				sorted = sorted[:max(k, 0)]
+			}
+			result.samples = append(result.samples, sorted...)
+		default:
+			result.samples = append(result.samples, Sample{Labels: groupLabels[key], Value: aggregate(n.op, samples)})
+		}
+	}
+	return result, nil
+}
+
+// groupLabels returns the labels identifying the aggregation group of a sample
+func (n *aggregateExpr) groupLabels(labels map[string]string) map[string]string {
+	grouped := make(map[string]string)
+	if n.without {
+		for key, val := range labels {
+			grouped[key] = val
+		}
+		for _, key := range n.grouping {
+			delete(grouped, key)
+		}
+		return grouped
+	}
+	for _, key := range n.grouping {
+		if val, ok := labels[key]; ok {
+			grouped[key] = val
+		}
+	}
+	return grouped
+}
+
+func aggregate(op string, samples []Sample) float64 {
+	switch op {
+	case "count":
+		return float64(len(samples))
+	case "min":
+		lowest := samples[0].Value
+		for _, s := range samples[1:] {
+			lowest = math.Min(lowest, s.Value)
+		}
+		return lowest
+	case "max":
+		highest := samples[0].Value
+		for _, s := range samples[1:] {
+			highest = math.Max(highest, s.Value)
+		}
+		return highest
+	}
+	sum := 0.0
+	for _, s := range samples {
+		sum += s.Value
+	}
+	if op == "avg" {
+		return sum / float64(len(samples))
+	}
+	return sum
+}
+
+func (n *binaryExpr) eval(metrics parser.MetricsData) (value, error) {
+	lhs, err := n.lhs.eval(metrics)
+	if err != nil {
+		return value{}, err
+	}
+	rhs, err := n.rhs.eval(metrics)
+	if err != nil {
+		return value{}, err
+	}
+	comparison := comparisonOps[n.op]
+
+	switch {
+	case lhs.scalar && rhs.scalar:
+		// Comparisons between scalars always return 0 or 1
+		return scalarValue(n.apply(lhs.samples[0].Value, rhs.samples[0].Value, comparison)), nil
+	case lhs.scalar || rhs.scalar:
+		return n.evalVectorScalar(lhs, rhs, comparison), nil
+	default:
+		return n.evalVectorVector(lhs, rhs, comparison)
+	}
+}
+
+// evalVectorScalar applies the operator between each vector sample and the scalar.
+// Comparisons without bool keep the samples for which the comparison holds.
+func (n *binaryExpr) evalVectorScalar(lhs, rhs value, comparison bool) value {
+	vector, scalar := lhs, rhs.samples[0].Value
+	if lhs.scalar {
+		vector, scalar = rhs, lhs.samples[0].Value
+	}
+
+	result := value{samples: []Sample{}}
+	for _, s := range vector.samples {
+		left, right := s.Value, scalar
+		if lhs.scalar {
+			left, right = scalar, s.Value
+		}
+		out := n.apply(left, right, comparison)
+		if comparison && !n.returnBool {
+			if out == 0 {
+				continue
+			}
+			out = s.Value
+		}
+		result.samples = append(result.samples, Sample{Labels: s.Labels, Value: out})
+	}
+	return result
+}
+
+// evalVectorVector matches samples one-to-one by their labels (restricted by on/ignoring)
+func (n *binaryExpr) evalVectorVector(lhs, rhs value, comparison bool) (value, error) {
+	rightBySignature := make(map[string]Sample, len(rhs.samples))
+	for _, s := range rhs.samples {
+		signature := parser.SeriesKey(n.matchingLabels(s.Labels))
+		if _, duplicate := rightBySignature[signature]; duplicate {
+			return value{}, fmt.Errorf("%s: multiple series on the right-hand side match {%s}; use on(...) or an aggregation", n.op, signature)
+		}
+		rightBySignature[signature] = s
+	}
+
+	result := value{samples: []Sample{}}
+	for _, left := range lhs.samples {
+		right, ok := rightBySignature[parser.SeriesKey(n.matchingLabels(left.Labels))]
+		if !ok {
+			continue
+		}
+		out := n.apply(left.Value, right.Value, comparison)
+		if comparison && !n.returnBool {
+			if out == 0 {
+				continue
+			}
+			result.samples = append(result.samples, left)
+			continue
+		}
+		result.samples = append(result.samples, Sample{Labels: n.matchingLabels(left.Labels), Value: out})
+	}
+	return result, nil
+}
+
+// matchingLabels returns the labels used to match series across the operands
+func (n *binaryExpr) matchingLabels(labels map[string]string) map[string]string {
+	if n.matching == nil {
+		return labels
+	}
+	matched := make(map[string]string)
+	if n.matching.on {
+		for _, key := range n.matching.labels {
+			if val, ok := labels[key]; ok {
+				matched[key] = val
+			}
+		}
+		return matched
+	}
+	for key, val := range labels {
+		matched[key] = val
+	}
+	for _, key := range n.matching.labels {
+		delete(matched, key)
+	}
+	return matched
+}
+
+// apply computes the operator; comparisons return 1 when true and 0 when false
+func (n *binaryExpr) apply(left, right float64, comparison bool) float64 {
+	if comparison {
+		var holds bool
+		switch n.op {
+		case "==":
+			holds = left == right
+		case "!=":
+			holds = left != right
+		case ">":
+			holds = left > right
+		case "<":
+			holds = left < right
+		case ">=":
+			holds = left >= right
+		case "<=":
+			holds = left <= right
+		}
+		if holds {
+			return 1
+		}
+		return 0
+	}
+	switch n.op {
+	case "+":
+		return left + right
+	case "-":
+		return left - right
+	case "*":
+		return left * right
+	case "/":
+		return left / right
+	case "%":
+		return math.Mod(left, right)
+	case "^":
+		return math.Pow(left, right)
+	}
+	return math.NaN()
+}
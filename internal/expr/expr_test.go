@@ -0,0 +1,187 @@
+package expr
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stackrox/sensor-metrics-analyzer/internal/parser"
+)
+
+func testMetrics() parser.MetricsData {
+	series := func(name string, values ...parser.MetricValue) *parser.Metric {
+		return &parser.Metric{Name: name, Values: values}
+	}
+	sample := func(value float64, labels ...string) parser.MetricValue {
+		m := map[string]string{}
+		for i := 0; i+1 < len(labels); i += 2 {
+			m[labels[i]] = labels[i+1]
+		}
+		return parser.MetricValue{Labels: m, Value: value}
+	}
+	return parser.MetricsData{
+		"queue_size": series("queue_size",
+			sample(300, "component", "enricher", "type", "a"),
+			sample(100, "component", "enricher", "type", "b"),
+			sample(50, "component", "detector", "type", "a"),
+			sample(10, "component", "resolver", "type", "a"),
+		),
+		"dropped_total": series("dropped_total",
+			sample(5, "component", "enricher"),
+			sample(0, "component", "detector"),
+		),
+		"received_total": series("received_total",
+			sample(100, "component", "enricher"),
+			sample(200, "component", "detector"),
+		),
+		"up": series("up", sample(1)),
+	}
+}
+
+func TestEval(t *testing.T) {
+	tests := map[string]struct {
+		expression string
+		wantScalar bool
+		want       map[string]float64 // Series key to value
+	}{
+		"should evaluate scalar arithmetic with precedence": {
+			expression: "1 + 2 * 3 ^ 2",
+			wantScalar: true,
+			want:       map[string]float64{"": 19},
+		},
+		"should select series with label matchers": {
+			expression: `queue_size{component=~"enr.*", type!="b"}`,
+			want:       map[string]float64{"component=enricher,type=a": 300},
+		},
+		"should sum by label": {
+			expression: "sum by (component) (queue_size)",
+			want: map[string]float64{
+				"component=enricher": 400,
+				"component=detector": 50,
+				"component=resolver": 10,
+			},
+		},
+		"should accept grouping after the arguments": {
+			expression: "max(queue_size) without (type)",
+			want: map[string]float64{
+				"component=enricher": 300,
+				"component=detector": 50,
+				"component=resolver": 10,
+			},
+		},
+		"should aggregate everything without grouping": {
+			expression: "count(queue_size)",
+			want:       map[string]float64{"": 4},
+		},
+		"should keep the top k series": {
+			expression: "topk(2, queue_size)",
+			want: map[string]float64{
+				"component=enricher,type=a": 300,
+				"component=enricher,type=b": 100,
+			},
+		},
+		"should match vectors one-to-one by labels": {
+			expression: "dropped_total / received_total * 100",
+			want: map[string]float64{
+				"component=enricher": 5,
+				"component=detector": 0,
+			},
+		},
+		"should match vectors on selected labels": {
+			expression: `queue_size{type="b"} / on(component) received_total`,
+			want:       map[string]float64{"component=enricher": 1},
+		},
+		"should filter with comparisons": {
+			expression: "queue_size > 60",
+			want: map[string]float64{
+				"component=enricher,type=a": 300,
+				"component=enricher,type=b": 100,
+			},
+		},
+		"should return 0 or 1 with bool comparisons": {
+			expression: "dropped_total > bool 0",
+			want: map[string]float64{
+				"component=enricher": 1,
+				"component=detector": 0,
+			},
+		},
+		"should apply abs and unary minus": {
+			expression: "abs(-up)",
+			want:       map[string]float64{"": 1},
+		},
+		"should return an empty vector for unknown metrics": {
+			expression: "missing_metric + 1",
+			want:       map[string]float64{},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			e, err := Parse(tt.expression)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			result, err := e.Eval(testMetrics())
+			if err != nil {
+				t.Fatalf("Eval() error = %v", err)
+			}
+			if result.Scalar != tt.wantScalar {
+				t.Errorf("Eval() scalar = %v, want %v", result.Scalar, tt.wantScalar)
+			}
+			if len(result.Samples) != len(tt.want) {
+				t.Fatalf("Eval() got %d samples, want %d: %v", len(result.Samples), len(tt.want), result.Samples)
+			}
+			for _, s := range result.Samples {
+				key := parser.SeriesKey(s.Labels)
+				want, ok := tt.want[key]
+				if !ok {
+					t.Errorf("Eval() unexpected series {%s}", key)
+					continue
+				}
+				if s.Value != want {
+					t.Errorf("Eval() {%s} = %v, want %v", key, s.Value, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := map[string]string{
+		"should reject empty expressions":        "",
+		"should reject unbalanced parentheses":   "sum(queue_size",
+		"should reject unquoted label values":    "queue_size{component=enricher}",
+		"should reject invalid regexps":          `queue_size{component=~"("}`,
+		"should reject trailing tokens":          "queue_size queue_size",
+		"should reject topk without a parameter": "topk(queue_size)",
+		"should reject unknown characters":       "queue_size @ 5",
+	}
+
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Parse(input); err == nil {
+				t.Errorf("Parse(%q) expected error but got none", input)
+			}
+		})
+	}
+}
+
+func TestEvalManyToOneError(t *testing.T) {
+	e, err := Parse("received_total / on() queue_size")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if _, err := e.Eval(testMetrics()); err == nil {
+		t.Error("Eval() expected error for ambiguous matching")
+	}
+}
+
+func TestMetricNames(t *testing.T) {
+	e, err := Parse(`topk(2, abs(-received_total{component="a"}) / on(component) sum by (component) (queue_size)) > queue_size`)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	got := strings.Join(e.MetricNames(), ",")
+	if got != "queue_size,received_total" {
+		t.Errorf("MetricNames() = %s, want queue_size,received_total", got)
+	}
+}
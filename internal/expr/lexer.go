@@ -0,0 +1,154 @@
+package expr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenNumber
+	tokenString
+	tokenLeftParen
+	tokenRightParen
+	tokenLeftBrace
+	tokenRightBrace
+	tokenComma
+	tokenOperator
+)
+
+type token struct {
+	kind tokenKind
+	text string  // Identifier, operator or unquoted string
+	num  float64 // Value of a number token
+	pos  int     // Byte offset in the expression
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of expression"
+	case tokenString:
+		return strconv.Quote(t.text)
+	case tokenNumber:
+		return strconv.FormatFloat(t.num, 'g', -1, 64)
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// operators lists multi-character operators before their single-character prefixes
+var operators = []string{"==", "!=", ">=", "<=", "=~", "!~", "+", "-", "*", "/", "%", "^", ">", "<", "="}
+
+// tokenize splits an expression into tokens
+func tokenize(input string) ([]token, error) {
+	var tokens []token
+	pos := 0
+	for pos < len(input) {
+		c := input[pos]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			pos++
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenLeftParen, text: "(", pos: pos})
+			pos++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenRightParen, text: ")", pos: pos})
+			pos++
+		case c == '{':
+			tokens = append(tokens, token{kind: tokenLeftBrace, text: "{", pos: pos})
+			pos++
+		case c == '}':
+			tokens = append(tokens, token{kind: tokenRightBrace, text: "}", pos: pos})
+			pos++
+		case c == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", pos: pos})
+			pos++
+		case c == '"' || c == '\'':
+			value, end, err := readString(input, pos)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenString, text: value, pos: pos})
+			pos = end
+		case isDigit(c) || (c == '.' && pos+1 < len(input) && isDigit(input[pos+1])):
+			end := pos
+			for end < len(input) && (isDigit(input[end]) || input[end] == '.' ||
+				input[end] == 'e' || input[end] == 'E' ||
+				((input[end] == '+' || input[end] == '-') && (input[end-1] == 'e' || input[end-1] == 'E'))) {
+				end++
+			}
+			num, err := strconv.ParseFloat(input[pos:end], 64)
+			if err != nil {
+				return nil, fmt.Errorf("position %d: invalid number %q", pos, input[pos:end])
+			}
+			tokens = append(tokens, token{kind: tokenNumber, num: num, text: input[pos:end], pos: pos})
+			pos = end
+		case isIdentStart(c):
+			end := pos
+			for end < len(input) && isIdentChar(input[end]) {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: input[pos:end], pos: pos})
+			pos = end
+		default:
+			op := ""
+			for _, candidate := range operators {
+				if strings.HasPrefix(input[pos:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("position %d: unexpected character %q", pos, c)
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: op, pos: pos})
+			pos += len(op)
+		}
+	}
+	tokens = append(tokens, token{kind: tokenEOF, pos: len(input)})
+	return tokens, nil
+}
+
+// readString reads a single- or double-quoted string starting at pos, resolving escapes
+func readString(input string, pos int) (string, int, error) {
+	quote := input[pos]
+	var value strings.Builder
+	for i := pos + 1; i < len(input); i++ {
+		switch input[i] {
+		case quote:
+			return value.String(), i + 1, nil
+		case '\\':
+			if i+1 >= len(input) {
+				break
+			}
+			i++
+			switch input[i] {
+			case 'n':
+				value.WriteByte('\n')
+			case 't':
+				value.WriteByte('\t')
+			default:
+				value.WriteByte(input[i])
+			}
+		default:
+			value.WriteByte(input[i])
+		}
+	}
+	return "", 0, fmt.Errorf("position %d: unterminated string", pos)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentStart(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || c == ':'
+}
+
+func isIdentChar(c byte) bool {
+	return isIdentStart(c) || isDigit(c)
+}
@@ -0,0 +1,407 @@
+package expr
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/stackrox/sensor-metrics-analyzer/internal/parser"
+)
+
+// node is an expression AST node
+type node interface {
+	eval(metrics parser.MetricsData) (value, error)
+}
+
+type numberLiteral struct {
+	value float64
+}
+
+type matchOp string
+
+const (
+	matchEqual     matchOp = "="
+	matchNotEqual  matchOp = "!="
+	matchRegexp    matchOp = "=~"
+	matchNotRegexp matchOp = "!~"
+)
+
+type labelMatcher struct {
+	name  string
+	op    matchOp
+	value string
+	re    *regexp.Regexp // Anchored, for =~ and !~
+}
+
+type vectorSelector struct {
+	name     string
+	matchers []labelMatcher
+}
+
+// vectorMatching restricts which labels identify matching series in a binary operation
+type vectorMatching struct {
+	on     bool // true for on(...), false for ignoring(...)
+	labels []string
+}
+
+type binaryExpr struct {
+	op         string
+	lhs, rhs   node
+	returnBool bool
+	matching   *vectorMatching
+}
+
+type unaryMinus struct {
+	expr node
+}
+
+type aggregateExpr struct {
+	op       string
+	param    node // k for topk/bottomk
+	expr     node
+	grouping []string
+	without  bool
+}
+
+type callExpr struct {
+	fn  string
+	arg node
+}
+
+// aggregations maps supported aggregation operators to whether they take a parameter
+var aggregations = map[string]bool{
+	"sum":     false,
+	"avg":     false,
+	"min":     false,
+	"max":     false,
+	"count":   false,
+	"topk":    true,
+	"bottomk": true,
+}
+
+// functions lists supported single-argument functions
+var functions = map[string]bool{
+	"abs": true,
+}
+
+var comparisonOps = map[string]bool{"==": true, "!=": true, ">": true, "<": true, ">=": true, "<=": true}
+
+// exprParser is a recursive descent parser. Operator precedence follows PromQL:
+// comparisons bind loosest, then + and -, then *, / and %, then ^ (right-associative).
+type exprParser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *exprParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *exprParser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *exprParser) errorf(t token, format string, args ...interface{}) error {
+	return fmt.Errorf("position %d: %s", t.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *exprParser) expect(kind tokenKind, what string) (token, error) {
+	t := p.next()
+	if t.kind != kind {
+		return t, p.errorf(t, "expected %s, got %s", what, t)
+	}
+	return t, nil
+}
+
+func (p *exprParser) isOperator(ops ...string) bool {
+	t := p.peek()
+	if t.kind != tokenOperator {
+		return false
+	}
+	for _, op := range ops {
+		if t.text == op {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *exprParser) parseExpr() (node, error) {
+	return p.parseComparison()
+}
+
+func (p *exprParser) parseComparison() (node, error) {
+	lhs, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenOperator && comparisonOps[p.peek().text] {
+		op := p.next().text
+		returnBool := false
+		if t := p.peek(); t.kind == tokenIdent && t.text == "bool" {
+			p.next()
+			returnBool = true
+		}
+		matching, err := p.parseMatching()
+		if err != nil {
+			return nil, err
+		}
+		rhs, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		lhs = &binaryExpr{op: op, lhs: lhs, rhs: rhs, returnBool: returnBool, matching: matching}
+	}
+	return lhs, nil
+}
+
+func (p *exprParser) parseAdditive() (node, error) {
+	lhs, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("+", "-") {
+		op := p.next().text
+		matching, err := p.parseMatching()
+		if err != nil {
+			return nil, err
+		}
+		rhs, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		lhs = &binaryExpr{op: op, lhs: lhs, rhs: rhs, matching: matching}
+	}
+	return lhs, nil
+}
+
+func (p *exprParser) parseMultiplicative() (node, error) {
+	lhs, err := p.parsePower()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("*", "/", "%") {
+		op := p.next().text
+		matching, err := p.parseMatching()
+		if err != nil {
+			return nil, err
+		}
+		rhs, err := p.parsePower()
+		if err != nil {
+			return nil, err
+		}
+		lhs = &binaryExpr{op: op, lhs: lhs, rhs: rhs, matching: matching}
+	}
+	return lhs, nil
+}
+
+func (p *exprParser) parsePower() (node, error) {
+	lhs, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	if !p.isOperator("^") {
+		return lhs, nil
+	}
+	p.next()
+	matching, err := p.parseMatching()
+	if err != nil {
+		return nil, err
+	}
+	rhs, err := p.parsePower()
+	if err != nil {
+		return nil, err
+	}
+	return &binaryExpr{op: "^", lhs: lhs, rhs: rhs, matching: matching}, nil
+}
+
+func (p *exprParser) parseUnary() (node, error) {
+	if p.isOperator("-") {
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryMinus{expr: operand}, nil
+	}
+	if p.isOperator("+") {
+		p.next()
+		return p.parseUnary()
+	}
+	return p.parsePrimary()
+}
+
+func (p *exprParser) parsePrimary() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenNumber:
+		return &numberLiteral{value: t.num}, nil
+	case tokenLeftParen:
+		inner, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokenRightParen, "')'"); err != nil {
+			return nil, err
+		}
+		return inner, nil
+	case tokenIdent:
+		next := p.peek()
+		if _, ok := aggregations[t.text]; ok && (next.kind == tokenLeftParen ||
+			(next.kind == tokenIdent && (next.text == "by" || next.text == "without"))) {
+			return p.parseAggregation(t.text)
+		}
+		if functions[t.text] && next.kind == tokenLeftParen {
+			return p.parseCall(t.text)
+		}
+		return p.parseSelector(t.text)
+	}
+	return nil, p.errorf(t, "unexpected %s", t)
+}
+
+func (p *exprParser) parseAggregation(op string) (node, error) {
+	agg := &aggregateExpr{op: op}
+
+	// Grouping may come before or after the arguments: sum by (a) (x) or sum(x) by (a)
+	if err := p.parseGrouping(agg); err != nil {
+		return nil, err
+	}
+
+	if _, err := p.expect(tokenLeftParen, "'('"); err != nil {
+		return nil, err
+	}
+	if aggregations[op] {
+		param, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		agg.param = param
+		if _, err := p.expect(tokenComma, "','"); err != nil {
+			return nil, err
+		}
+	}
+	inner, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	agg.expr = inner
+	if _, err := p.expect(tokenRightParen, "')'"); err != nil {
+		return nil, err
+	}
+
+	if agg.grouping == nil {
+		if err := p.parseGrouping(agg); err != nil {
+			return nil, err
+		}
+	}
+	return agg, nil
+}
+
+func (p *exprParser) parseGrouping(agg *aggregateExpr) error {
+	t := p.peek()
+	if t.kind != tokenIdent || (t.text != "by" && t.text != "without") {
+		return nil
+	}
+	p.next()
+	labels, err := p.parseLabelList()
+	if err != nil {
+		return err
+	}
+	agg.without = t.text == "without"
+	agg.grouping = labels
+	return nil
+}
+
+// parseMatching parses an optional on(...) or ignoring(...) clause
+func (p *exprParser) parseMatching() (*vectorMatching, error) {
+	t := p.peek()
+	if t.kind != tokenIdent || (t.text != "on" && t.text != "ignoring") {
+		return nil, nil
+	}
+	p.next()
+	labels, err := p.parseLabelList()
+	if err != nil {
+		return nil, err
+	}
+	return &vectorMatching{on: t.text == "on", labels: labels}, nil
+}
+
+func (p *exprParser) parseLabelList() ([]string, error) {
+	if _, err := p.expect(tokenLeftParen, "'('"); err != nil {
+		return nil, err
+	}
+	labels := []string{}
+	for p.peek().kind != tokenRightParen {
+		name, err := p.expect(tokenIdent, "label name")
+		if err != nil {
+			return nil, err
+		}
+		labels = append(labels, name.text)
+		if p.peek().kind != tokenComma {
+			break
+		}
+		p.next()
+	}
+	if _, err := p.expect(tokenRightParen, "')'"); err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
+
+func (p *exprParser) parseCall(fn string) (node, error) {
+	p.next() // '('
+	arg, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.expect(tokenRightParen, "')'"); err != nil {
+		return nil, err
+	}
+	return &callExpr{fn: fn, arg: arg}, nil
+}
+
+func (p *exprParser) parseSelector(name string) (node, error) {
+	selector := &vectorSelector{name: name}
+	if p.peek().kind != tokenLeftBrace {
+		return selector, nil
+	}
+	p.next()
+
+	for p.peek().kind != tokenRightBrace {
+		labelName, err := p.expect(tokenIdent, "label name")
+		if err != nil {
+			return nil, err
+		}
+		opToken := p.next()
+		op := matchOp(opToken.text)
+		if opToken.kind != tokenOperator || (op != matchEqual && op != matchNotEqual && op != matchRegexp && op != matchNotRegexp) {
+			return nil, p.errorf(opToken, "expected label matcher operator (=, !=, =~, !~), got %s", opToken)
+		}
+		valueToken, err := p.expect(tokenString, "quoted label value")
+		if err != nil {
+			return nil, err
+		}
+
+		matcher := labelMatcher{name: labelName.text, op: op, value: valueToken.text}
+		if op == matchRegexp || op == matchNotRegexp {
+			re, err := regexp.Compile("^(?:" + valueToken.text + ")$")
+			if err != nil {
+				return nil, p.errorf(valueToken, "invalid regular expression %q: %v", valueToken.text, err)
+			}
+			matcher.re = re
+		}
+		selector.matchers = append(selector.matchers, matcher)
+
+		if p.peek().kind != tokenComma {
+			break
+		}
+		p.next()
+	}
+	if _, err := p.expect(tokenRightBrace, "'}'"); err != nil {
+		return nil, err
+	}
+	return selector, nil
+}
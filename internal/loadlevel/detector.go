@@ -9,80 +9,170 @@ import (
 
 // Detector evaluates cluster load level based on weighted metrics
 type Detector struct {
-	rules []rules.LoadDetectionRule
+	rules    []rules.LoadDetectionRule
+	strategy rules.LoadStrategy
+	levels   []rules.LoadLevel
+	rank     map[rules.LoadLevel]int // Orders levels for max and for breaking vote ties
 }
 
-// NewDetector creates a new load level detector
-func NewDetector(loadRules []rules.LoadDetectionRule) *Detector {
+// NewDetector creates a new load level detector that combines the levels of
+// all rules with the given strategy. An empty strategy selects max. The load
+// levels are the tiers declared by the rules, or low, medium and high without
+// rules.
+func NewDetector(loadRules []rules.LoadDetectionRule, strategy rules.LoadStrategy) *Detector {
+	if strategy == "" {
+		strategy = rules.LoadStrategyMax
+	}
+	// Conflicting orders are reported when the rules are loaded; fall back to
+	// the best-effort order here
+	levels, _ := rules.LoadTiers(loadRules)
+	rank := make(map[rules.LoadLevel]int, len(levels))
+	for i, level := range levels {
+		rank[level] = i
+	}
 	return &Detector{
-		rules: loadRules,
+		rules:    loadRules,
+		strategy: strategy,
+		levels:   levels,
+		rank:     rank,
 	}
 }
 
-// Detect evaluates the cluster load level from metrics
-func (d *Detector) Detect(metrics parser.MetricsData) (rules.LoadLevel, error) {
-	if len(d.rules) == 0 {
-		// Default to medium if no rules configured
-		return rules.LoadLevelMedium, nil
+// Levels returns the load levels known to the detector from lowest to highest
+func (d *Detector) Levels() []rules.LoadLevel {
+	return d.levels
+}
+
+// DefaultLevel returns the level used when no rule detects one
+func (d *Detector) DefaultLevel() rules.LoadLevel {
+	return rules.DefaultLoadLevel(d.levels)
+}
+
+// Detect evaluates every load detection rule against the metrics and combines
+// their levels. Rules that found none of their metrics, or whose score matched
+// no threshold, do not take part. Without any participating rule the level
+// defaults to DefaultLevel.
+func (d *Detector) Detect(metrics parser.MetricsData) (rules.LoadLevel, rules.LoadDetection, error) {
+	detection := rules.LoadDetection{
+		Strategy: d.strategy,
+		Levels:   d.levels,
+		Rules:    make([]rules.LoadRuleVerdict, 0, len(d.rules)),
+	}
+	for _, rule := range d.rules {
+		detection.Rules = append(detection.Rules, evaluateRule(rule, metrics))
 	}
 
-	// Use the first load detection rule
-	rule := d.rules[0]
+	level, err := d.combine(detection.Rules)
+	if err != nil {
+		return "", detection, err
+	}
+	detection.Detected = level
+	return level, detection, nil
+}
+
+// evaluateRule computes the weighted average of a rule's metrics and the
+// first threshold it matches
+func evaluateRule(rule rules.LoadDetectionRule, metrics parser.MetricsData) rules.LoadRuleVerdict {
+	verdict := rules.LoadRuleVerdict{
+		RuleName: rule.DisplayName,
+		Weight:   rule.Weight,
+	}
+	if verdict.Weight == 0 {
+		verdict.Weight = 1
+	}
 
 	// Calculate weighted sum of metrics
 	weightedSum := 0.0
 	totalWeight := 0.0
 
 	for _, metricDef := range rule.Metrics {
-		metric, exists := metrics.GetMetric(metricDef.Source)
-		if !exists {
-			continue // Skip missing metrics
+		input := rules.LoadMetricInput{
+			Name:   metricDef.Name,
+			Source: metricDef.Source,
+			Weight: metricDef.Weight,
 		}
-
-		// Get the metric value (sum all values if multiple)
-		value := metric.SumValues()
-		weightedSum += value * metricDef.Weight
-		totalWeight += metricDef.Weight
+		// Missing metrics are skipped
+		if metric, exists := metrics.GetMetric(metricDef.Source); exists {
+			// Get the metric value (sum all values if multiple)
+			input.Found = true
+			input.Value = metric.SumValues()
+			weightedSum += input.Value * metricDef.Weight
+			totalWeight += metricDef.Weight
+		}
+		verdict.Metrics = append(verdict.Metrics, input)
 	}
 
 	if totalWeight == 0 {
-		// No metrics found, default to medium
-		return rules.LoadLevelMedium, nil
+		// No metrics found, the rule has no opinion
+		return verdict
 	}
 
 	// Normalize by total weight (optional, but helps with consistency)
-	normalizedValue := weightedSum / totalWeight
+	verdict.Score = weightedSum / totalWeight
+	for i, input := range verdict.Metrics {
+		if input.Found {
+			verdict.Metrics[i].Contribution = input.Value * input.Weight / totalWeight
+		}
+	}
 
 	// Find matching threshold
-	for _, threshold := range rule.Thresholds {
-		matches := true
-		if threshold.MinValue > 0 && normalizedValue < threshold.MinValue {
-			matches = false
-		}
-		if threshold.MaxValue > 0 && normalizedValue >= threshold.MaxValue {
-			matches = false
-		}
-		if matches {
-			return threshold.Level, nil
+	for i, threshold := range rule.Thresholds {
+		if threshold.Matches(verdict.Score) {
+			verdict.Level = threshold.Level
+			verdict.Threshold = &rule.Thresholds[i]
+			break
 		}
 	}
-
-	// Default to medium if no threshold matches
-	return rules.LoadLevelMedium, nil
+	return verdict
 }
 
-// DetectWithOverride allows overriding the detected load level
-func DetectWithOverride(metrics parser.MetricsData, detector *Detector, override rules.LoadLevel) (rules.LoadLevel, error) {
-	if override != "" {
-		// Validate override
-		validLevels := []rules.LoadLevel{rules.LoadLevelLow, rules.LoadLevelMedium, rules.LoadLevelHigh}
-		for _, level := range validLevels {
-			if override == level {
-				return override, nil
+// combine merges the levels of the rules that detected one
+func (d *Detector) combine(verdicts []rules.LoadRuleVerdict) (rules.LoadLevel, error) {
+	votes := make(map[rules.LoadLevel]float64)
+	for _, v := range verdicts {
+		if v.Level != "" {
+			votes[v.Level] += v.Weight
+		}
+	}
+	if len(votes) == 0 {
+		return d.DefaultLevel(), nil
+	}
+
+	var best rules.LoadLevel
+	switch d.strategy {
+	case rules.LoadStrategyMax:
+		for level := range votes {
+			if best == "" || d.rank[level] > d.rank[best] {
+				best = level
 			}
 		}
-		return "", fmt.Errorf("invalid load level override: %s", override)
+	case rules.LoadStrategyWeightedVote:
+		// Ties go to the higher level
+		for level, weight := range votes {
+			if best == "" || weight > votes[best] || (weight == votes[best] && d.rank[level] > d.rank[best]) {
+				best = level
+			}
+		}
+	default:
+		return "", fmt.Errorf("invalid load detection strategy: %s", d.strategy)
 	}
+	return best, nil
+}
 
-	return detector.Detect(metrics)
+// DetectWithOverride allows overriding the detected load level. The rules are
+// evaluated either way so that the report can show what would have been detected.
+func DetectWithOverride(metrics parser.MetricsData, detector *Detector, override rules.LoadLevel) (rules.LoadLevel, rules.LoadDetection, error) {
+	level, detection, err := detector.Detect(metrics)
+	if override == "" {
+		return level, detection, err
+	}
+
+	// The override must be one of the declared tiers
+	for _, valid := range detector.Levels() {
+		if override == valid {
+			detection.Overridden = true
+			return override, detection, nil
+		}
+	}
+	return "", detection, fmt.Errorf("invalid load level override: %s (must be one of: %s)", override, rules.FormatLoadLevels(detector.Levels()))
 }
@@ -1,6 +1,7 @@
 package loadlevel
 
 import (
+	"math"
 	"testing"
 
 	"github.com/stackrox/sensor-metrics-analyzer/internal/parser"
@@ -21,9 +22,9 @@ func TestDetect(t *testing.T) {
 					{Source: "pods", Weight: 0.5},
 				},
 				Thresholds: []rules.LoadDetectionThreshold{
-					{Level: rules.LoadLevelLow, MaxValue: 100},
-					{Level: rules.LoadLevelMedium, MinValue: 100, MaxValue: 500},
-					{Level: rules.LoadLevelHigh, MinValue: 500},
+					{Level: rules.LoadLevelLow, MaxValue: bound(100)},
+					{Level: rules.LoadLevelMedium, MinValue: bound(100), MaxValue: bound(500)},
+					{Level: rules.LoadLevelHigh, MinValue: bound(500)},
 				},
 			},
 			metrics: parser.MetricsData{
@@ -50,9 +51,9 @@ func TestDetect(t *testing.T) {
 					{Source: "pods", Weight: 0.5},
 				},
 				Thresholds: []rules.LoadDetectionThreshold{
-					{Level: rules.LoadLevelLow, MaxValue: 100},
-					{Level: rules.LoadLevelMedium, MinValue: 100, MaxValue: 500},
-					{Level: rules.LoadLevelHigh, MinValue: 500},
+					{Level: rules.LoadLevelLow, MaxValue: bound(100)},
+					{Level: rules.LoadLevelMedium, MinValue: bound(100), MaxValue: bound(500)},
+					{Level: rules.LoadLevelHigh, MinValue: bound(500)},
 				},
 			},
 			metrics: parser.MetricsData{
@@ -79,9 +80,9 @@ func TestDetect(t *testing.T) {
 					{Source: "pods", Weight: 0.5},
 				},
 				Thresholds: []rules.LoadDetectionThreshold{
-					{Level: rules.LoadLevelLow, MaxValue: 100},
-					{Level: rules.LoadLevelMedium, MinValue: 100, MaxValue: 500},
-					{Level: rules.LoadLevelHigh, MinValue: 500},
+					{Level: rules.LoadLevelLow, MaxValue: bound(100)},
+					{Level: rules.LoadLevelMedium, MinValue: bound(100), MaxValue: bound(500)},
+					{Level: rules.LoadLevelHigh, MinValue: bound(500)},
 				},
 			},
 			metrics: parser.MetricsData{
@@ -110,9 +111,9 @@ func TestDetect(t *testing.T) {
 					{Source: "missing_metric", Weight: 1.0},
 				},
 				Thresholds: []rules.LoadDetectionThreshold{
-					{Level: rules.LoadLevelLow, MaxValue: 100},
-					{Level: rules.LoadLevelMedium, MinValue: 100, MaxValue: 500},
-					{Level: rules.LoadLevelHigh, MinValue: 500},
+					{Level: rules.LoadLevelLow, MaxValue: bound(100)},
+					{Level: rules.LoadLevelMedium, MinValue: bound(100), MaxValue: bound(500)},
+					{Level: rules.LoadLevelHigh, MinValue: bound(500)},
 				},
 			},
 			metrics:   parser.MetricsData{},
@@ -126,9 +127,9 @@ func TestDetect(t *testing.T) {
 					{Source: "pods", Weight: 0.5},
 				},
 				Thresholds: []rules.LoadDetectionThreshold{
-					{Level: rules.LoadLevelLow, MaxValue: 100},
-					{Level: rules.LoadLevelMedium, MinValue: 100, MaxValue: 500},
-					{Level: rules.LoadLevelHigh, MinValue: 500},
+					{Level: rules.LoadLevelLow, MaxValue: bound(100)},
+					{Level: rules.LoadLevelMedium, MinValue: bound(100), MaxValue: bound(500)},
+					{Level: rules.LoadLevelHigh, MinValue: bound(500)},
 				},
 			},
 			metrics: parser.MetricsData{
@@ -155,8 +156,8 @@ func TestDetect(t *testing.T) {
 
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
-			detector := NewDetector([]rules.LoadDetectionRule{tt.rule})
-			level, err := detector.Detect(tt.metrics)
+			detector := NewDetector([]rules.LoadDetectionRule{tt.rule}, "")
+			level, _, err := detector.Detect(tt.metrics)
 
 			if (err != nil) != tt.wantError {
 				t.Errorf("Detect() error = %v, wantError %v", err, tt.wantError)
@@ -205,8 +206,8 @@ func TestDetectWithOverride(t *testing.T) {
 
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
-			detector := NewDetector([]rules.LoadDetectionRule{})
-			level, err := DetectWithOverride(tt.metrics, detector, tt.override)
+			detector := NewDetector([]rules.LoadDetectionRule{}, "")
+			level, _, err := DetectWithOverride(tt.metrics, detector, tt.override)
 
 			if (err != nil) != tt.wantError {
 				t.Errorf("DetectWithOverride() error = %v, wantError %v", err, tt.wantError)
@@ -219,3 +220,235 @@ func TestDetectWithOverride(t *testing.T) {
 		})
 	}
 }
+
+func bound(v float64) *float64 {
+	return &v
+}
+
+func TestDetectMultipleRules(t *testing.T) {
+	metrics := parser.MetricsData{
+		"containers":    &parser.Metric{Name: "containers", Values: []parser.MetricValue{{Value: 600, Labels: map[string]string{}}}},
+		"deployments":   &parser.Metric{Name: "deployments", Values: []parser.MetricValue{{Value: 40, Labels: map[string]string{}}}},
+		"network_flows": &parser.Metric{Name: "network_flows", Values: []parser.MetricValue{{Value: 150, Labels: map[string]string{}}}},
+	}
+	thresholds := []rules.LoadDetectionThreshold{
+		{Level: rules.LoadLevelLow, MaxValue: bound(100)},
+		{Level: rules.LoadLevelMedium, MinValue: bound(100), MaxValue: bound(500)},
+		{Level: rules.LoadLevelHigh, MinValue: bound(500)},
+	}
+	rule := func(name, source string, weight float64) rules.LoadDetectionRule {
+		return rules.LoadDetectionRule{
+			DisplayName: name,
+			Weight:      weight,
+			Metrics:     []rules.LoadDetectionMetric{{Source: source, Weight: 1}},
+			Thresholds:  thresholds,
+		}
+	}
+	containers := rule("containers", "containers", 1)    // 600 → high
+	deployments := rule("deployments", "deployments", 1) // 40 → low
+	flows := rule("flows", "network_flows", 1)           // 150 → medium
+	heavyFlows := rule("flows", "network_flows", 3)      // 150 → medium, 3 votes
+	missing := rule("missing", "missing_metric", 10)     // No metrics, no vote
+
+	tests := map[string]struct {
+		rules     []rules.LoadDetectionRule
+		strategy  rules.LoadStrategy
+		wantLevel rules.LoadLevel
+	}{
+		"should pick the highest level with max": {
+			rules:     []rules.LoadDetectionRule{deployments, containers, flows},
+			strategy:  rules.LoadStrategyMax,
+			wantLevel: rules.LoadLevelHigh,
+		},
+		"should default to max": {
+			rules:     []rules.LoadDetectionRule{deployments, flows},
+			wantLevel: rules.LoadLevelMedium,
+		},
+		"should pick the level with the most weight with weighted_vote": {
+			rules:     []rules.LoadDetectionRule{containers, deployments, heavyFlows},
+			strategy:  rules.LoadStrategyWeightedVote,
+			wantLevel: rules.LoadLevelMedium,
+		},
+		"should break vote ties towards the higher level": {
+			rules:     []rules.LoadDetectionRule{deployments, containers},
+			strategy:  rules.LoadStrategyWeightedVote,
+			wantLevel: rules.LoadLevelHigh,
+		},
+		"should ignore rules without metrics": {
+			rules:     []rules.LoadDetectionRule{missing, deployments},
+			strategy:  rules.LoadStrategyWeightedVote,
+			wantLevel: rules.LoadLevelLow,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			level, detection, err := NewDetector(tt.rules, tt.strategy).Detect(metrics)
+			if err != nil {
+				t.Fatalf("Detect() error = %v", err)
+			}
+			if level != tt.wantLevel {
+				t.Errorf("Detect() level = %v, want %v", level, tt.wantLevel)
+			}
+			if len(detection.Rules) != len(tt.rules) {
+				t.Errorf("Detect() returned %d verdicts, want %d", len(detection.Rules), len(tt.rules))
+			}
+		})
+	}
+}
+
+func TestDetectVerdicts(t *testing.T) {
+	metrics := parser.MetricsData{
+		"containers": &parser.Metric{Name: "containers", Values: []parser.MetricValue{{Value: 0, Labels: map[string]string{}}}},
+	}
+	rule := rules.LoadDetectionRule{
+		DisplayName: "cluster_volume",
+		Metrics:     []rules.LoadDetectionMetric{{Source: "containers", Weight: 1}},
+		Thresholds: []rules.LoadDetectionThreshold{
+			{Level: rules.LoadLevelLow, MaxValue: bound(0)},
+			{Level: rules.LoadLevelHigh, MinValue: bound(0)},
+		},
+	}
+
+	level, detection, err := DetectWithOverride(metrics, NewDetector([]rules.LoadDetectionRule{rule}, ""), rules.LoadLevelHigh)
+	if err != nil {
+		t.Fatalf("DetectWithOverride() error = %v", err)
+	}
+	if level != rules.LoadLevelHigh || !detection.Overridden {
+		t.Errorf("DetectWithOverride() = %v (overridden %v), want overridden high", level, detection.Overridden)
+	}
+
+	// A zero bound is a real bound, not "unset"
+	verdict := detection.Rules[0]
+	if verdict.Level != rules.LoadLevelHigh || verdict.Threshold == nil || verdict.Threshold.MinValue == nil {
+		t.Errorf("verdict = %+v, want high from the second threshold", verdict)
+	}
+	if verdict.RuleName != "cluster_volume" || verdict.Weight != 1 || verdict.Score != 0 {
+		t.Errorf("verdict = %+v, want cluster_volume with weight 1 and score 0", verdict)
+	}
+	if detection.Strategy != rules.LoadStrategyMax {
+		t.Errorf("strategy = %v, want max", detection.Strategy)
+	}
+}
+
+func TestDetectMetricInputs(t *testing.T) {
+	metrics := parser.MetricsData{
+		"containers": &parser.Metric{Name: "containers", Values: []parser.MetricValue{
+			{Value: 150, Labels: map[string]string{"node": "a"}},
+			{Value: 50, Labels: map[string]string{"node": "b"}},
+		}},
+		"pods": &parser.Metric{Name: "pods", Values: []parser.MetricValue{{Value: 100, Labels: map[string]string{}}}},
+	}
+	rule := rules.LoadDetectionRule{
+		DisplayName: "cluster_volume",
+		Metrics: []rules.LoadDetectionMetric{
+			{Name: "containers", Source: "containers", Weight: 1.0},
+			{Name: "pods", Source: "pods", Weight: 0.5},
+			{Name: "nodes", Source: "nodes", Weight: 2.0},
+		},
+		Thresholds: []rules.LoadDetectionThreshold{{Level: rules.LoadLevelMedium}},
+	}
+
+	_, detection, err := NewDetector([]rules.LoadDetectionRule{rule}, "").Detect(metrics)
+	if err != nil {
+		t.Fatalf("Detect() error = %v", err)
+	}
+	verdict := detection.Rules[0]
+
+	// (200 × 1.0 + 100 × 0.5) / 1.5; the missing nodes metric does not count
+	want := []rules.LoadMetricInput{
+		{Name: "containers", Source: "containers", Found: true, Value: 200, Weight: 1.0, Contribution: 200 / 1.5},
+		{Name: "pods", Source: "pods", Found: true, Value: 100, Weight: 0.5, Contribution: 50 / 1.5},
+		{Name: "nodes", Source: "nodes", Weight: 2.0},
+	}
+	if len(verdict.Metrics) != len(want) {
+		t.Fatalf("Detect() got %d metric inputs, want %d", len(verdict.Metrics), len(want))
+	}
+	sum := 0.0
+	for i, input := range verdict.Metrics {
+		if input != want[i] {
+			t.Errorf("metric input %d = %+v, want %+v", i, input, want[i])
+		}
+		sum += input.Contribution
+	}
+	if math.Abs(sum-verdict.Score) > 1e-9 {
+		t.Errorf("contributions add up to %v, want the score %v", sum, verdict.Score)
+	}
+	if detection.Detected != rules.LoadLevelMedium {
+		t.Errorf("Detected = %v, want medium", detection.Detected)
+	}
+}
+
+func TestDetectCustomTiers(t *testing.T) {
+	tiers := rules.LoadDetectionRule{
+		DisplayName: "pods",
+		Metrics:     []rules.LoadDetectionMetric{{Name: "pods", Source: "pods", Weight: 1}},
+		Thresholds: []rules.LoadDetectionThreshold{
+			{Level: "xlarge", MinValue: bound(5000)},
+			{Level: "large", MinValue: bound(500), MaxValue: bound(5000)},
+			{Level: "small", MinValue: bound(20), MaxValue: bound(500)},
+			{Level: "tiny", MaxValue: bound(20)},
+		},
+	}
+	coarse := rules.LoadDetectionRule{
+		DisplayName: "deployments",
+		Metrics:     []rules.LoadDetectionMetric{{Name: "deployments", Source: "deployments", Weight: 1}},
+		Levels:      []rules.LoadLevel{"small", "large"},
+		Thresholds: []rules.LoadDetectionThreshold{
+			{Level: "large", MinValue: bound(100)},
+		},
+	}
+	pods := func(v float64) parser.MetricsData {
+		return parser.MetricsData{"pods": &parser.Metric{Name: "pods", Values: []parser.MetricValue{{Value: v, Labels: map[string]string{}}}}}
+	}
+
+	tests := map[string]struct {
+		metrics   parser.MetricsData
+		override  rules.LoadLevel
+		wantLevel rules.LoadLevel
+		wantError bool
+	}{
+		"should detect the lowest tier": {
+			metrics:   pods(10),
+			wantLevel: "tiny",
+		},
+		"should detect the highest tier": {
+			metrics:   pods(50000),
+			wantLevel: "xlarge",
+		},
+		"should take the higher tier of two rules": {
+			metrics: parser.MetricsData{
+				"pods":        pods(100)["pods"],
+				"deployments": &parser.Metric{Name: "deployments", Values: []parser.MetricValue{{Value: 200, Labels: map[string]string{}}}},
+			},
+			wantLevel: "large",
+		},
+		"should default to the middle tier without medium": {
+			metrics:   parser.MetricsData{},
+			wantLevel: "small",
+		},
+		"should accept a declared tier as override": {
+			metrics:   pods(10),
+			override:  "xlarge",
+			wantLevel: "xlarge",
+		},
+		"should reject an undeclared override": {
+			metrics:   pods(10),
+			override:  rules.LoadLevelHigh,
+			wantError: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			detector := NewDetector([]rules.LoadDetectionRule{tiers, coarse}, rules.LoadStrategyMax)
+			level, _, err := DetectWithOverride(tt.metrics, detector, tt.override)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("DetectWithOverride() error = %v, wantError %v", err, tt.wantError)
+			}
+			if !tt.wantError && level != tt.wantLevel {
+				t.Errorf("DetectWithOverride() level = %v, want %v", level, tt.wantLevel)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package parser
+
+import (
+	"sort"
+	"strings"
+)
+
+// familySuffixes are the sample name suffixes of histogram, summary and
+// OpenMetrics counter families
+var familySuffixes = []string{"_bucket", "_sum", "_count", "_total", "_created"}
+
+// boundedLabels are labels whose values are fixed by the metric definition
+// rather than by what is observed, so they never explode
+var boundedLabels = map[string]bool{"le": true, "quantile": true}
+
+// FamilyCardinality is the number of series of a metric family and of distinct
+// values of each of its labels
+type FamilyCardinality struct {
+	Family      string
+	Series      int
+	LabelValues map[string]int // Excluding le and quantile
+}
+
+// FamilyOf returns the family a series name belongs to: the name itself if it
+// has a # TYPE, or the typed family it is a _bucket, _sum, _count, _total or
+// _created series of
+func (md MetricsData) FamilyOf(name string) string {
+	if metric, ok := md[name]; ok && metric.Type != "" {
+		return name
+	}
+	for _, suffix := range familySuffixes {
+		base, found := strings.CutSuffix(name, suffix)
+		if !found {
+			continue
+		}
+		if metric, ok := md[base]; ok && metric.Type != "" {
+			return base
+		}
+	}
+	return name
+}
+
+// Cardinality counts the series of every metric family of the scrape and the
+// distinct values of their labels, sorted by series count, highest first
+func (md MetricsData) Cardinality() []FamilyCardinality {
+	byFamily := make(map[string]*FamilyCardinality)
+	values := make(map[string]map[string]map[string]bool) // family -> label -> values
+	for name, metric := range md {
+		if len(metric.Values) == 0 {
+			continue
+		}
+		family := md.FamilyOf(name)
+		fc := byFamily[family]
+		if fc == nil {
+			fc = &FamilyCardinality{Family: family, LabelValues: make(map[string]int)}
+			byFamily[family] = fc
+			values[family] = make(map[string]map[string]bool)
+		}
+		fc.Series += len(metric.Values)
+		for _, v := range metric.Values {
+			for label, value := range v.Labels {
+				if boundedLabels[label] {
+					continue
+				}
+				if values[family][label] == nil {
+					values[family][label] = make(map[string]bool)
+				}
+				values[family][label][value] = true
+			}
+		}
+	}
+
+	result := make([]FamilyCardinality, 0, len(byFamily))
+	for family, fc := range byFamily {
+		for label, seen := range values[family] {
+			fc.LabelValues[label] = len(seen)
+		}
+		result = append(result, *fc)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Series != result[j].Series {
+			return result[i].Series > result[j].Series
+		}
+		return result[i].Family < result[j].Family
+	})
+	return result
+}
+
+// CardinalityOf returns the cardinality of one metric family; ok is false
+// when the scrape has no series of it
+func (md MetricsData) CardinalityOf(family string) (FamilyCardinality, bool) {
+	for _, fc := range md.Cardinality() {
+		if fc.Family == family {
+			return fc, true
+		}
+	}
+	return FamilyCardinality{}, false
+}
+
+// TopLabels returns the labels with the most distinct values, at most n, ties by name
+func (fc FamilyCardinality) TopLabels(n int) []string {
+	labels := make([]string, 0, len(fc.LabelValues))
+	for label := range fc.LabelValues {
+		labels = append(labels, label)
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		if fc.LabelValues[labels[i]] != fc.LabelValues[labels[j]] {
+			return fc.LabelValues[labels[i]] > fc.LabelValues[labels[j]]
+		}
+		return labels[i] < labels[j]
+	})
+	if len(labels) > n {
+		labels = labels[:n]
+	}
+	return labels
+}
@@ -0,0 +1,80 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCardinality(t *testing.T) {
+	metrics, err := ParseReader(strings.NewReader(`# TYPE requests_total counter
+requests_total{pod="a",code="200"} 1
+requests_total{pod="b",code="200"} 1
+requests_total{pod="c",code="500"} 1
+# TYPE latency_seconds histogram
+latency_seconds_bucket{pod="a",le="1"} 1
+latency_seconds_bucket{pod="a",le="+Inf"} 1
+latency_seconds_sum{pod="a"} 1
+latency_seconds_count{pod="a"} 1
+go_goroutines 10
+`))
+	if err != nil {
+		t.Fatalf("ParseReader() error = %v", err)
+	}
+
+	families := metrics.Cardinality()
+	if len(families) != 3 {
+		t.Fatalf("Cardinality() got %d families, want 3: %+v", len(families), families)
+	}
+
+	tests := map[string]struct {
+		family     string
+		wantSeries int
+		wantLabels map[string]int
+		wantTop    []string
+	}{
+		"should count series and distinct label values": {
+			family:     "requests_total",
+			wantSeries: 3,
+			wantLabels: map[string]int{"pod": 3, "code": 2},
+			wantTop:    []string{"pod", "code"},
+		},
+		"should group histogram series into their family without le": {
+			family:     "latency_seconds",
+			wantSeries: 4,
+			wantLabels: map[string]int{"pod": 1},
+			wantTop:    []string{"pod"},
+		},
+		"should count unlabeled metrics": {
+			family:     "go_goroutines",
+			wantSeries: 1,
+			wantLabels: map[string]int{},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			fc, ok := metrics.CardinalityOf(tt.family)
+			if !ok {
+				t.Fatalf("CardinalityOf(%q) not found", tt.family)
+			}
+			if fc.Series != tt.wantSeries {
+				t.Errorf("CardinalityOf(%q) series = %d, want %d", tt.family, fc.Series, tt.wantSeries)
+			}
+			if len(fc.LabelValues) != len(tt.wantLabels) {
+				t.Errorf("CardinalityOf(%q) labels = %v, want %v", tt.family, fc.LabelValues, tt.wantLabels)
+			}
+			for label, want := range tt.wantLabels {
+				if fc.LabelValues[label] != want {
+					t.Errorf("CardinalityOf(%q) label %s = %d, want %d", tt.family, label, fc.LabelValues[label], want)
+				}
+			}
+			if top := fc.TopLabels(5); strings.Join(top, ",") != strings.Join(tt.wantTop, ",") {
+				t.Errorf("TopLabels() = %v, want %v", top, tt.wantTop)
+			}
+		})
+	}
+
+	if families[0].Family != "latency_seconds" {
+		t.Errorf("Cardinality() first family = %s, want the largest latency_seconds", families[0].Family)
+	}
+}
@@ -0,0 +1,57 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/binary"
+	"regexp"
+)
+
+// Format identifies a metrics exposition format
+type Format string
+
+const (
+	FormatText        Format = "text"        // Classic Prometheus text format (text/plain; version=0.0.4)
+	FormatOpenMetrics Format = "openmetrics" // OpenMetrics text (application/openmetrics-text)
+	FormatProtobuf    Format = "protobuf"    // Length-delimited io.prometheus.client.MetricFamily messages
+)
+
+var (
+	metricNameRegex = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+	// OpenMetrics-only constructs: the EOF marker and UNIT metadata
+	openMetricsMarkerRegex = regexp.MustCompile(`(?m)^# (EOF|UNIT )`)
+)
+
+// DetectFormat guesses the exposition format of raw scrape content
+func DetectFormat(data []byte) Format {
+	if looksLikeProtobuf(data) {
+		return FormatProtobuf
+	}
+	if openMetricsMarkerRegex.Match(data) {
+		return FormatOpenMetrics
+	}
+	return FormatText
+}
+
+// looksLikeProtobuf checks whether data starts with a length-delimited
+// MetricFamily whose first field is a valid metric name (field 1, wire type 2).
+func looksLikeProtobuf(data []byte) bool {
+	if len(data) == 0 || bytes.HasPrefix(data, []byte("#")) {
+		return false
+	}
+
+	msgLen, n := binary.Uvarint(data)
+	if n <= 0 || msgLen == 0 || uint64(len(data)-n) < msgLen {
+		return false
+	}
+	msg := data[n : n+int(msgLen)]
+
+	if len(msg) < 2 || msg[0] != 0x0a {
+		return false
+	}
+	nameLen, m := binary.Uvarint(msg[1:])
+	if m <= 0 || nameLen == 0 || uint64(len(msg)-1-m) < nameLen {
+		return false
+	}
+	name := msg[1+m : 1+m+int(nameLen)]
+	return metricNameRegex.Match(name)
+}
@@ -0,0 +1,197 @@
+package parser
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/proto"
+)
+
+const openMetricsSample = `# TYPE rox_sensor_events counter
+# UNIT rox_sensor_events events
+# HELP rox_sensor_events Number of events
+rox_sensor_events_total{type="Pod"} 42 1700000000.5 # {trace_id="abc"} 1.0 1700000000
+rox_sensor_events_created{type="Pod"} 1690000000
+# TYPE queue_size gauge
+queue_size 7
+# EOF
+`
+
+func TestDetectFormat(t *testing.T) {
+	tests := map[string]struct {
+		data []byte
+		want Format
+	}{
+		"should detect classic text format": {
+			data: []byte("# TYPE foo gauge\nfoo 1\n"),
+			want: FormatText,
+		},
+		"should detect OpenMetrics by EOF marker": {
+			data: []byte(openMetricsSample),
+			want: FormatOpenMetrics,
+		},
+		"should detect delimited protobuf": {
+			data: encodeFamilies(t, &dto.MetricFamily{
+				Name:   proto.String("foo"),
+				Type:   dto.MetricType_GAUGE.Enum(),
+				Metric: []*dto.Metric{{Gauge: &dto.Gauge{Value: proto.Float64(1)}}},
+			}),
+			want: FormatProtobuf,
+		},
+		"should treat empty input as text": {
+			data: []byte{},
+			want: FormatText,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := DetectFormat(tt.data); got != tt.want {
+				t.Errorf("DetectFormat() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseReaderOpenMetrics(t *testing.T) {
+	metrics, err := ParseReader(strings.NewReader(openMetricsSample))
+	if err != nil {
+		t.Fatalf("ParseReader() error = %v", err)
+	}
+
+	family, exists := metrics.GetMetric("rox_sensor_events")
+	if !exists {
+		t.Fatal("ParseReader() family metadata missing")
+	}
+	if family.Type != "counter" || family.Unit != "events" || family.Help != "Number of events" {
+		t.Errorf("ParseReader() family = %+v", family)
+	}
+
+	total, exists := metrics.GetMetric("rox_sensor_events_total")
+	if !exists || len(total.Values) != 1 {
+		t.Fatal("ParseReader() sample with timestamp and exemplar was dropped")
+	}
+	sample := total.Values[0]
+	if sample.Value != 42 || sample.Labels["type"] != "Pod" {
+		t.Errorf("ParseReader() sample = %+v", sample)
+	}
+	wantTimestamp := time.Unix(1700000000, 500000000)
+	if !sample.Timestamp.Equal(wantTimestamp) {
+		t.Errorf("ParseReader() timestamp = %v, want %v", sample.Timestamp, wantTimestamp)
+	}
+	if sample.Exemplar == nil || sample.Exemplar.Labels["trace_id"] != "abc" || sample.Exemplar.Value != 1 {
+		t.Errorf("ParseReader() exemplar = %+v", sample.Exemplar)
+	}
+
+	if _, exists := metrics.GetMetric("rox_sensor_events_created"); !exists {
+		t.Error("ParseReader() _created series missing")
+	}
+}
+
+func TestParseReaderOpenMetricsContentAfterEOF(t *testing.T) {
+	_, err := ParseReader(strings.NewReader("foo 1\n# EOF\nbar 2\n"))
+	if err == nil {
+		t.Error("ParseReader() expected error for content after # EOF")
+	}
+}
+
+func TestParseReaderTextTimestamp(t *testing.T) {
+	metrics, err := ParseReader(strings.NewReader("foo 1 1700000000123\n"))
+	if err != nil {
+		t.Fatalf("ParseReader() error = %v", err)
+	}
+	want := time.UnixMilli(1700000000123)
+	if got := metrics["foo"].Values[0].Timestamp; !got.Equal(want) {
+		t.Errorf("ParseReader() timestamp = %v, want %v", got, want)
+	}
+}
+
+func TestParseReaderProtobuf(t *testing.T) {
+	data := encodeFamilies(t,
+		&dto.MetricFamily{
+			Name: proto.String("requests_total"),
+			Help: proto.String("Total requests"),
+			Type: dto.MetricType_COUNTER.Enum(),
+			Metric: []*dto.Metric{{
+				Label:       []*dto.LabelPair{{Name: proto.String("code"), Value: proto.String("200")}},
+				Counter:     &dto.Counter{Value: proto.Float64(10)},
+				TimestampMs: proto.Int64(1700000000000),
+			}},
+		},
+		&dto.MetricFamily{
+			Name: proto.String("latency_seconds"),
+			Type: dto.MetricType_HISTOGRAM.Enum(),
+			Metric: []*dto.Metric{{
+				Histogram: &dto.Histogram{
+					SampleCount: proto.Uint64(10),
+					SampleSum:   proto.Float64(2.5),
+					Bucket: []*dto.Bucket{
+						{UpperBound: proto.Float64(0.1), CumulativeCount: proto.Uint64(6)},
+						{UpperBound: proto.Float64(1), CumulativeCount: proto.Uint64(9)},
+					},
+				},
+			}},
+		},
+		&dto.MetricFamily{
+			Name: proto.String("gc_duration_seconds"),
+			Type: dto.MetricType_SUMMARY.Enum(),
+			Metric: []*dto.Metric{{
+				Summary: &dto.Summary{
+					SampleCount: proto.Uint64(4),
+					SampleSum:   proto.Float64(0.4),
+					Quantile:    []*dto.Quantile{{Quantile: proto.Float64(0.5), Value: proto.Float64(0.09)}},
+				},
+			}},
+		},
+	)
+
+	metrics, err := ParseReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("ParseReader() error = %v", err)
+	}
+
+	counter := metrics["requests_total"]
+	if counter == nil || counter.Type != "counter" || counter.Help != "Total requests" {
+		t.Fatalf("ParseReader() counter = %+v", counter)
+	}
+	if counter.Values[0].Value != 10 || counter.Values[0].Labels["code"] != "200" {
+		t.Errorf("ParseReader() counter sample = %+v", counter.Values[0])
+	}
+	if !counter.Values[0].Timestamp.Equal(time.UnixMilli(1700000000000)) {
+		t.Errorf("ParseReader() counter timestamp = %v", counter.Values[0].Timestamp)
+	}
+
+	if bases := metrics.GetHistogramBaseNames(); len(bases) != 1 || bases[0] != "latency_seconds" {
+		t.Errorf("GetHistogramBaseNames() = %v, want [latency_seconds]", bases)
+	}
+	infCount, ok := metrics["latency_seconds_bucket"].GetHistogramInfBucketCount()
+	if !ok || infCount != 10 {
+		t.Errorf("ParseReader() implicit +Inf bucket = %v (found %v), want 10", infCount, ok)
+	}
+	if count, _ := metrics.GetHistogramCount("latency_seconds"); count != 10 {
+		t.Errorf("ParseReader() histogram count = %v, want 10", count)
+	}
+
+	summary := metrics["gc_duration_seconds"]
+	if summary == nil || summary.Type != "summary" || summary.Values[0].Labels["quantile"] != "0.5" {
+		t.Errorf("ParseReader() summary = %+v", summary)
+	}
+	if sum, _ := metrics.GetHistogramSum("gc_duration_seconds"); sum != 0.4 {
+		t.Errorf("ParseReader() summary sum = %v, want 0.4", sum)
+	}
+}
+
+func encodeFamilies(t *testing.T, families ...*dto.MetricFamily) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	for _, family := range families {
+		if _, err := protodelim.MarshalTo(&buf, family); err != nil {
+			t.Fatalf("protodelim.MarshalTo() error = %v", err)
+		}
+	}
+	return buf.Bytes()
+}
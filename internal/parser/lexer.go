@@ -0,0 +1,346 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseError describes a malformed line in a text exposition
+type ParseError struct {
+	Line   int    // 1-based line number
+	Column int    // 1-based byte offset within the line, 0 if unknown
+	Msg    string // What was wrong
+}
+
+func (e *ParseError) Error() string {
+	if e.Column > 0 {
+		return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Msg)
+	}
+	return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+}
+
+// sampleLexer tokenizes a single sample line of the text exposition format:
+//
+//	metric_name{label="value",...} value [timestamp] [# {label="value"} value [timestamp]]
+//
+// Label values are unescaped following the exposition format rules
+// (\\, \" and \n), so they may contain commas, equals signs and braces.
+type sampleLexer struct {
+	line string
+	pos  int
+}
+
+// lexError is returned by the lexer and converted to a ParseError with the line number attached
+type lexError struct {
+	pos int
+	msg string
+}
+
+func (e *lexError) Error() string {
+	return e.msg
+}
+
+func (l *sampleLexer) errorf(format string, args ...interface{}) *lexError {
+	return &lexError{pos: l.pos, msg: fmt.Sprintf(format, args...)}
+}
+
+// parseSampleLine parses a complete sample line into its metric name and value
+func parseSampleLine(line string, openMetrics bool) (string, MetricValue, error) {
+	l := &sampleLexer{line: line}
+	var sample MetricValue
+
+	name := l.readMetricName()
+	if name == "" {
+		return "", sample, l.errorf("invalid metric name")
+	}
+
+	labels := make(map[string]string)
+	if l.peek() == '{' {
+		var err error
+		if labels, err = l.readLabels(); err != nil {
+			return "", sample, err
+		}
+	}
+	sample.Labels = labels
+
+	if !l.skipSpaces() {
+		return "", sample, l.errorf("expected whitespace before value")
+	}
+
+	valueToken := l.readToken()
+	value, err := parseSampleValue(valueToken)
+	if err != nil {
+		return "", sample, &lexError{pos: l.pos - len(valueToken), msg: fmt.Sprintf("invalid value %q", valueToken)}
+	}
+	sample.Value = value
+
+	l.skipSpaces()
+	if !l.done() && l.peek() != '#' {
+		tsToken := l.readToken()
+		timestamp, err := parseTimestamp(tsToken, openMetrics)
+		if err != nil {
+			return "", sample, &lexError{pos: l.pos - len(tsToken), msg: fmt.Sprintf("invalid timestamp %q", tsToken)}
+		}
+		sample.Timestamp = timestamp
+		l.skipSpaces()
+	}
+
+	if !l.done() && l.peek() == '#' {
+		exemplar, err := l.readExemplar()
+		if err != nil {
+			return "", sample, err
+		}
+		sample.Exemplar = exemplar
+	}
+
+	if !l.done() {
+		return "", sample, l.errorf("unexpected trailing content %q", l.line[l.pos:])
+	}
+
+	return name, sample, nil
+}
+
+// parseLabels parses the inside of a label set, like: key1="value1",key2="value2"
+func parseLabels(labelsStr string) (map[string]string, error) {
+	l := &sampleLexer{line: "{" + labelsStr + "}"}
+	labels, err := l.readLabels()
+	if err != nil {
+		return nil, err
+	}
+	if l.skipSpaces(); !l.done() {
+		return nil, l.errorf("unexpected content after label set")
+	}
+	return labels, nil
+}
+
+// ParseLabelSelector parses an equality label selector such as
+// {component="enricher",namespace="stackrox"}. The braces are optional and an
+// empty selector matches every series.
+func ParseLabelSelector(selector string) (map[string]string, error) {
+	selector = strings.TrimSpace(selector)
+	if strings.HasPrefix(selector, "{") {
+		if !strings.HasSuffix(selector, "}") {
+			return nil, fmt.Errorf("invalid label selector %s: missing closing '}'", selector)
+		}
+		selector = selector[1 : len(selector)-1]
+	}
+	labels, err := parseLabels(selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label selector %s: %w", selector, err)
+	}
+	return labels, nil
+}
+
+// readExemplar parses "# {labels} value [timestamp]"; exemplar timestamps are always in seconds
+func (l *sampleLexer) readExemplar() (*Exemplar, error) {
+	l.pos++ // '#'
+	l.skipSpaces()
+	if l.peek() != '{' {
+		return nil, l.errorf("expected '{' to start exemplar labels")
+	}
+	labels, err := l.readLabels()
+	if err != nil {
+		return nil, err
+	}
+	l.skipSpaces()
+
+	valueToken := l.readToken()
+	value, err := parseSampleValue(valueToken)
+	if err != nil {
+		return nil, &lexError{pos: l.pos - len(valueToken), msg: fmt.Sprintf("invalid exemplar value %q", valueToken)}
+	}
+	exemplar := &Exemplar{Labels: labels, Value: value}
+
+	l.skipSpaces()
+	if !l.done() {
+		tsToken := l.readToken()
+		timestamp, err := parseTimestamp(tsToken, true)
+		if err != nil {
+			return nil, &lexError{pos: l.pos - len(tsToken), msg: fmt.Sprintf("invalid exemplar timestamp %q", tsToken)}
+		}
+		exemplar.Timestamp = timestamp
+		l.skipSpaces()
+	}
+	return exemplar, nil
+}
+
+// readLabels parses a brace-enclosed label set, allowing a trailing comma
+func (l *sampleLexer) readLabels() (map[string]string, error) {
+	labels := make(map[string]string)
+	l.pos++ // '{'
+
+	for {
+		l.skipSpaces()
+		if l.done() {
+			return nil, l.errorf("unterminated label set")
+		}
+		if l.peek() == '}' {
+			l.pos++
+			return labels, nil
+		}
+
+		key := l.readLabelName()
+		if key == "" {
+			return nil, l.errorf("invalid label name")
+		}
+		if _, exists := labels[key]; exists {
+			return nil, l.errorf("duplicate label %q", key)
+		}
+
+		l.skipSpaces()
+		if l.peek() != '=' {
+			return nil, l.errorf("expected '=' after label %q", key)
+		}
+		l.pos++
+		l.skipSpaces()
+
+		value, err := l.readQuoted()
+		if err != nil {
+			return nil, err
+		}
+		labels[key] = value
+
+		l.skipSpaces()
+		switch l.peek() {
+		case ',':
+			l.pos++
+		case '}':
+		default:
+			return nil, l.errorf("expected ',' or '}' after value of label %q", key)
+		}
+	}
+}
+
+// readQuoted parses a double-quoted label value and resolves its escape sequences
+func (l *sampleLexer) readQuoted() (string, error) {
+	if l.peek() != '"' {
+		return "", l.errorf("expected '\"' to start label value")
+	}
+	start := l.pos
+	l.pos++
+
+	var value strings.Builder
+	for !l.done() {
+		c := l.line[l.pos]
+		switch c {
+		case '"':
+			l.pos++
+			return value.String(), nil
+		case '\\':
+			if l.pos+1 >= len(l.line) {
+				return "", l.errorf("unterminated escape sequence")
+			}
+			l.pos++
+			switch l.line[l.pos] {
+			case '\\':
+				value.WriteByte('\\')
+			case '"':
+				value.WriteByte('"')
+			case 'n':
+				value.WriteByte('\n')
+			default:
+				// Unknown escapes are kept verbatim, as the Prometheus parser does
+				value.WriteByte('\\')
+				value.WriteByte(l.line[l.pos])
+			}
+		default:
+			value.WriteByte(c)
+		}
+		l.pos++
+	}
+	return "", &lexError{pos: start, msg: "unterminated label value"}
+}
+
+func (l *sampleLexer) readMetricName() string {
+	start := l.pos
+	for !l.done() && isNameChar(l.line[l.pos], l.pos == start, true) {
+		l.pos++
+	}
+	return l.line[start:l.pos]
+}
+
+func (l *sampleLexer) readLabelName() string {
+	start := l.pos
+	for !l.done() && isNameChar(l.line[l.pos], l.pos == start, false) {
+		l.pos++
+	}
+	return l.line[start:l.pos]
+}
+
+// readToken reads up to the next whitespace
+func (l *sampleLexer) readToken() string {
+	start := l.pos
+	for !l.done() && !isSpace(l.line[l.pos]) {
+		l.pos++
+	}
+	return l.line[start:l.pos]
+}
+
+// skipSpaces advances past whitespace and reports whether any was skipped
+func (l *sampleLexer) skipSpaces() bool {
+	start := l.pos
+	for !l.done() && isSpace(l.line[l.pos]) {
+		l.pos++
+	}
+	return l.pos > start
+}
+
+func (l *sampleLexer) peek() byte {
+	if l.done() {
+		return 0
+	}
+	return l.line[l.pos]
+}
+
+func (l *sampleLexer) done() bool {
+	return l.pos >= len(l.line)
+}
+
+func isNameChar(c byte, first, allowColon bool) bool {
+	switch {
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c == '_':
+		return true
+	case c == ':':
+		return allowColon
+	case c >= '0' && c <= '9':
+		return !first
+	}
+	return false
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t'
+}
+
+// parseSampleValue parses a sample value, including NaN and signed Inf
+func parseSampleValue(token string) (float64, error) {
+	if token == "" {
+		return 0, fmt.Errorf("missing value")
+	}
+	return strconv.ParseFloat(token, 64)
+}
+
+// unescapeHelp resolves the \\ and \n escapes allowed in HELP text
+func unescapeHelp(help string) string {
+	if !strings.Contains(help, `\`) {
+		return help
+	}
+	var out strings.Builder
+	for i := 0; i < len(help); i++ {
+		if help[i] == '\\' && i+1 < len(help) {
+			switch help[i+1] {
+			case '\\':
+				out.WriteByte('\\')
+				i++
+				continue
+			case 'n':
+				out.WriteByte('\n')
+				i++
+				continue
+			}
+		}
+		out.WriteByte(help[i])
+	}
+	return out.String()
+}
@@ -2,24 +2,54 @@ package parser
 
 import (
 	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
 	"io"
+	"math"
 	"os"
 	"regexp"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
+// maxLineSize is the longest exposition line accepted; label-heavy series can exceed bufio's default
+const maxLineSize = 1024 * 1024
+
 // HistogramBucket represents a histogram bucket
 type HistogramBucket struct {
 	Le    float64 // Less than or equal to value
 	Count float64 // Cumulative count
 }
 
+// SummaryQuantile is one precomputed quantile of a summary series
+type SummaryQuantile struct {
+	Quantile float64 // Between 0 and 1
+	Value    float64
+}
+
+// SummarySeries holds the quantiles of one label set of a summary (all labels except "quantile")
+type SummarySeries struct {
+	Key       string // Series key of the label set, matching its _sum and _count series
+	Labels    map[string]string
+	Quantiles []SummaryQuantile // Sorted by quantile
+}
+
 // MetricValue represents a single metric data point
 type MetricValue struct {
-	Labels map[string]string
-	Value  float64
+	Labels    map[string]string
+	Value     float64
+	Timestamp time.Time // Zero when the sample carries no timestamp
+	Exemplar  *Exemplar // Optional OpenMetrics/protobuf exemplar
+}
+
+// Exemplar is an example observation attached to a sample
+type Exemplar struct {
+	Labels    map[string]string
+	Value     float64
+	Timestamp time.Time
 }
 
 // Metric represents a Prometheus metric with metadata
@@ -27,6 +57,7 @@ type Metric struct {
 	Name   string
 	Help   string
 	Type   string
+	Unit   string // Declared by OpenMetrics "# UNIT" or the protobuf unit field
 	Values []MetricValue
 }
 
@@ -34,12 +65,17 @@ type Metric struct {
 type MetricsData map[string]*Metric
 
 var (
-	helpRegex         = regexp.MustCompile(`^# HELP\s+(\S+)\s+(.*)$`)
-	typeRegex         = regexp.MustCompile(`^# TYPE\s+(\S+)\s+(\S+)$`)
-	metricRegex       = regexp.MustCompile(`^([a-zA-Z_][a-zA-Z0-9_:]*)\{([^}]*)\}\s+(.+)$`)
-	simpleMetricRegex = regexp.MustCompile(`^([a-zA-Z_][a-zA-Z0-9_:]*)\s+(.+)$`)
+	helpRegex = regexp.MustCompile(`^# HELP\s+(\S+)\s+(.*)$`)
+	typeRegex = regexp.MustCompile(`^# TYPE\s+(\S+)\s+(\S+)$`)
+	unitRegex = regexp.MustCompile(`^# UNIT\s+(\S+)\s*(\S*)$`)
 )
 
+// ParseOptions controls how malformed input is handled
+type ParseOptions struct {
+	// Strict fails parsing on the first malformed line instead of skipping it
+	Strict bool
+}
+
 // ParseFile parses a Prometheus metrics file.
 func ParseFile(filepath string) (MetricsData, error) {
 	file, err := os.Open(filepath)
@@ -51,104 +87,132 @@ func ParseFile(filepath string) (MetricsData, error) {
 }
 
 // ParseReader parses Prometheus metrics from a reader.
+// The exposition format (classic text, OpenMetrics text or delimited protobuf)
+// is detected from the content. Malformed lines are skipped.
 func ParseReader(reader io.Reader) (MetricsData, error) {
+	metrics, _, err := ParseReaderWithOptions(reader, ParseOptions{})
+	return metrics, err
+}
+
+// ParseReaderWithOptions parses Prometheus metrics from a reader. In lenient mode
+// malformed lines are skipped and returned as ParseErrors; in strict mode the
+// first malformed line fails parsing.
+func ParseReaderWithOptions(reader io.Reader, opts ParseOptions) (MetricsData, []*ParseError, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch DetectFormat(data) {
+	case FormatProtobuf:
+		metrics, err := parseProtobuf(data)
+		return metrics, nil, err
+	case FormatOpenMetrics:
+		return parseText(data, true, opts)
+	default:
+		return parseText(data, false, opts)
+	}
+}
+
+// parseText parses the classic Prometheus text format or, with openMetrics set,
+// OpenMetrics text. The two differ in timestamp units (milliseconds vs seconds)
+// and in OpenMetrics requiring nothing to follow "# EOF".
+func parseText(data []byte, openMetrics bool, opts ParseOptions) (MetricsData, []*ParseError, error) {
 	metrics := make(MetricsData)
-	scanner := bufio.NewScanner(reader)
+	var skipped []*ParseError
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
 
+	lineNumber := 0
+	seenEOF := false
 	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
+		lineNumber++
+		raw := scanner.Text()
+		line := strings.TrimSpace(raw)
 
-		if line == "" || strings.HasPrefix(line, "# EOF") {
+		if line == "" {
 			continue
 		}
 
-		// Parse HELP
-		if matches := helpRegex.FindStringSubmatch(line); matches != nil {
-			metricName := matches[1]
-			helpText := matches[2]
-			if metrics[metricName] == nil {
-				metrics[metricName] = &Metric{Name: metricName}
-			}
-			metrics[metricName].Help = helpText
-			continue
+		if seenEOF {
+			return nil, nil, &ParseError{Line: lineNumber, Msg: "unexpected content after # EOF"}
 		}
 
-		// Parse TYPE
-		if matches := typeRegex.FindStringSubmatch(line); matches != nil {
-			metricName := matches[1]
-			metricType := matches[2]
-			if metrics[metricName] == nil {
-				metrics[metricName] = &Metric{Name: metricName}
-			}
-			metrics[metricName].Type = metricType
+		if line == "# EOF" {
+			seenEOF = true
 			continue
 		}
 
-		// Parse metric with labels
-		if matches := metricRegex.FindStringSubmatch(line); matches != nil {
-			metricName := matches[1]
-			labelsStr := matches[2]
-			valueStr := matches[3]
-
-			value, err := strconv.ParseFloat(valueStr, 64)
-			if err != nil {
-				continue // Skip non-numeric values
+		if strings.HasPrefix(line, "#") {
+			// Parse HELP
+			if matches := helpRegex.FindStringSubmatch(line); matches != nil {
+				metrics.getOrCreate(matches[1]).Help = unescapeHelp(matches[2])
+				continue
 			}
 
-			labels := parseLabels(labelsStr)
+			// Parse TYPE
+			if matches := typeRegex.FindStringSubmatch(line); matches != nil {
+				metrics.getOrCreate(matches[1]).Type = matches[2]
+				continue
+			}
 
-			if metrics[metricName] == nil {
-				metrics[metricName] = &Metric{Name: metricName}
+			// Parse UNIT (OpenMetrics)
+			if matches := unitRegex.FindStringSubmatch(line); matches != nil {
+				metrics.getOrCreate(matches[1]).Unit = matches[2]
 			}
-			metrics[metricName].Values = append(metrics[metricName].Values, MetricValue{
-				Labels: labels,
-				Value:  value,
-			})
+
+			// Any other comment is ignored
 			continue
 		}
 
-		// Parse simple metric (no labels)
-		if matches := simpleMetricRegex.FindStringSubmatch(line); matches != nil {
-			metricName := matches[1]
-			valueStr := matches[2]
-
-			value, err := strconv.ParseFloat(valueStr, 64)
-			if err != nil {
-				continue
+		name, sample, err := parseSampleLine(line, openMetrics)
+		if err != nil {
+			parseErr := &ParseError{Line: lineNumber, Msg: err.Error()}
+			var lexErr *lexError
+			if errors.As(err, &lexErr) {
+				// Report the column in the untrimmed line
+				parseErr.Column = lexErr.pos + len(raw) - len(strings.TrimLeft(raw, " \t")) + 1
 			}
-
-			if metrics[metricName] == nil {
-				metrics[metricName] = &Metric{Name: metricName}
+			if opts.Strict {
+				return nil, nil, parseErr
 			}
-			metrics[metricName].Values = append(metrics[metricName].Values, MetricValue{
-				Labels: make(map[string]string),
-				Value:  value,
-			})
+			skipped = append(skipped, parseErr)
+			continue
 		}
+		metric := metrics.getOrCreate(name)
+		metric.Values = append(metric.Values, sample)
 	}
 
-	return metrics, scanner.Err()
-}
-
-// parseLabels parses label string like: key1="value1",key2="value2"
-func parseLabels(labelsStr string) map[string]string {
-	labels := make(map[string]string)
-
-	if labelsStr == "" {
-		return labels
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("line %d: %w", lineNumber+1, err)
 	}
+	return metrics, skipped, nil
+}
 
-	pairs := strings.Split(labelsStr, ",")
-	for _, pair := range pairs {
-		parts := strings.SplitN(pair, "=", 2)
-		if len(parts) == 2 {
-			key := strings.TrimSpace(parts[0])
-			value := strings.Trim(strings.TrimSpace(parts[1]), `"`)
-			labels[key] = value
+// parseTimestamp parses a sample timestamp. OpenMetrics uses (fractional) seconds,
+// the classic text format uses integer milliseconds.
+func parseTimestamp(raw string, inSeconds bool) (time.Time, error) {
+	if inSeconds {
+		seconds, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return time.Time{}, err
 		}
+		whole, frac := math.Modf(seconds)
+		return time.Unix(int64(whole), int64(frac*1e9)), nil
 	}
+	millis, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.UnixMilli(millis), nil
+}
 
-	return labels
+// getOrCreate returns the metric with the given name, creating it if needed
+func (md MetricsData) getOrCreate(name string) *Metric {
+	if md[name] == nil {
+		md[name] = &Metric{Name: name}
+	}
+	return md[name]
 }
 
 // GetMetric retrieves a metric by name
@@ -176,6 +240,30 @@ func (m *Metric) GetValuesByLabel(labelKey string) map[string]float64 {
 	return result
 }
 
+// SelectValues returns the samples whose labels include every selector label with the same value
+func (m *Metric) SelectValues(selector map[string]string) []MetricValue {
+	if len(selector) == 0 {
+		return m.Values
+	}
+	var selected []MetricValue
+	for _, v := range m.Values {
+		if MatchesLabels(v.Labels, selector) {
+			selected = append(selected, v)
+		}
+	}
+	return selected
+}
+
+// MatchesLabels reports whether labels contains every selector label with the same value
+func MatchesLabels(labels, selector map[string]string) bool {
+	for key, want := range selector {
+		if labels[key] != want {
+			return false
+		}
+	}
+	return true
+}
+
 // SumValues sums all values of a metric
 func (m *Metric) SumValues() float64 {
 	sum := 0.0
@@ -285,7 +373,7 @@ func (md MetricsData) GetHistogramBaseNames() []string {
 
 	for metricName, metric := range md {
 		// Check if this is a histogram type metric
-		if metric.Type == "histogram" {
+		if metric.Type == "histogram" || metric.Type == "gaugehistogram" {
 			// Extract base name by removing _bucket, _sum, _count suffixes
 			baseName := metricName
 			if strings.HasSuffix(baseName, "_bucket") {
@@ -307,3 +395,54 @@ func (md MetricsData) GetHistogramBaseNames() []string {
 	sort.Strings(result)
 	return result
 }
+
+// GetSummarySeries splits the quantile samples of a summary metric into series
+// by their labels excluding "quantile", sorted by series key
+func (m *Metric) GetSummarySeries() []SummarySeries {
+	byKey := make(map[string]*SummarySeries)
+	var keys []string
+	for _, v := range m.Values {
+		qStr, exists := v.Labels["quantile"]
+		if !exists {
+			continue
+		}
+		q, err := strconv.ParseFloat(qStr, 64)
+		if err != nil {
+			continue
+		}
+		key := seriesKeyWithout(v.Labels, "quantile")
+		s, seen := byKey[key]
+		if !seen {
+			labels := copyLabels(v.Labels)
+			delete(labels, "quantile")
+			s = &SummarySeries{Key: key, Labels: labels}
+			byKey[key] = s
+			keys = append(keys, key)
+		}
+		s.Quantiles = append(s.Quantiles, SummaryQuantile{Quantile: q, Value: v.Value})
+	}
+
+	sort.Strings(keys)
+	series := make([]SummarySeries, 0, len(keys))
+	for _, key := range keys {
+		s := byKey[key]
+		sort.Slice(s.Quantiles, func(i, j int) bool {
+			return s.Quantiles[i].Quantile < s.Quantiles[j].Quantile
+		})
+		series = append(series, *s)
+	}
+	return series
+}
+
+// GetSummaryBaseNames returns a sorted list of summary metric names. Their _sum and
+// _count series are read with GetHistogramSum and GetHistogramCount.
+func (md MetricsData) GetSummaryBaseNames() []string {
+	var result []string
+	for metricName, metric := range md {
+		if metric.Type == "summary" {
+			result = append(result, metricName)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
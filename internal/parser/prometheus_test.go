@@ -3,6 +3,7 @@ package parser
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -88,9 +89,10 @@ func TestParseFile(t *testing.T) {
 
 func TestParseLabels(t *testing.T) {
 	tests := map[string]struct {
-		input    string
-		want     map[string]string
-		wantKeys []string
+		input     string
+		want      map[string]string
+		wantKeys  []string
+		wantError bool
 	}{
 		"should parse single label correctly": {
 			input:    `key1="value1"`,
@@ -112,11 +114,44 @@ func TestParseLabels(t *testing.T) {
 			wantKeys: []string{"key1", "key2"},
 			want:     map[string]string{"key1": "value1", "key2": "value2"},
 		},
+		"should keep commas, equals signs and braces inside values": {
+			input: `path="/v1/a,b={c}",build="go=1.24"`,
+			want:  map[string]string{"path": "/v1/a,b={c}", "build": "go=1.24"},
+		},
+		"should unescape quotes, backslashes and newlines": {
+			input: `msg="say \"hi\"\n",dir="C:\\tmp"`,
+			want:  map[string]string{"msg": "say \"hi\"\n", "dir": `C:\tmp`},
+		},
+		"should allow a trailing comma": {
+			input: `key1="value1",`,
+			want:  map[string]string{"key1": "value1"},
+		},
+		"should reject unterminated values": {
+			input:     `key1="value1`,
+			wantError: true,
+		},
+		"should reject unquoted values": {
+			input:     `key1=value1`,
+			wantError: true,
+		},
+		"should reject duplicate labels": {
+			input:     `key1="a",key1="b"`,
+			wantError: true,
+		},
 	}
 
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
-			got := parseLabels(tt.input)
+			got, err := parseLabels(tt.input)
+			if tt.wantError {
+				if err == nil {
+					t.Errorf("parseLabels() expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseLabels() error = %v", err)
+			}
 
 			if len(got) != len(tt.want) {
 				t.Errorf("parseLabels() got %d keys, want %d", len(got), len(tt.want))
@@ -297,3 +332,96 @@ func TestGetValuesByLabel(t *testing.T) {
 		})
 	}
 }
+
+func TestParseReaderWithOptions(t *testing.T) {
+	input := strings.Join([]string{
+		`# TYPE rox_sensor_info gauge`,
+		`rox_sensor_info{build="a,b=c",path="/api/{id}",msg="quote \"x\""} 1`,
+		`broken_metric{label="unterminated} 1`,
+		`not_a_number 1.2.3`,
+		`queue_size 7`,
+	}, "\n")
+
+	tests := map[string]struct {
+		strict      bool
+		wantError   string
+		wantSkipped []string
+	}{
+		"should skip malformed lines and report them with line numbers": {
+			wantSkipped: []string{
+				"line 3, column 21: unterminated label value",
+				`line 4, column 14: invalid value "1.2.3"`,
+			},
+		},
+		"should fail on the first malformed line in strict mode": {
+			strict:    true,
+			wantError: "line 3, column 21: unterminated label value",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			metrics, skipped, err := ParseReaderWithOptions(strings.NewReader(input), ParseOptions{Strict: tt.strict})
+			if tt.wantError != "" {
+				if err == nil || err.Error() != tt.wantError {
+					t.Errorf("ParseReaderWithOptions() error = %v, want %q", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseReaderWithOptions() error = %v", err)
+			}
+
+			if len(skipped) != len(tt.wantSkipped) {
+				t.Fatalf("ParseReaderWithOptions() skipped %d lines, want %d: %v", len(skipped), len(tt.wantSkipped), skipped)
+			}
+			for i, want := range tt.wantSkipped {
+				if skipped[i].Error() != want {
+					t.Errorf("ParseReaderWithOptions() skipped[%d] = %q, want %q", i, skipped[i].Error(), want)
+				}
+			}
+
+			info := metrics["rox_sensor_info"].Values[0].Labels
+			if info["build"] != "a,b=c" || info["path"] != "/api/{id}" || info["msg"] != `quote "x"` {
+				t.Errorf("ParseReaderWithOptions() labels = %v", info)
+			}
+			if _, exists := metrics["queue_size"]; !exists {
+				t.Error("ParseReaderWithOptions() dropped the line after malformed ones")
+			}
+		})
+	}
+}
+
+func TestGetSummarySeries(t *testing.T) {
+	metrics, err := ParseReader(strings.NewReader(`# TYPE rpc_duration_seconds summary
+rpc_duration_seconds{service="b",quantile="0.99"} 0.9
+rpc_duration_seconds{service="b",quantile="0.5"} 0.2
+rpc_duration_seconds{service="a",quantile="0.5"} 0.1
+rpc_duration_seconds_sum{service="a"} 12
+rpc_duration_seconds_count{service="a"} 100
+# TYPE go_goroutines gauge
+go_goroutines 10
+`))
+	if err != nil {
+		t.Fatalf("ParseReader() error = %v", err)
+	}
+
+	if names := metrics.GetSummaryBaseNames(); len(names) != 1 || names[0] != "rpc_duration_seconds" {
+		t.Errorf("GetSummaryBaseNames() = %v, want [rpc_duration_seconds]", names)
+	}
+
+	series := metrics["rpc_duration_seconds"].GetSummarySeries()
+	if len(series) != 2 {
+		t.Fatalf("GetSummarySeries() got %d series, want 2", len(series))
+	}
+	if series[0].Key != "service=a" || series[0].Labels["service"] != "a" || len(series[0].Quantiles) != 1 {
+		t.Errorf("GetSummarySeries() first series = %+v", series[0])
+	}
+	if _, hasQuantile := series[0].Labels["quantile"]; hasQuantile {
+		t.Error("GetSummarySeries() kept the quantile label")
+	}
+	b := series[1].Quantiles
+	if len(b) != 2 || b[0].Quantile != 0.5 || b[1].Quantile != 0.99 || b[1].Value != 0.9 {
+		t.Errorf("GetSummarySeries() second series quantiles = %+v, want 0.5 and 0.99 sorted", b)
+	}
+}
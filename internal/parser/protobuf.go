@@ -0,0 +1,165 @@
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/protobuf/encoding/protodelim"
+)
+
+// parseProtobuf parses the Prometheus delimited protobuf exposition format.
+// Metric families are flattened the same way the text format exposes them:
+// histograms become <name>_bucket, <name>_sum and <name>_count, summaries
+// become <name> (with a quantile label), <name>_sum and <name>_count.
+func parseProtobuf(data []byte) (MetricsData, error) {
+	metrics := make(MetricsData)
+	reader := bufio.NewReader(bytes.NewReader(data))
+
+	for familyIndex := 0; ; familyIndex++ {
+		family := &dto.MetricFamily{}
+		err := protodelim.UnmarshalFrom(reader, family)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("metric family %d: failed to decode protobuf: %w", familyIndex, err)
+		}
+		addMetricFamily(metrics, family)
+	}
+
+	return metrics, nil
+}
+
+func addMetricFamily(metrics MetricsData, family *dto.MetricFamily) {
+	name := family.GetName()
+	base := metrics.getOrCreate(name)
+	base.Help = family.GetHelp()
+	base.Unit = family.GetUnit()
+
+	switch family.GetType() {
+	case dto.MetricType_COUNTER:
+		base.Type = "counter"
+		for _, m := range family.GetMetric() {
+			sample := newProtoSample(m, m.GetCounter().GetValue())
+			if exemplar := m.GetCounter().GetExemplar(); exemplar != nil {
+				sample.Exemplar = newProtoExemplar(exemplar)
+			}
+			base.Values = append(base.Values, sample)
+		}
+	case dto.MetricType_GAUGE:
+		base.Type = "gauge"
+		for _, m := range family.GetMetric() {
+			base.Values = append(base.Values, newProtoSample(m, m.GetGauge().GetValue()))
+		}
+	case dto.MetricType_UNTYPED:
+		base.Type = "untyped"
+		for _, m := range family.GetMetric() {
+			base.Values = append(base.Values, newProtoSample(m, m.GetUntyped().GetValue()))
+		}
+	case dto.MetricType_SUMMARY:
+		base.Type = "summary"
+		sum := metrics.getOrCreate(name + "_sum")
+		count := metrics.getOrCreate(name + "_count")
+		for _, m := range family.GetMetric() {
+			summary := m.GetSummary()
+			for _, q := range summary.GetQuantile() {
+				sample := newProtoSample(m, q.GetValue())
+				sample.Labels["quantile"] = formatFloatLabel(q.GetQuantile())
+				base.Values = append(base.Values, sample)
+			}
+			sum.Values = append(sum.Values, newProtoSample(m, summary.GetSampleSum()))
+			count.Values = append(count.Values, newProtoSample(m, float64(summary.GetSampleCount())))
+		}
+	case dto.MetricType_HISTOGRAM, dto.MetricType_GAUGE_HISTOGRAM:
+		// Gauge histogram buckets are current counts rather than cumulative
+		// ones, so they keep the OpenMetrics type name and are not diffed
+		base.Type = "histogram"
+		if family.GetType() == dto.MetricType_GAUGE_HISTOGRAM {
+			base.Type = "gaugehistogram"
+		}
+		buckets := metrics.getOrCreate(name + "_bucket")
+		sum := metrics.getOrCreate(name + "_sum")
+		count := metrics.getOrCreate(name + "_count")
+		for _, m := range family.GetMetric() {
+			histogram := m.GetHistogram()
+			sampleCount := float64(histogram.GetSampleCount())
+			if histogram.SampleCountFloat != nil {
+				sampleCount = histogram.GetSampleCountFloat()
+			}
+
+			hasInf := false
+			for _, b := range histogram.GetBucket() {
+				bucketCount := float64(b.GetCumulativeCount())
+				if b.CumulativeCountFloat != nil {
+					bucketCount = b.GetCumulativeCountFloat()
+				}
+				sample := newProtoSample(m, bucketCount)
+				sample.Labels["le"] = formatFloatLabel(b.GetUpperBound())
+				if exemplar := b.GetExemplar(); exemplar != nil {
+					sample.Exemplar = newProtoExemplar(exemplar)
+				}
+				buckets.Values = append(buckets.Values, sample)
+				if math.IsInf(b.GetUpperBound(), 1) {
+					hasInf = true
+				}
+			}
+			// The +Inf bucket is implicit in protobuf; the text format always exposes it
+			if !hasInf {
+				sample := newProtoSample(m, sampleCount)
+				sample.Labels["le"] = "+Inf"
+				buckets.Values = append(buckets.Values, sample)
+			}
+
+			sum.Values = append(sum.Values, newProtoSample(m, histogram.GetSampleSum()))
+			count.Values = append(count.Values, newProtoSample(m, sampleCount))
+		}
+	}
+}
+
+func newProtoSample(m *dto.Metric, value float64) MetricValue {
+	labels := make(map[string]string, len(m.GetLabel()))
+	for _, pair := range m.GetLabel() {
+		labels[pair.GetName()] = pair.GetValue()
+	}
+	sample := MetricValue{
+		Labels: labels,
+		Value:  value,
+	}
+	if m.TimestampMs != nil {
+		sample.Timestamp = time.UnixMilli(m.GetTimestampMs())
+	}
+	return sample
+}
+
+func newProtoExemplar(e *dto.Exemplar) *Exemplar {
+	labels := make(map[string]string, len(e.GetLabel()))
+	for _, pair := range e.GetLabel() {
+		labels[pair.GetName()] = pair.GetValue()
+	}
+	exemplar := &Exemplar{
+		Labels: labels,
+		Value:  e.GetValue(),
+	}
+	if e.GetTimestamp() != nil {
+		exemplar.Timestamp = e.GetTimestamp().AsTime()
+	}
+	return exemplar
+}
+
+// formatFloatLabel formats le/quantile values the way the text format does
+func formatFloatLabel(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -0,0 +1,226 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// processStartTimeMetric is used to detect process restarts between two snapshots
+const processStartTimeMetric = "process_start_time_seconds"
+
+// DiffSnapshots compares two snapshots of the same process taken interval apart.
+// Counters are converted to per-second rates over the interval. Histogram and
+// summary components (_bucket, _sum, _count) are converted to interval-only
+// counts, so percentiles computed from them describe only the interval.
+// Gauges and gauge histograms are taken from the current snapshot unchanged.
+//
+// Counter resets are handled per series: when a value decreased, the process
+// is assumed to have restarted and the current value is used as the increase.
+// A changed process_start_time_seconds marks every series as reset.
+func DiffSnapshots(baseline, current MetricsData, interval time.Duration) (MetricsData, error) {
+	return DiffSnapshotSequence([]MetricsData{baseline, current}, interval)
+}
+
+// DiffSnapshotSequence is DiffSnapshots over more than two snapshots of the same
+// process, each taken step after the previous one. Increases are summed scrape by
+// scrape, so a counter reset between any two scrapes only loses the increase
+// before the reset within that step. Rates are over the whole sequence.
+func DiffSnapshotSequence(snapshots []MetricsData, step time.Duration) (MetricsData, error) {
+	if len(snapshots) < 2 {
+		return nil, fmt.Errorf("at least two snapshots are required, got %d", len(snapshots))
+	}
+	if step <= 0 {
+		return nil, fmt.Errorf("interval between snapshots must be positive, got %s", step)
+	}
+	seconds := (step * time.Duration(len(snapshots)-1)).Seconds()
+
+	// Increase per metric and series, summed over all steps
+	increases := make(map[string]map[string]float64)
+	for i := 1; i < len(snapshots); i++ {
+		baseline, current := snapshots[i-1], snapshots[i]
+		restarted := processRestarted(baseline, current)
+		for name, metric := range current {
+			if current.cumulativeKind(name) == cumulativeNone {
+				continue
+			}
+			if increases[name] == nil {
+				increases[name] = make(map[string]float64)
+			}
+			baselineMetric, _ := baseline.GetMetric(name)
+			for _, delta := range diffValues(metric, baselineMetric, restarted) {
+				increases[name][SeriesKey(delta.Labels)] += delta.Value
+			}
+		}
+	}
+
+	current := snapshots[len(snapshots)-1]
+	result := make(MetricsData, len(current))
+	for name, metric := range current {
+		diffed := &Metric{
+			Name: metric.Name,
+			Help: metric.Help,
+			Type: metric.Type,
+		}
+		result[name] = diffed
+
+		kind := current.cumulativeKind(name)
+		if kind == cumulativeNone {
+			diffed.Values = copyValues(metric.Values)
+			continue
+		}
+
+		diffed.Values = copyValues(metric.Values)
+		for i := range diffed.Values {
+			diffed.Values[i].Value = increases[name][SeriesKey(diffed.Values[i].Labels)]
+			if kind == cumulativeCounter {
+				diffed.Values[i].Value /= seconds
+			}
+		}
+	}
+
+	return result, nil
+}
+
+type cumulativeKind int
+
+const (
+	cumulativeNone cumulativeKind = iota
+	cumulativeCounter
+	cumulativeDistribution
+)
+
+// cumulativeKind classifies a metric by how it behaves between two scrapes
+func (md MetricsData) cumulativeKind(name string) cumulativeKind {
+	if md.isDistributionComponent(name) {
+		return cumulativeDistribution
+	}
+	metric, ok := md[name]
+	if !ok {
+		return cumulativeNone
+	}
+	switch metric.Type {
+	case "counter":
+		return cumulativeCounter
+	case "":
+		// Untyped metrics following the naming convention are treated as counters
+		if strings.HasSuffix(name, "_total") {
+			return cumulativeCounter
+		}
+	}
+	return cumulativeNone
+}
+
+// isDistributionComponent reports whether name is the _bucket, _sum or _count
+// series of a histogram or summary. The TYPE may be declared on the family
+// name or, as in some exports, on the _bucket series itself.
+func (md MetricsData) isDistributionComponent(name string) bool {
+	if metric, ok := md[name]; ok && metric.Type == "histogram" {
+		return true
+	}
+	for _, suffix := range []string{"_bucket", "_sum", "_count"} {
+		if !strings.HasSuffix(name, suffix) {
+			continue
+		}
+		base := strings.TrimSuffix(name, suffix)
+		if family, ok := md[base]; ok && (family.Type == "histogram" || family.Type == "summary") {
+			return true
+		}
+		if buckets, ok := md[base+"_bucket"]; ok && buckets.Type == "histogram" {
+			return true
+		}
+	}
+	return false
+}
+
+// diffValues returns the increase of every series of current since baseline.
+// Buckets of a histogram series are reset together so the result stays monotonic.
+func diffValues(current, baseline *Metric, restarted bool) []MetricValue {
+	previous := make(map[string]float64)
+	if baseline != nil {
+		for _, v := range baseline.Values {
+			previous[SeriesKey(v.Labels)] = v.Value
+		}
+	}
+
+	// Detect resets per series, ignoring the le label so all buckets agree
+	reset := make(map[string]bool)
+	for _, v := range current.Values {
+		groupKey := seriesKeyWithout(v.Labels, "le")
+		prev, seen := previous[SeriesKey(v.Labels)]
+		if restarted || !seen || v.Value < prev {
+			reset[groupKey] = true
+		}
+	}
+
+	deltas := make([]MetricValue, 0, len(current.Values))
+	for _, v := range current.Values {
+		delta := v.Value
+		if !reset[seriesKeyWithout(v.Labels, "le")] {
+			delta = v.Value - previous[SeriesKey(v.Labels)]
+		}
+		deltas = append(deltas, MetricValue{
+			Labels: copyLabels(v.Labels),
+			Value:  delta,
+		})
+	}
+	return deltas
+}
+
+func processRestarted(baseline, current MetricsData) bool {
+	before, ok := baseline.GetMetric(processStartTimeMetric)
+	if !ok {
+		return false
+	}
+	after, ok := current.GetMetric(processStartTimeMetric)
+	if !ok {
+		return false
+	}
+	beforeValue, ok := before.GetSingleValue()
+	if !ok {
+		return false
+	}
+	afterValue, ok := after.GetSingleValue()
+	if !ok {
+		return false
+	}
+	return beforeValue != afterValue
+}
+
+// SeriesKey creates a stable key identifying a series by its full label set
+func SeriesKey(labels map[string]string) string {
+	return seriesKeyWithout(labels, "")
+}
+
+func seriesKeyWithout(labels map[string]string, excluded string) string {
+	keys := make([]string, 0, len(labels))
+	for k, v := range labels {
+		if k == excluded {
+			continue
+		}
+		keys = append(keys, k+"="+v)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ",")
+}
+
+func copyValues(values []MetricValue) []MetricValue {
+	result := make([]MetricValue, 0, len(values))
+	for _, v := range values {
+		result = append(result, MetricValue{
+			Labels:    copyLabels(v.Labels),
+			Value:     v.Value,
+			Timestamp: v.Timestamp,
+		})
+	}
+	return result
+}
+
+func copyLabels(labels map[string]string) map[string]string {
+	result := make(map[string]string, len(labels))
+	for k, v := range labels {
+		result[k] = v
+	}
+	return result
+}
@@ -0,0 +1,223 @@
+package parser
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	baseline := `# TYPE ops_total counter
+ops_total{Operation="Add"} 100
+ops_total{Operation="Remove"} 500
+# TYPE queue_size gauge
+queue_size 10
+# TYPE latency_seconds histogram
+latency_seconds_bucket{le="0.1"} 90
+latency_seconds_bucket{le="1"} 100
+latency_seconds_bucket{le="+Inf"} 100
+latency_seconds_sum 20
+latency_seconds_count 100
+`
+	current := `# TYPE ops_total counter
+ops_total{Operation="Add"} 700
+ops_total{Operation="Remove"} 60
+ops_total{Operation="Drop"} 30
+# TYPE queue_size gauge
+queue_size 42
+# TYPE latency_seconds histogram
+latency_seconds_bucket{le="0.1"} 100
+latency_seconds_bucket{le="1"} 150
+latency_seconds_bucket{le="+Inf"} 200
+latency_seconds_sum 120
+latency_seconds_count 200
+`
+
+	before, err := ParseReader(strings.NewReader(baseline))
+	if err != nil {
+		t.Fatalf("ParseReader() baseline error = %v", err)
+	}
+	after, err := ParseReader(strings.NewReader(current))
+	if err != nil {
+		t.Fatalf("ParseReader() current error = %v", err)
+	}
+
+	diffed, err := DiffSnapshots(before, after, time.Minute)
+	if err != nil {
+		t.Fatalf("DiffSnapshots() error = %v", err)
+	}
+
+	tests := map[string]struct {
+		metric string
+		labels map[string]string
+		want   float64
+	}{
+		"should convert counter increase to per-second rate": {
+			metric: "ops_total",
+			labels: map[string]string{"Operation": "Add"},
+			want:   10, // (700 - 100) / 60s
+		},
+		"should treat decreased counter as reset": {
+			metric: "ops_total",
+			labels: map[string]string{"Operation": "Remove"},
+			want:   1, // 60 / 60s
+		},
+		"should treat new series as starting from zero": {
+			metric: "ops_total",
+			labels: map[string]string{"Operation": "Drop"},
+			want:   0.5,
+		},
+		"should keep gauges from current snapshot": {
+			metric: "queue_size",
+			labels: map[string]string{},
+			want:   42,
+		},
+		"should keep interval-only histogram bucket counts": {
+			metric: "latency_seconds_bucket",
+			labels: map[string]string{"le": "0.1"},
+			want:   10,
+		},
+		"should keep interval-only histogram count": {
+			metric: "latency_seconds_count",
+			labels: map[string]string{},
+			want:   100,
+		},
+		"should keep interval-only histogram sum": {
+			metric: "latency_seconds_sum",
+			labels: map[string]string{},
+			want:   100,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			metric, exists := diffed.GetMetric(tt.metric)
+			if !exists {
+				t.Fatalf("DiffSnapshots() metric %s missing", tt.metric)
+			}
+			found := false
+			for _, v := range metric.Values {
+				if SeriesKey(v.Labels) == SeriesKey(tt.labels) {
+					found = true
+					if v.Value != tt.want {
+						t.Errorf("DiffSnapshots() %s%v = %v, want %v", tt.metric, tt.labels, v.Value, tt.want)
+					}
+				}
+			}
+			if !found {
+				t.Errorf("DiffSnapshots() series %s%v missing", tt.metric, tt.labels)
+			}
+		})
+	}
+}
+
+func TestDiffSnapshotsProcessRestart(t *testing.T) {
+	baseline := MetricsData{
+		"process_start_time_seconds": &Metric{Type: "gauge", Values: []MetricValue{{Labels: map[string]string{}, Value: 1000}}},
+		"events_total":               &Metric{Type: "counter", Values: []MetricValue{{Labels: map[string]string{}, Value: 50}}},
+	}
+	current := MetricsData{
+		"process_start_time_seconds": &Metric{Type: "gauge", Values: []MetricValue{{Labels: map[string]string{}, Value: 2000}}},
+		"events_total":               &Metric{Type: "counter", Values: []MetricValue{{Labels: map[string]string{}, Value: 80}}},
+	}
+
+	diffed, err := DiffSnapshots(baseline, current, 10*time.Second)
+	if err != nil {
+		t.Fatalf("DiffSnapshots() error = %v", err)
+	}
+	value, _ := diffed["events_total"].GetSingleValue()
+	if value != 8 {
+		t.Errorf("DiffSnapshots() events_total = %v, want 8 (restart means counting from zero)", value)
+	}
+}
+
+func TestDiffSnapshotsGaugeHistogram(t *testing.T) {
+	// Gauge histogram buckets count what is currently in each bucket, e.g.
+	// queued items by age, rather than observations since the start
+	scrape := func(fast, total uint64) []byte {
+		return encodeFamilies(t, &dto.MetricFamily{
+			Name: proto.String("queue_item_age_seconds"),
+			Type: dto.MetricType_GAUGE_HISTOGRAM.Enum(),
+			Metric: []*dto.Metric{{
+				Histogram: &dto.Histogram{
+					SampleCount: proto.Uint64(total),
+					SampleSum:   proto.Float64(float64(total)),
+					Bucket:      []*dto.Bucket{{UpperBound: proto.Float64(1), CumulativeCount: proto.Uint64(fast)}},
+				},
+			}},
+		})
+	}
+	before, err := ParseReader(bytes.NewReader(scrape(3, 4)))
+	if err != nil {
+		t.Fatalf("ParseReader() baseline error = %v", err)
+	}
+	after, err := ParseReader(bytes.NewReader(scrape(8, 10)))
+	if err != nil {
+		t.Fatalf("ParseReader() current error = %v", err)
+	}
+
+	diffed, err := DiffSnapshots(before, after, 10*time.Second)
+	if err != nil {
+		t.Fatalf("DiffSnapshots() error = %v", err)
+	}
+
+	if family := diffed["queue_item_age_seconds"]; family == nil || family.Type != "gaugehistogram" {
+		t.Fatalf("DiffSnapshots() family = %+v, want type gaugehistogram", family)
+	}
+	if bases := diffed.GetHistogramBaseNames(); len(bases) != 1 || bases[0] != "queue_item_age_seconds" {
+		t.Errorf("GetHistogramBaseNames() = %v, want [queue_item_age_seconds]", bases)
+	}
+	buckets, _ := diffed.GetMetric("queue_item_age_seconds_bucket")
+	if got := buckets.GetValuesByLabel("le"); got["1"] != 8 || got["+Inf"] != 10 {
+		t.Errorf("DiffSnapshots() buckets = %v, want the current 8 and 10", got)
+	}
+	if count, _ := diffed.GetHistogramCount("queue_item_age_seconds"); count != 10 {
+		t.Errorf("DiffSnapshots() count = %v, want the current 10", count)
+	}
+}
+
+func TestDiffSnapshotsInvalidInterval(t *testing.T) {
+	if _, err := DiffSnapshots(MetricsData{}, MetricsData{}, 0); err == nil {
+		t.Error("DiffSnapshots() expected error for zero interval")
+	}
+}
+
+func TestDiffSnapshotSequence(t *testing.T) {
+	scrapes := []string{
+		"# TYPE ops_total counter\nops_total 100\n# TYPE queue_size gauge\nqueue_size 1\n",
+		"# TYPE ops_total counter\nops_total 160\n# TYPE queue_size gauge\nqueue_size 2\n",
+		// Restart: the counter starts again from zero
+		"# TYPE ops_total counter\nops_total 30\n# TYPE queue_size gauge\nqueue_size 3\n",
+		"# TYPE ops_total counter\nops_total 90\n# TYPE queue_size gauge\nqueue_size 4\n",
+	}
+	snapshots := make([]MetricsData, 0, len(scrapes))
+	for _, scrape := range scrapes {
+		metrics, err := ParseReader(strings.NewReader(scrape))
+		if err != nil {
+			t.Fatalf("ParseReader() error = %v", err)
+		}
+		snapshots = append(snapshots, metrics)
+	}
+
+	diffed, err := DiffSnapshotSequence(snapshots, 10*time.Second)
+	if err != nil {
+		t.Fatalf("DiffSnapshotSequence() error = %v", err)
+	}
+
+	ops, _ := diffed.GetMetric("ops_total")
+	if got, _ := ops.GetSingleValue(); got != 5 { // (60 + 30 + 60) / 30s
+		t.Errorf("DiffSnapshotSequence() ops_total = %v, want 5", got)
+	}
+	queue, _ := diffed.GetMetric("queue_size")
+	if got, _ := queue.GetSingleValue(); got != 4 {
+		t.Errorf("DiffSnapshotSequence() queue_size = %v, want 4", got)
+	}
+
+	if _, err := DiffSnapshotSequence(snapshots[:1], time.Second); err == nil {
+		t.Error("DiffSnapshotSequence() expected error for a single snapshot")
+	}
+}
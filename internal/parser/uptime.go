@@ -0,0 +1,69 @@
+package parser
+
+import (
+	"math"
+	"time"
+)
+
+// ProcessStartTime returns when the scraped process started, from process_start_time_seconds
+func (md MetricsData) ProcessStartTime() (time.Time, bool) {
+	metric, ok := md.GetMetric(processStartTimeMetric)
+	if !ok {
+		return time.Time{}, false
+	}
+	seconds, ok := metric.GetSingleValue()
+	if !ok || seconds <= 0 {
+		return time.Time{}, false
+	}
+	whole, fraction := math.Modf(seconds)
+	return time.Unix(int64(whole), int64(fraction*float64(time.Second))), true
+}
+
+// LatestTimestamp returns the newest sample timestamp. Most exporters, Sensor
+// included, do not timestamp samples, in which case ok is false.
+func (md MetricsData) LatestTimestamp() (time.Time, bool) {
+	var latest time.Time
+	for _, metric := range md {
+		for _, v := range metric.Values {
+			if v.Timestamp.After(latest) {
+				latest = v.Timestamp
+			}
+		}
+	}
+	return latest, !latest.IsZero()
+}
+
+// Uptime returns how long the scraped process had been running when the scrape
+// was taken. The scrape time is the latest sample timestamp, or scrapeTime when
+// the samples carry none. ok is false without process_start_time_seconds or a
+// scrape time, and when the start time is after the scrape time.
+func (md MetricsData) Uptime(scrapeTime time.Time) (time.Duration, bool) {
+	start, ok := md.ProcessStartTime()
+	if !ok {
+		return 0, false
+	}
+	if latest, ok := md.LatestTimestamp(); ok {
+		scrapeTime = latest
+	}
+	if scrapeTime.IsZero() || !scrapeTime.After(start) {
+		return 0, false
+	}
+	return scrapeTime.Sub(start), true
+}
+
+// ScaleCounters returns a copy of the metrics with every counter series multiplied
+// by factor. Histograms, summaries and gauges are copied unchanged.
+func (md MetricsData) ScaleCounters(factor float64) MetricsData {
+	result := make(MetricsData, len(md))
+	for name, metric := range md {
+		scaled := *metric
+		scaled.Values = copyValues(metric.Values)
+		if md.cumulativeKind(name) == cumulativeCounter {
+			for i := range scaled.Values {
+				scaled.Values[i].Value *= factor
+			}
+		}
+		result[name] = &scaled
+	}
+	return result
+}
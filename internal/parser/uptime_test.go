@@ -0,0 +1,94 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUptime(t *testing.T) {
+	scrapeTime := time.Unix(1700003600, 0)
+
+	tests := map[string]struct {
+		metrics    string
+		scrapeTime time.Time
+		want       time.Duration
+		wantOK     bool
+	}{
+		"should measure uptime up to the scrape time": {
+			metrics:    "process_start_time_seconds 1700000000\n",
+			scrapeTime: scrapeTime,
+			want:       time.Hour,
+			wantOK:     true,
+		},
+		"should prefer sample timestamps over the scrape time": {
+			metrics:    "process_start_time_seconds 1700000000 1700000600000\n",
+			scrapeTime: scrapeTime,
+			want:       10 * time.Minute,
+			wantOK:     true,
+		},
+		"should keep fractional start times": {
+			metrics:    "process_start_time_seconds 1700003599.5\n",
+			scrapeTime: scrapeTime,
+			want:       500 * time.Millisecond,
+			wantOK:     true,
+		},
+		"should not know the uptime without a scrape time": {
+			metrics: "process_start_time_seconds 1700000000\n",
+		},
+		"should not know the uptime without a start time": {
+			metrics:    "go_goroutines 10\n",
+			scrapeTime: scrapeTime,
+		},
+		"should reject a start time after the scrape": {
+			metrics:    "process_start_time_seconds 1700007200\n",
+			scrapeTime: scrapeTime,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			metrics, err := ParseReader(strings.NewReader(tt.metrics))
+			if err != nil {
+				t.Fatalf("ParseReader() error = %v", err)
+			}
+			got, ok := metrics.Uptime(tt.scrapeTime)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Uptime() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestScaleCounters(t *testing.T) {
+	metrics, err := ParseReader(strings.NewReader(`# TYPE ops_total counter
+ops_total{Operation="Add"} 3600
+# TYPE queue_size gauge
+queue_size 42
+dropped_total 36
+# TYPE latency_seconds histogram
+latency_seconds_bucket{le="+Inf"} 7200
+latency_seconds_count 7200
+`))
+	if err != nil {
+		t.Fatalf("ParseReader() error = %v", err)
+	}
+
+	scaled := metrics.ScaleCounters(1.0 / 3600)
+
+	want := map[string]float64{
+		"ops_total":              1,
+		"dropped_total":          0.01,
+		"queue_size":             42,
+		"latency_seconds_bucket": 7200,
+		"latency_seconds_count":  7200,
+	}
+	for name, value := range want {
+		if got := scaled[name].Values[0].Value; got != value {
+			t.Errorf("ScaleCounters() %s = %g, want %g", name, got, value)
+		}
+	}
+	if metrics["ops_total"].Values[0].Value != 3600 {
+		t.Error("ScaleCounters() modified the original metrics")
+	}
+}
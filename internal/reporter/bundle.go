@@ -0,0 +1,162 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/stackrox/sensor-metrics-analyzer/internal/rules"
+)
+
+// JSONBundleReport is the machine-readable form of a bundle analysis
+type JSONBundleReport struct {
+	SchemaVersion string              `json:"schema_version"`
+	Source        string              `json:"source"`
+	GeneratedAt   time.Time           `json:"generated_at"`
+	Summary       JSONSummary         `json:"summary"`
+	Clusters      []JSONClusterReport `json:"clusters"`
+}
+
+// JSONClusterReport is the analysis of one metrics file of a bundle
+type JSONClusterReport struct {
+	ClusterName string      `json:"cluster_name"`
+	File        string      `json:"file"`
+	Error       string      `json:"error,omitempty"`
+	Report      *JSONReport `json:"report,omitempty"`
+}
+
+// GenerateBundleJSON creates an indented JSON report for a bundle analysis
+func GenerateBundleJSON(bundle rules.BundleReport) (string, error) {
+	out := JSONBundleReport{
+		SchemaVersion: JSONSchemaVersion,
+		Source:        bundle.Source,
+		GeneratedAt:   bundle.Timestamp,
+		Summary: JSONSummary{
+			Total:   bundle.Summary.TotalAnalyzed,
+			Red:     bundle.Summary.RedCount,
+			Yellow:  bundle.Summary.YellowCount,
+			Green:   bundle.Summary.GreenCount,
+			Unknown: bundle.Summary.UnknownCount,
+		},
+		Clusters: make([]JSONClusterReport, 0, len(bundle.Clusters)),
+	}
+	for _, cluster := range bundle.Clusters {
+		entry := JSONClusterReport{
+			ClusterName: cluster.ClusterName,
+			File:        cluster.File,
+			Error:       cluster.Error,
+		}
+		if cluster.Error == "" {
+			report := NewJSONReport(cluster.Report)
+			entry.Report = &report
+		}
+		out.Clusters = append(out.Clusters, entry)
+	}
+
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode JSON report: %w", err)
+	}
+	return string(data) + "\n", nil
+}
+
+// GenerateBundleConsole creates a console report with a per-cluster summary table
+// followed by the individual cluster reports
+func GenerateBundleConsole(bundle rules.BundleReport) string {
+	var result strings.Builder
+
+	result.WriteString(color.New(color.Bold).Sprint("Diagnostic Bundle Analysis Report\n\n"))
+	result.WriteString(fmt.Sprintf("Source: %s\n", bundle.Source))
+	result.WriteString(fmt.Sprintf("Clusters: %d\n", len(bundle.Clusters)))
+	result.WriteString(fmt.Sprintf("Generated: %s\n\n", bundle.Timestamp.Format("2006-01-02 15:04:05")))
+
+	result.WriteString(color.New(color.Bold).Sprint("Cluster Summary\n"))
+	t := table.NewWriter()
+	var tableBuf bytes.Buffer
+	t.SetOutputMirror(&tableBuf)
+	t.AppendHeader(table.Row{"Cluster", "ACS Version", "Load Level", "🔴 RED", "🟡 YELLOW", "🟢 GREEN", "⚪ UNKNOWN", "File"})
+	for _, cluster := range bundle.Clusters {
+		if cluster.Error != "" {
+			t.AppendRow(table.Row{cluster.ClusterName, "-", "-", "-", "-", "-", "-", cluster.File})
+			continue
+		}
+		summary := cluster.Report.Summary
+		t.AppendRow(table.Row{
+			cluster.ClusterName,
+			cluster.Report.ACSVersion,
+			cluster.Report.LoadLevel,
+			summary.RedCount,
+			summary.YellowCount,
+			summary.GreenCount,
+			summary.UnknownCount,
+			cluster.File,
+		})
+	}
+	t.AppendFooter(table.Row{"Total", "", "", bundle.Summary.RedCount, bundle.Summary.YellowCount, bundle.Summary.GreenCount,
+		bundle.Summary.UnknownCount, ""})
+	t.SetStyle(table.StyleRounded)
+	t.Render()
+	result.WriteString(tableBuf.String())
+	result.WriteString("\n")
+
+	for _, cluster := range bundle.Clusters {
+		if cluster.Error != "" {
+			result.WriteString(color.RedString("Failed to analyze %s: %s\n", cluster.File, cluster.Error))
+		}
+	}
+
+	for _, cluster := range bundle.Clusters {
+		if cluster.Error != "" {
+			continue
+		}
+		result.WriteString("\n" + strings.Repeat("═", 80) + "\n\n")
+		result.WriteString(GenerateConsole(cluster.Report))
+	}
+
+	return result.String()
+}
+
+// GenerateBundleMarkdown creates a markdown report with a per-cluster summary table
+// followed by each cluster's report rendered with the markdown template
+func GenerateBundleMarkdown(bundle rules.BundleReport, templatePath string) (string, error) {
+	var result strings.Builder
+
+	result.WriteString("# Diagnostic Bundle Analysis Report\n\n")
+	result.WriteString(fmt.Sprintf("- **Source:** %s\n", bundle.Source))
+	result.WriteString(fmt.Sprintf("- **Clusters:** %d\n", len(bundle.Clusters)))
+	result.WriteString(fmt.Sprintf("- **Report Generated:** %s\n\n", bundle.Timestamp.Format("2006-01-02 15:04:05")))
+
+	result.WriteString("## Cluster Summary\n\n")
+	result.WriteString("| Cluster | ACS Version | Load Level | 🔴 RED | 🟡 YELLOW | 🟢 GREEN | ⚪ UNKNOWN | File |\n")
+	result.WriteString("|---------|-------------|------------|--------|-----------|----------|------------|------|\n")
+	for _, cluster := range bundle.Clusters {
+		if cluster.Error != "" {
+			result.WriteString(fmt.Sprintf("| %s | - | - | - | - | - | - | `%s` (failed: %s) |\n", cluster.ClusterName, cluster.File, cluster.Error))
+			continue
+		}
+		summary := cluster.Report.Summary
+		result.WriteString(fmt.Sprintf("| %s | %s | %s | %d | %d | %d | %d | `%s` |\n",
+			cluster.ClusterName, cluster.Report.ACSVersion, cluster.Report.LoadLevel,
+			summary.RedCount, summary.YellowCount, summary.GreenCount, summary.UnknownCount, cluster.File))
+	}
+	result.WriteString(fmt.Sprintf("| **Total** | | | %d | %d | %d | %d | |\n",
+		bundle.Summary.RedCount, bundle.Summary.YellowCount, bundle.Summary.GreenCount, bundle.Summary.UnknownCount))
+
+	for _, cluster := range bundle.Clusters {
+		if cluster.Error != "" {
+			continue
+		}
+		markdown, err := GenerateMarkdown(cluster.Report, templatePath)
+		if err != nil {
+			return "", fmt.Errorf("cluster %s: %w", cluster.ClusterName, err)
+		}
+		result.WriteString("\n---\n\n")
+		result.WriteString(markdown)
+	}
+
+	return result.String(), nil
+}
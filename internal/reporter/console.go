@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/jedib0t/go-pretty/v6/table"
@@ -19,8 +20,20 @@ func GenerateConsole(report rules.AnalysisReport) string {
 	result.WriteString(color.New(color.Bold).Sprint("Automated Metrics Analysis Report\n\n"))
 	result.WriteString(fmt.Sprintf("Cluster: %s\n", report.ClusterName))
 	result.WriteString(fmt.Sprintf("ACS Version: %s\n", report.ACSVersion))
-	result.WriteString(fmt.Sprintf("Load Level: %s\n", report.LoadLevel))
+	result.WriteString(fmt.Sprintf("Load Level: %s\n", FormatLoadLevel(report)))
+	if report.RateInterval > 0 {
+		result.WriteString(fmt.Sprintf("Mode: per-second rates over %s between two scrapes\n", report.RateInterval))
+	}
+	if report.Uptime > 0 {
+		result.WriteString(fmt.Sprintf("Sensor Uptime: %s\n", report.Uptime.Round(time.Second)))
+	}
 	result.WriteString(fmt.Sprintf("Generated: %s\n\n", report.Timestamp.Format("2006-01-02 15:04:05")))
+	for _, warning := range report.Warnings {
+		result.WriteString(color.YellowString("⚠️  %s\n", warning))
+	}
+	if len(report.Warnings) > 0 {
+		result.WriteString("\n")
+	}
 
 	// Summary table
 	result.WriteString(color.New(color.Bold).Sprint("Summary\n"))
@@ -50,6 +63,13 @@ func GenerateConsole(report rules.AnalysisReport) string {
 			report.Summary.GreenCount,
 			fmt.Sprintf("%.1f%%", greenPct),
 		})
+		if report.Summary.UnknownCount > 0 {
+			t.AppendRow(table.Row{
+				color.HiBlackString("⚪ UNKNOWN"),
+				report.Summary.UnknownCount,
+				fmt.Sprintf("%.1f%%", float64(report.Summary.UnknownCount)/float64(total)*100),
+			})
+		}
 	}
 
 	t.SetStyle(table.StyleRounded)
@@ -57,6 +77,9 @@ func GenerateConsole(report rules.AnalysisReport) string {
 	result.WriteString(tableBuf.String())
 	result.WriteString("\n")
 
+	// Why the thresholds of this load level were used
+	writeLoadDetectionConsole(&result, report)
+
 	// Critical Issues
 	redResults := filterByStatus(report.Results, rules.StatusRed)
 	if len(redResults) > 0 {
@@ -125,6 +148,17 @@ func GenerateConsole(report rules.AnalysisReport) string {
 		}
 	}
 
+	// Rules that could not be evaluated (compact)
+	unknownResults := filterByStatus(report.Results, rules.StatusUnknown)
+	if len(unknownResults) > 0 {
+		result.WriteString(color.New(color.Bold, color.FgHiBlack).Sprint("⚪ Not Evaluated\n\n"))
+		for _, r := range unknownResults {
+			result.WriteString(color.HiBlackString("  ? "))
+			result.WriteString(fmt.Sprintf("%s: %s (%s)\n", r.RuleName, r.Message, r.Reason))
+		}
+		result.WriteString("\n")
+	}
+
 	// Healthy Metrics (compact)
 	greenResults := filterByStatus(report.Results, rules.StatusGreen)
 	if len(greenResults) > 0 {
@@ -0,0 +1,105 @@
+package reporter
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/stackrox/sensor-metrics-analyzer/internal/rules"
+)
+
+// GenerateCoverageConsole creates a console report of rule coverage for a scrape
+func GenerateCoverageConsole(report rules.CoverageReport) string {
+	var result strings.Builder
+
+	result.WriteString(color.New(color.Bold).Sprint("Rule Coverage Report\n\n"))
+	result.WriteString(fmt.Sprintf("Metrics: %s (ACS %s)\n\n", report.Source, describeVersion(report.ACSVersion)))
+
+	result.WriteString(color.New(color.Bold).Sprint("Summary\n"))
+	t := table.NewWriter()
+	var tableBuf bytes.Buffer
+	t.SetOutputMirror(&tableBuf)
+	t.AppendHeader(table.Row{"Coverage", "Count"})
+	t.AppendRow(table.Row{"Rules checked", report.Summary.Rules})
+	t.AppendRow(table.Row{color.YellowString("Rules with absent metrics"), report.Summary.RulesWithMissing})
+	t.AppendRow(table.Row{"Rules skipped for ACS version", report.Summary.SkippedRules})
+	t.AppendRow(table.Row{"Sensor metrics in scrape", report.Summary.SensorMetrics})
+	t.AppendRow(table.Row{color.YellowString("Sensor metrics without rules"), report.Summary.UncoveredMetrics})
+	t.SetStyle(table.StyleRounded)
+	t.Render()
+	result.WriteString(tableBuf.String())
+	result.WriteString("\n")
+
+	if len(report.Rules) > 0 {
+		result.WriteString(color.New(color.Bold).Sprint("Rules With Absent Metrics\n\n"))
+		for _, r := range report.Rules {
+			result.WriteString(fmt.Sprintf("  %s (%d of %d absent)\n", r.RuleName, len(r.Missing), len(r.Missing)+r.Matched))
+			for _, metric := range r.Missing {
+				result.WriteString(fmt.Sprintf("    - %s\n", metric))
+			}
+		}
+		result.WriteString("\n")
+	}
+
+	if len(report.Uncovered) > 0 {
+		result.WriteString(color.New(color.Bold).Sprint("Sensor Metrics Without Rules\n\n"))
+		lastType := ""
+		for _, m := range report.Uncovered {
+			if m.Type != lastType {
+				result.WriteString(fmt.Sprintf("  %s:\n", m.Type))
+				lastType = m.Type
+			}
+			result.WriteString(fmt.Sprintf("    - %s (%d series)\n", m.Name, m.Series))
+		}
+		result.WriteString("\n")
+	}
+
+	return result.String()
+}
+
+// GenerateCoverageMarkdown creates a markdown report of rule coverage for a scrape
+func GenerateCoverageMarkdown(report rules.CoverageReport) string {
+	var result strings.Builder
+
+	result.WriteString("# Rule Coverage Report\n\n")
+	result.WriteString(fmt.Sprintf("- **Metrics:** %s (ACS %s)\n\n", report.Source, describeVersion(report.ACSVersion)))
+
+	result.WriteString("## Summary\n\n")
+	result.WriteString(fmt.Sprintf("- **Rules checked:** %d\n", report.Summary.Rules))
+	result.WriteString(fmt.Sprintf("- **Rules with absent metrics:** %d\n", report.Summary.RulesWithMissing))
+	result.WriteString(fmt.Sprintf("- **Rules skipped for ACS version:** %d\n", report.Summary.SkippedRules))
+	result.WriteString(fmt.Sprintf("- **Sensor metrics in scrape:** %d\n", report.Summary.SensorMetrics))
+	result.WriteString(fmt.Sprintf("- **Sensor metrics without rules:** %d\n", report.Summary.UncoveredMetrics))
+
+	if len(report.Rules) > 0 {
+		result.WriteString("\n## Rules With Absent Metrics\n\n")
+		result.WriteString("| Rule | Absent Metrics |\n")
+		result.WriteString("|------|----------------|\n")
+		for _, r := range report.Rules {
+			result.WriteString(fmt.Sprintf("| %s | %s |\n",
+				escapeMarkdownCell(r.RuleName),
+				escapeMarkdownCell(strings.Join(r.Missing, ", "))))
+		}
+	}
+
+	if len(report.Uncovered) > 0 {
+		result.WriteString("\n## Sensor Metrics Without Rules\n\n")
+		result.WriteString("| Metric | Type | Series | Help |\n")
+		result.WriteString("|--------|------|--------|------|\n")
+		for _, m := range report.Uncovered {
+			result.WriteString(fmt.Sprintf("| %s | %s | %d | %s |\n",
+				m.Name, m.Type, m.Series, escapeMarkdownCell(m.Help)))
+		}
+	}
+
+	return result.String()
+}
+
+func describeVersion(version string) string {
+	if version == "" {
+		return "unknown, all rules checked"
+	}
+	return version
+}
@@ -0,0 +1,166 @@
+package reporter
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/stackrox/sensor-metrics-analyzer/internal/rules"
+)
+
+// diffSections lists the change kinds in report order with their headings
+var diffSections = []struct {
+	change  rules.ChangeKind
+	heading string
+}{
+	{rules.ChangeRegressed, "🔺 Regressions"},
+	{rules.ChangeImproved, "✅ Improvements"},
+	{rules.ChangeAdded, "🆕 New Results"},
+	{rules.ChangeRemoved, "🗑️ Removed Results"},
+	{rules.ChangeUnchanged, "➖ Unchanged Status, Changed Value"},
+}
+
+// GenerateDiffConsole creates a console report of the changes between two analyses
+func GenerateDiffConsole(diff rules.ReportDiff) string {
+	var result strings.Builder
+
+	result.WriteString(color.New(color.Bold).Sprint("Analysis Diff Report\n\n"))
+	result.WriteString(fmt.Sprintf("Before: %s\n", describeReport(diff.Before)))
+	result.WriteString(fmt.Sprintf("After:  %s\n\n", describeReport(diff.After)))
+
+	result.WriteString(color.New(color.Bold).Sprint("Summary\n"))
+	t := table.NewWriter()
+	var tableBuf bytes.Buffer
+	t.SetOutputMirror(&tableBuf)
+	t.AppendHeader(table.Row{"Change", "Count"})
+	t.AppendRow(table.Row{color.RedString("🔺 Regressed"), diff.Summary.Regressed})
+	t.AppendRow(table.Row{color.GreenString("✅ Improved"), diff.Summary.Improved})
+	t.AppendRow(table.Row{"🆕 Added", diff.Summary.Added})
+	t.AppendRow(table.Row{"🗑️ Removed", diff.Summary.Removed})
+	t.AppendRow(table.Row{"➖ Unchanged", diff.Summary.Unchanged})
+	t.SetStyle(table.StyleRounded)
+	t.Render()
+	result.WriteString(tableBuf.String())
+	result.WriteString("\n")
+
+	for _, section := range diffSections {
+		entries := filterDiff(diff.Results, section.change)
+		if len(entries) == 0 {
+			continue
+		}
+		result.WriteString(color.New(color.Bold).Sprintf("%s\n\n", section.heading))
+		for _, d := range entries {
+			result.WriteString(fmt.Sprintf("  %s: %s", d.RuleName, FormatStatusTransition(d)))
+			if values := FormatValueChange(d); values != "" {
+				result.WriteString(fmt.Sprintf(", value %s", values))
+			}
+			result.WriteString("\n")
+			if d.After != nil && d.Change != rules.ChangeUnchanged {
+				result.WriteString(fmt.Sprintf("    %s\n", d.After.Message))
+			}
+		}
+		result.WriteString("\n")
+	}
+
+	return result.String()
+}
+
+// GenerateDiffMarkdown creates a markdown report of the changes between two analyses
+func GenerateDiffMarkdown(diff rules.ReportDiff) string {
+	var result strings.Builder
+
+	result.WriteString("# Analysis Diff Report\n\n")
+	result.WriteString(fmt.Sprintf("- **Before:** %s\n", describeReport(diff.Before)))
+	result.WriteString(fmt.Sprintf("- **After:** %s\n\n", describeReport(diff.After)))
+
+	result.WriteString("## Summary\n\n")
+	result.WriteString(fmt.Sprintf("- 🔺 **Regressed:** %d\n", diff.Summary.Regressed))
+	result.WriteString(fmt.Sprintf("- ✅ **Improved:** %d\n", diff.Summary.Improved))
+	result.WriteString(fmt.Sprintf("- 🆕 **Added:** %d\n", diff.Summary.Added))
+	result.WriteString(fmt.Sprintf("- 🗑️ **Removed:** %d\n", diff.Summary.Removed))
+	result.WriteString(fmt.Sprintf("- ➖ **Unchanged:** %d\n", diff.Summary.Unchanged))
+
+	for _, section := range diffSections {
+		entries := filterDiff(diff.Results, section.change)
+		if len(entries) == 0 {
+			continue
+		}
+		result.WriteString(fmt.Sprintf("\n## %s\n\n", section.heading))
+		result.WriteString("| Rule | Status | Value | Message |\n")
+		result.WriteString("|------|--------|-------|---------|\n")
+		for _, d := range entries {
+			message := ""
+			if d.After != nil {
+				message = d.After.Message
+			}
+			result.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n",
+				escapeMarkdownCell(d.RuleName),
+				FormatStatusTransition(d),
+				FormatValueChange(d),
+				escapeMarkdownCell(message)))
+		}
+	}
+
+	return result.String()
+}
+
+// FormatStatusTransition formats a status change, e.g. "RED → GREEN" or "new YELLOW"
+func FormatStatusTransition(d rules.ResultDiff) string {
+	switch {
+	case d.Before == nil:
+		return fmt.Sprintf("new %s", d.After.Status)
+	case d.After == nil:
+		return fmt.Sprintf("was %s", d.Before.Status)
+	case strings.EqualFold(string(d.Before.Status), string(d.After.Status)):
+		return string(d.After.Status)
+	}
+	return fmt.Sprintf("%s → %s", d.Before.Status, d.After.Status)
+}
+
+// FormatValueChange formats a value change with its delta, e.g. "120 → 15 (-105)".
+// It returns an empty string unless the result is in both analyses.
+func FormatValueChange(d rules.ResultDiff) string {
+	if d.Before == nil || d.After == nil {
+		return ""
+	}
+	if d.Delta() == 0 {
+		return formatNumber(d.After.Value)
+	}
+	sign := ""
+	if d.Delta() > 0 {
+		sign = "+"
+	}
+	return fmt.Sprintf("%s → %s (%s%s)", formatNumber(d.Before.Value), formatNumber(d.After.Value), sign, formatNumber(d.Delta()))
+}
+
+// filterDiff returns the results of one change kind. Unchanged results are
+// only included when their value changed.
+func filterDiff(results []rules.ResultDiff, change rules.ChangeKind) []rules.ResultDiff {
+	var filtered []rules.ResultDiff
+	for _, d := range results {
+		if d.Change != change {
+			continue
+		}
+		if change == rules.ChangeUnchanged && d.Delta() == 0 {
+			continue
+		}
+		filtered = append(filtered, d)
+	}
+	return filtered
+}
+
+func describeReport(report rules.AnalysisReport) string {
+	return fmt.Sprintf("%s (ACS %s, load %s, %s)", report.ClusterName, report.ACSVersion, report.LoadLevel,
+		report.Timestamp.Format("2006-01-02 15:04:05"))
+}
+
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(value, 'g', 6, 64)
+}
+
+func escapeMarkdownCell(text string) string {
+	return strings.ReplaceAll(text, "|", "\\|")
+}
@@ -0,0 +1,253 @@
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/stackrox/sensor-metrics-analyzer/internal/rules"
+)
+
+// JSONSchemaVersion is the version of the JSON report schema.
+// Bump it on any backwards-incompatible change (removed or renamed fields,
+// changed types or semantics). Adding optional fields does not require a bump.
+// The schema is documented in docs/usage/json-output.md.
+const JSONSchemaVersion = "2"
+
+// readableSchemaVersions are the older schema versions ParseJSON still accepts.
+// Version 1 predates the UNKNOWN status: rules that could not be evaluated were GREEN.
+var readableSchemaVersions = map[string]bool{"1": true, JSONSchemaVersion: true}
+
+// JSONReport is the stable, machine-readable form of an analysis report
+type JSONReport struct {
+	SchemaVersion       string             `json:"schema_version"`
+	ClusterName         string             `json:"cluster_name"`
+	ACSVersion          string             `json:"acs_version"`
+	LoadLevel           string             `json:"load_level"`
+	LoadDetection       *JSONLoadDetection `json:"load_detection,omitempty"`
+	GeneratedAt         time.Time          `json:"generated_at"`
+	RateIntervalSeconds float64            `json:"rate_interval_seconds,omitempty"`
+	UptimeSeconds       float64            `json:"uptime_seconds,omitempty"`
+	Warnings            []string           `json:"warnings,omitempty"`
+	Summary             JSONSummary        `json:"summary"`
+	Results             []JSONResult       `json:"results"`
+}
+
+// JSONLoadDetection explains how the load level was determined
+type JSONLoadDetection struct {
+	Strategy      string              `json:"strategy"`
+	Levels        []string            `json:"levels,omitempty"`
+	DetectedLevel string              `json:"detected_level"`
+	Overridden    bool                `json:"overridden"`
+	Rules         []JSONLoadRuleScore `json:"rules"`
+}
+
+// JSONLoadRuleScore is the outcome of a single load detection rule
+type JSONLoadRuleScore struct {
+	RuleName  string             `json:"rule_name"`
+	Weight    float64            `json:"weight"`
+	Score     float64            `json:"score"`
+	Level     string             `json:"level,omitempty"`
+	Threshold *JSONLoadThreshold `json:"threshold,omitempty"`
+	Metrics   []JSONLoadMetric   `json:"metrics"`
+}
+
+// JSONLoadMetric is one metric's part in a load detection score
+type JSONLoadMetric struct {
+	Name         string  `json:"name"`
+	Source       string  `json:"source"`
+	Found        bool    `json:"found"`
+	Value        float64 `json:"value"`
+	Weight       float64 `json:"weight"`
+	Contribution float64 `json:"contribution"`
+}
+
+// JSONLoadThreshold is the threshold a load detection score matched
+type JSONLoadThreshold struct {
+	Level    string   `json:"level"`
+	MinValue *float64 `json:"min_value,omitempty"`
+	MaxValue *float64 `json:"max_value,omitempty"`
+}
+
+// JSONSummary contains aggregate counts by status
+type JSONSummary struct {
+	Total   int `json:"total"`
+	Red     int `json:"red"`
+	Yellow  int `json:"yellow"`
+	Green   int `json:"green"`
+	Unknown int `json:"unknown"`
+}
+
+// JSONResult is a single rule evaluation result
+type JSONResult struct {
+	RuleName                 string    `json:"rule_name"`
+	Status                   string    `json:"status"`
+	Reason                   string    `json:"reason,omitempty"`
+	Value                    float64   `json:"value"`
+	Message                  string    `json:"message"`
+	MetricHelp               string    `json:"metric_help,omitempty"`
+	Details                  []string  `json:"details"`
+	ReviewStatus             string    `json:"review_status,omitempty"`
+	PotentialActionUser      string    `json:"potential_action_user,omitempty"`
+	PotentialActionDeveloper string    `json:"potential_action_developer,omitempty"`
+	EvaluatedAt              time.Time `json:"evaluated_at"`
+}
+
+// NewJSONReport converts an analysis report to its JSON schema representation
+func NewJSONReport(report rules.AnalysisReport) JSONReport {
+	out := JSONReport{
+		SchemaVersion:       JSONSchemaVersion,
+		ClusterName:         report.ClusterName,
+		ACSVersion:          report.ACSVersion,
+		LoadLevel:           string(report.LoadLevel),
+		GeneratedAt:         report.Timestamp,
+		RateIntervalSeconds: report.RateInterval.Seconds(),
+		UptimeSeconds:       report.Uptime.Seconds(),
+		Warnings:            report.Warnings,
+		Summary: JSONSummary{
+			Total:   report.Summary.TotalAnalyzed,
+			Red:     report.Summary.RedCount,
+			Yellow:  report.Summary.YellowCount,
+			Green:   report.Summary.GreenCount,
+			Unknown: report.Summary.UnknownCount,
+		},
+		Results: make([]JSONResult, 0, len(report.Results)),
+	}
+
+	if detection := report.LoadDetection; detection.Strategy != "" {
+		out.LoadDetection = &JSONLoadDetection{
+			Strategy:      string(detection.Strategy),
+			DetectedLevel: string(detection.Detected),
+			Overridden:    detection.Overridden,
+			Rules:         make([]JSONLoadRuleScore, 0, len(detection.Rules)),
+		}
+		for _, level := range detection.Levels {
+			out.LoadDetection.Levels = append(out.LoadDetection.Levels, string(level))
+		}
+		for _, v := range detection.Rules {
+			score := JSONLoadRuleScore{
+				RuleName: v.RuleName,
+				Weight:   v.Weight,
+				Score:    v.Score,
+				Level:    string(v.Level),
+				Metrics:  make([]JSONLoadMetric, 0, len(v.Metrics)),
+			}
+			for _, input := range v.Metrics {
+				score.Metrics = append(score.Metrics, JSONLoadMetric(input))
+			}
+			if v.Threshold != nil {
+				score.Threshold = &JSONLoadThreshold{
+					Level:    string(v.Threshold.Level),
+					MinValue: v.Threshold.MinValue,
+					MaxValue: v.Threshold.MaxValue,
+				}
+			}
+			out.LoadDetection.Rules = append(out.LoadDetection.Rules, score)
+		}
+	}
+
+	for _, r := range report.Results {
+		details := r.Details
+		if details == nil {
+			details = []string{}
+		}
+		out.Results = append(out.Results, JSONResult{
+			RuleName:                 r.RuleName,
+			Status:                   string(r.Status),
+			Reason:                   string(r.Reason),
+			Value:                    r.Value,
+			Message:                  r.Message,
+			MetricHelp:               r.MetricHelp,
+			Details:                  details,
+			ReviewStatus:             r.ReviewStatus,
+			PotentialActionUser:      r.PotentialActionUser,
+			PotentialActionDeveloper: r.PotentialActionDeveloper,
+			EvaluatedAt:              r.Timestamp,
+		})
+	}
+
+	return out
+}
+
+// GenerateJSON creates an indented JSON report from analysis results
+func GenerateJSON(report rules.AnalysisReport) (string, error) {
+	data, err := json.MarshalIndent(NewJSONReport(report), "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode JSON report: %w", err)
+	}
+	return string(data) + "\n", nil
+}
+
+// ParseJSON reads a report written by GenerateJSON back into an analysis report
+func ParseJSON(data []byte) (rules.AnalysisReport, error) {
+	var in JSONReport
+	if err := json.Unmarshal(data, &in); err != nil {
+		return rules.AnalysisReport{}, fmt.Errorf("failed to decode JSON report: %w", err)
+	}
+	if !readableSchemaVersions[in.SchemaVersion] {
+		return rules.AnalysisReport{}, fmt.Errorf("unsupported JSON report schema version %q (want %q)", in.SchemaVersion, JSONSchemaVersion)
+	}
+
+	report := rules.AnalysisReport{
+		ClusterName:  in.ClusterName,
+		ACSVersion:   in.ACSVersion,
+		LoadLevel:    rules.LoadLevel(in.LoadLevel),
+		Timestamp:    in.GeneratedAt,
+		RateInterval: time.Duration(in.RateIntervalSeconds * float64(time.Second)),
+		Uptime:       time.Duration(in.UptimeSeconds * float64(time.Second)),
+		Warnings:     in.Warnings,
+		Summary: rules.Summary{
+			TotalAnalyzed: in.Summary.Total,
+			RedCount:      in.Summary.Red,
+			YellowCount:   in.Summary.Yellow,
+			GreenCount:    in.Summary.Green,
+			UnknownCount:  in.Summary.Unknown,
+		},
+		Results: make([]rules.EvaluationResult, 0, len(in.Results)),
+	}
+	if in.LoadDetection != nil {
+		report.LoadDetection = rules.LoadDetection{
+			Strategy:   rules.LoadStrategy(in.LoadDetection.Strategy),
+			Detected:   rules.LoadLevel(in.LoadDetection.DetectedLevel),
+			Overridden: in.LoadDetection.Overridden,
+		}
+		for _, level := range in.LoadDetection.Levels {
+			report.LoadDetection.Levels = append(report.LoadDetection.Levels, rules.LoadLevel(level))
+		}
+		for _, score := range in.LoadDetection.Rules {
+			verdict := rules.LoadRuleVerdict{
+				RuleName: score.RuleName,
+				Weight:   score.Weight,
+				Score:    score.Score,
+				Level:    rules.LoadLevel(score.Level),
+			}
+			for _, input := range score.Metrics {
+				verdict.Metrics = append(verdict.Metrics, rules.LoadMetricInput(input))
+			}
+			if score.Threshold != nil {
+				verdict.Threshold = &rules.LoadDetectionThreshold{
+					Level:    rules.LoadLevel(score.Threshold.Level),
+					MinValue: score.Threshold.MinValue,
+					MaxValue: score.Threshold.MaxValue,
+				}
+			}
+			report.LoadDetection.Rules = append(report.LoadDetection.Rules, verdict)
+		}
+	}
+	for _, r := range in.Results {
+		report.Results = append(report.Results, rules.EvaluationResult{
+			RuleName:                 r.RuleName,
+			Status:                   rules.Status(r.Status),
+			Reason:                   rules.UnknownReason(r.Reason),
+			Value:                    r.Value,
+			Message:                  r.Message,
+			MetricHelp:               r.MetricHelp,
+			Details:                  r.Details,
+			ReviewStatus:             r.ReviewStatus,
+			PotentialActionUser:      r.PotentialActionUser,
+			PotentialActionDeveloper: r.PotentialActionDeveloper,
+			Timestamp:                r.EvaluatedAt,
+		})
+	}
+	return report, nil
+}
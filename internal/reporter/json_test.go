@@ -0,0 +1,187 @@
+package reporter
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stackrox/sensor-metrics-analyzer/internal/rules"
+)
+
+func TestGenerateJSON(t *testing.T) {
+	report := rules.AnalysisReport{
+		ClusterName:  "prod",
+		ACSVersion:   "4.8.0",
+		LoadLevel:    rules.LoadLevelHigh,
+		Timestamp:    time.Date(2026, 1, 30, 10, 0, 0, 0, time.UTC),
+		RateInterval: 5 * time.Minute,
+		Results: []rules.EvaluationResult{
+			{
+				RuleName:            "queue",
+				Status:              rules.StatusRed,
+				Value:               42,
+				Message:             "backed up",
+				Details:             []string{"add: 50"},
+				PotentialActionUser: "investigate",
+			},
+			{
+				RuleName: "gauge",
+				Status:   rules.StatusGreen,
+			},
+		},
+		Summary: rules.Summary{TotalAnalyzed: 2, RedCount: 1, GreenCount: 1},
+	}
+
+	output, err := GenerateJSON(report)
+	if err != nil {
+		t.Fatalf("GenerateJSON() error = %v", err)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal([]byte(output), &decoded); err != nil {
+		t.Fatalf("GenerateJSON() produced invalid JSON: %v", err)
+	}
+
+	if decoded["schema_version"] != JSONSchemaVersion {
+		t.Errorf("GenerateJSON() schema_version = %v, want %v", decoded["schema_version"], JSONSchemaVersion)
+	}
+	if decoded["load_level"] != "high" {
+		t.Errorf("GenerateJSON() load_level = %v, want high", decoded["load_level"])
+	}
+	if decoded["rate_interval_seconds"] != 300.0 {
+		t.Errorf("GenerateJSON() rate_interval_seconds = %v, want 300", decoded["rate_interval_seconds"])
+	}
+
+	summary := decoded["summary"].(map[string]interface{})
+	if summary["total"] != 2.0 || summary["red"] != 1.0 || summary["green"] != 1.0 {
+		t.Errorf("GenerateJSON() summary = %v", summary)
+	}
+
+	results := decoded["results"].([]interface{})
+	if len(results) != 2 {
+		t.Fatalf("GenerateJSON() got %d results, want 2", len(results))
+	}
+	first := results[0].(map[string]interface{})
+	if first["status"] != "RED" || first["potential_action_user"] != "investigate" {
+		t.Errorf("GenerateJSON() first result = %v", first)
+	}
+	second := results[1].(map[string]interface{})
+	if details, ok := second["details"].([]interface{}); !ok || len(details) != 0 {
+		t.Errorf("GenerateJSON() nil details should encode as empty array, got %v", second["details"])
+	}
+}
+
+func TestGenerateBundleJSON(t *testing.T) {
+	bundle := rules.BundleReport{
+		Source:    "bundle.zip",
+		Timestamp: time.Date(2026, 1, 30, 10, 0, 0, 0, time.UTC),
+		Clusters: []rules.ClusterReport{
+			{
+				ClusterName: "prod",
+				File:        "prod/prod-sensor-metrics.txt",
+				Report: rules.AnalysisReport{
+					ClusterName: "prod",
+					Results:     []rules.EvaluationResult{{RuleName: "queue", Status: rules.StatusRed}},
+					Summary:     rules.Summary{TotalAnalyzed: 1, RedCount: 1},
+				},
+			},
+			{
+				ClusterName: "broken",
+				File:        "broken-sensor-metrics.txt",
+				Error:       "failed to parse metrics",
+			},
+		},
+		Summary: rules.Summary{TotalAnalyzed: 1, RedCount: 1},
+	}
+
+	output, err := GenerateBundleJSON(bundle)
+	if err != nil {
+		t.Fatalf("GenerateBundleJSON() error = %v", err)
+	}
+
+	var decoded JSONBundleReport
+	if err := json.Unmarshal([]byte(output), &decoded); err != nil {
+		t.Fatalf("GenerateBundleJSON() produced invalid JSON: %v", err)
+	}
+
+	if decoded.Source != "bundle.zip" || decoded.Summary.Red != 1 {
+		t.Errorf("GenerateBundleJSON() source = %v, summary = %v", decoded.Source, decoded.Summary)
+	}
+	if len(decoded.Clusters) != 2 {
+		t.Fatalf("GenerateBundleJSON() got %d clusters, want 2", len(decoded.Clusters))
+	}
+	if decoded.Clusters[0].Report == nil || decoded.Clusters[0].Report.Summary.Red != 1 {
+		t.Errorf("GenerateBundleJSON() first cluster report = %v", decoded.Clusters[0].Report)
+	}
+	if decoded.Clusters[1].Report != nil || decoded.Clusters[1].Error == "" {
+		t.Errorf("GenerateBundleJSON() failed cluster should have an error and no report, got %+v", decoded.Clusters[1])
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	maxValue := 100.0
+	report := rules.AnalysisReport{
+		ClusterName: "prod",
+		LoadLevel:   rules.LoadLevelLow,
+		LoadDetection: rules.LoadDetection{
+			Strategy: rules.LoadStrategyWeightedVote,
+			Levels:   []rules.LoadLevel{"tiny", rules.LoadLevelLow, "xlarge"},
+			Rules: []rules.LoadRuleVerdict{
+				{RuleName: "cluster_volume", Weight: 2, Score: 42, Level: rules.LoadLevelLow,
+					Threshold: &rules.LoadDetectionThreshold{Level: rules.LoadLevelLow, MaxValue: &maxValue}},
+				{RuleName: "network_flows", Weight: 1},
+			},
+		},
+		Timestamp:    time.Date(2026, 1, 30, 10, 0, 0, 0, time.UTC),
+		RateInterval: time.Minute,
+		Uptime:       90 * time.Minute,
+		Warnings:     []string{"short uptime"},
+		Results: []rules.EvaluationResult{
+			{RuleName: "queue", Status: rules.StatusYellow, Value: 7, Details: []string{"add: 10"}},
+			{RuleName: "renamed", Status: rules.StatusUnknown, Reason: rules.ReasonMetricNotFound, Details: []string{}},
+		},
+		Summary: rules.Summary{TotalAnalyzed: 2, YellowCount: 1, UnknownCount: 1},
+	}
+
+	output, err := GenerateJSON(report)
+	if err != nil {
+		t.Fatalf("GenerateJSON() error = %v", err)
+	}
+	parsed, err := ParseJSON([]byte(output))
+	if err != nil {
+		t.Fatalf("ParseJSON() error = %v", err)
+	}
+
+	if parsed.ClusterName != "prod" || parsed.LoadLevel != rules.LoadLevelLow || parsed.RateInterval != time.Minute {
+		t.Errorf("ParseJSON() metadata = %+v", parsed)
+	}
+	if parsed.Uptime != 90*time.Minute || len(parsed.Warnings) != 1 {
+		t.Errorf("ParseJSON() uptime = %v, warnings = %v", parsed.Uptime, parsed.Warnings)
+	}
+	if parsed.Summary != report.Summary {
+		t.Errorf("ParseJSON() summary = %+v, want %+v", parsed.Summary, report.Summary)
+	}
+	if len(parsed.Results) != 2 || parsed.Results[0].Status != rules.StatusYellow || parsed.Results[0].Value != 7 {
+		t.Fatalf("ParseJSON() results = %+v", parsed.Results)
+	}
+	if r := parsed.Results[1]; r.Status != rules.StatusUnknown || r.Reason != rules.ReasonMetricNotFound {
+		t.Errorf("ParseJSON() unknown result = %+v", r)
+	}
+	detection := parsed.LoadDetection
+	if detection.Strategy != rules.LoadStrategyWeightedVote || len(detection.Rules) != 2 || len(detection.Levels) != 3 || detection.Levels[2] != "xlarge" {
+		t.Fatalf("ParseJSON() load detection = %+v", detection)
+	}
+	if v := detection.Rules[0]; v.Score != 42 || v.Weight != 2 || v.Threshold == nil || v.Threshold.MinValue != nil || *v.Threshold.MaxValue != 100 {
+		t.Errorf("ParseJSON() first load rule = %+v", v)
+	}
+	if v := detection.Rules[1]; v.Level != "" || v.Threshold != nil {
+		t.Errorf("ParseJSON() load rule without a level = %+v", v)
+	}
+
+	if _, err := ParseJSON([]byte(`{"schema_version": "1"}`)); err != nil {
+		t.Errorf("ParseJSON() error = %v for a version 1 report", err)
+	}
+	if _, err := ParseJSON([]byte(`{"schema_version": "99"}`)); err == nil {
+		t.Error("ParseJSON() expected error for unsupported schema version")
+	}
+}
@@ -0,0 +1,113 @@
+package reporter
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/stackrox/sensor-metrics-analyzer/internal/rules"
+)
+
+// FormatLoadThreshold describes the score range of a load detection threshold
+func FormatLoadThreshold(t *rules.LoadDetectionThreshold) string {
+	if t == nil {
+		return "no threshold matched"
+	}
+	switch {
+	case t.MinValue != nil && t.MaxValue != nil:
+		return fmt.Sprintf("%s ≤ score < %s", formatNumber(*t.MinValue), formatNumber(*t.MaxValue))
+	case t.MinValue != nil:
+		return fmt.Sprintf("score ≥ %s", formatNumber(*t.MinValue))
+	case t.MaxValue != nil:
+		return fmt.Sprintf("score < %s", formatNumber(*t.MaxValue))
+	}
+	return "any score"
+}
+
+// FormatLoadLevel describes how the load level of a report was determined,
+// e.g. "high (max of 2 rules)" or "low (overridden, detected medium)"
+func FormatLoadLevel(report rules.AnalysisReport) string {
+	detection := report.LoadDetection
+	switch {
+	case detection.Overridden:
+		return fmt.Sprintf("%s (overridden, detected %s)", report.LoadLevel, detection.Detected)
+	case detection.Strategy == "":
+		return string(report.LoadLevel)
+	case len(detection.Rules) == 0:
+		return fmt.Sprintf("%s (default, no load detection rules)", report.LoadLevel)
+	case !anyLevelDetected(detection):
+		return fmt.Sprintf("%s (default, no load detection rule matched)", report.LoadLevel)
+	case len(detection.Rules) == 1:
+		return fmt.Sprintf("%s (%s)", report.LoadLevel, detection.Rules[0].RuleName)
+	}
+	return fmt.Sprintf("%s (%s of %d rules)", report.LoadLevel, detection.Strategy, len(detection.Rules))
+}
+
+// FormatLoadVerdict summarizes one load detection rule's score and level
+func FormatLoadVerdict(v rules.LoadRuleVerdict) string {
+	if v.Level == "" {
+		if !anyMetricFound(v) {
+			return "no metrics found, abstained"
+		}
+		return fmt.Sprintf("score %s, no threshold matched, abstained", formatNumber(v.Score))
+	}
+	return fmt.Sprintf("score %s → %s (%s), weight %s",
+		formatNumber(v.Score), v.Level, FormatLoadThreshold(v.Threshold), formatNumber(v.Weight))
+}
+
+func anyLevelDetected(detection rules.LoadDetection) bool {
+	for _, v := range detection.Rules {
+		if v.Level != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func anyMetricFound(v rules.LoadRuleVerdict) bool {
+	for _, input := range v.Metrics {
+		if input.Found {
+			return true
+		}
+	}
+	return false
+}
+
+// writeLoadDetectionConsole renders the load detection breakdown of a report
+func writeLoadDetectionConsole(result *strings.Builder, report rules.AnalysisReport) {
+	detection := report.LoadDetection
+	if detection.Strategy == "" {
+		return
+	}
+
+	result.WriteString(color.New(color.Bold).Sprint("Load Detection\n"))
+	result.WriteString(fmt.Sprintf("Strategy: %s\n", detection.Strategy))
+	if len(detection.Levels) > 0 {
+		result.WriteString(fmt.Sprintf("Levels: %s\n", rules.FormatLoadLevels(detection.Levels)))
+	}
+	if detection.Overridden {
+		result.WriteString(fmt.Sprintf("Overridden: %s was used instead of the detected %s\n", report.LoadLevel, detection.Detected))
+	}
+	for _, v := range detection.Rules {
+		result.WriteString(fmt.Sprintf("%s: %s\n", color.New(color.Bold).Sprint(v.RuleName), FormatLoadVerdict(v)))
+
+		t := table.NewWriter()
+		var tableBuf bytes.Buffer
+		t.SetOutputMirror(&tableBuf)
+		t.AppendHeader(table.Row{"Metric", "Source", "Value", "Weight", "Contribution"})
+		for _, input := range v.Metrics {
+			value, contribution := "missing", "-"
+			if input.Found {
+				value = formatNumber(input.Value)
+				contribution = formatNumber(input.Contribution)
+			}
+			t.AppendRow(table.Row{input.Name, input.Source, value, formatNumber(input.Weight), contribution})
+		}
+		t.SetStyle(table.StyleRounded)
+		t.Render()
+		result.WriteString(tableBuf.String())
+	}
+	result.WriteString("\n")
+}
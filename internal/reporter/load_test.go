@@ -0,0 +1,127 @@
+package reporter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stackrox/sensor-metrics-analyzer/internal/rules"
+)
+
+func TestFormatLoadThreshold(t *testing.T) {
+	bound := func(v float64) *float64 { return &v }
+
+	tests := map[string]struct {
+		threshold *rules.LoadDetectionThreshold
+		want      string
+	}{
+		"should describe a closed range": {
+			threshold: &rules.LoadDetectionThreshold{MinValue: bound(100), MaxValue: bound(500)},
+			want:      "100 ≤ score < 500",
+		},
+		"should describe a lower bound": {
+			threshold: &rules.LoadDetectionThreshold{MinValue: bound(0)},
+			want:      "score ≥ 0",
+		},
+		"should describe an upper bound": {
+			threshold: &rules.LoadDetectionThreshold{MaxValue: bound(100)},
+			want:      "score < 100",
+		},
+		"should describe an open range": {
+			threshold: &rules.LoadDetectionThreshold{},
+			want:      "any score",
+		},
+		"should describe a missing threshold": {
+			want: "no threshold matched",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := FormatLoadThreshold(tt.threshold); got != tt.want {
+				t.Errorf("FormatLoadThreshold() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatLoadLevel(t *testing.T) {
+	matched := rules.LoadRuleVerdict{RuleName: "cluster_volume", Level: rules.LoadLevelHigh}
+	abstained := rules.LoadRuleVerdict{RuleName: "network_volume"}
+
+	tests := map[string]struct {
+		report rules.AnalysisReport
+		want   string
+	}{
+		"should show the bare level of reports without a breakdown": {
+			report: rules.AnalysisReport{LoadLevel: rules.LoadLevelLow},
+			want:   "low",
+		},
+		"should name a single rule": {
+			report: rules.AnalysisReport{LoadLevel: rules.LoadLevelHigh, LoadDetection: rules.LoadDetection{
+				Strategy: rules.LoadStrategyMax, Detected: rules.LoadLevelHigh, Rules: []rules.LoadRuleVerdict{matched},
+			}},
+			want: "high (cluster_volume)",
+		},
+		"should name the strategy for several rules": {
+			report: rules.AnalysisReport{LoadLevel: rules.LoadLevelHigh, LoadDetection: rules.LoadDetection{
+				Strategy: rules.LoadStrategyWeightedVote, Detected: rules.LoadLevelHigh, Rules: []rules.LoadRuleVerdict{matched, abstained},
+			}},
+			want: "high (weighted_vote of 2 rules)",
+		},
+		"should explain the default": {
+			report: rules.AnalysisReport{LoadLevel: rules.LoadLevelMedium, LoadDetection: rules.LoadDetection{
+				Strategy: rules.LoadStrategyMax, Detected: rules.LoadLevelMedium, Rules: []rules.LoadRuleVerdict{abstained},
+			}},
+			want: "medium (default, no load detection rule matched)",
+		},
+		"should show the detected level when overridden": {
+			report: rules.AnalysisReport{LoadLevel: rules.LoadLevelLow, LoadDetection: rules.LoadDetection{
+				Strategy: rules.LoadStrategyMax, Detected: rules.LoadLevelHigh, Overridden: true, Rules: []rules.LoadRuleVerdict{matched},
+			}},
+			want: "low (overridden, detected high)",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := FormatLoadLevel(tt.report); got != tt.want {
+				t.Errorf("FormatLoadLevel() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateConsoleLoadDetection(t *testing.T) {
+	report := rules.AnalysisReport{
+		LoadLevel: rules.LoadLevelMedium,
+		LoadDetection: rules.LoadDetection{
+			Strategy: rules.LoadStrategyMax,
+			Detected: rules.LoadLevelMedium,
+			Rules: []rules.LoadRuleVerdict{{
+				RuleName: "cluster_volume",
+				Weight:   1,
+				Score:    250,
+				Level:    rules.LoadLevelMedium,
+				Threshold: &rules.LoadDetectionThreshold{
+					Level: rules.LoadLevelMedium,
+				},
+				Metrics: []rules.LoadMetricInput{
+					{Name: "containers", Source: "rox_sensor_num_containers", Found: true, Value: 250, Weight: 1, Contribution: 250},
+					{Name: "pods", Source: "rox_sensor_num_pods", Weight: 0.5},
+				},
+			}},
+		},
+	}
+
+	output := GenerateConsole(report)
+	for _, want := range []string{
+		"Load Level: medium (cluster_volume)",
+		"score 250 → medium (any score), weight 1",
+		"rox_sensor_num_containers",
+		"missing",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("GenerateConsole() missing %q in:\n%s", want, output)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"text/template"
+	"time"
 
 	"github.com/stackrox/sensor-metrics-analyzer/internal/rules"
 )
@@ -31,9 +32,21 @@ func templateFuncs() template.FuncMap {
 		"formatBytes":   formatBytes,
 		"formatPercent": formatPercent,
 		"formatValue":   formatValue,
+		"formatNumber":  formatNumber,
+		"formatUptime":  formatUptime,
+
+		"formatLoadLevel":     FormatLoadLevel,
+		"formatLoadLevels":    rules.FormatLoadLevels,
+		"formatLoadThreshold": FormatLoadThreshold,
+		"formatLoadVerdict":   FormatLoadVerdict,
 	}
 }
 
+// formatUptime rounds an uptime to whole seconds
+func formatUptime(uptime time.Duration) string {
+	return uptime.Round(time.Second).String()
+}
+
 // formatBytes formats byte values
 func formatBytes(bytes float64) string {
 	if bytes < 1024 {
@@ -75,14 +88,16 @@ func GenerateMarkdownFromTemplate(report rules.AnalysisReport, templatePath stri
 
 	data := struct {
 		rules.AnalysisReport
-		RedResults    []rules.EvaluationResult
-		YellowResults []rules.EvaluationResult
-		GreenResults  []rules.EvaluationResult
+		RedResults     []rules.EvaluationResult
+		YellowResults  []rules.EvaluationResult
+		GreenResults   []rules.EvaluationResult
+		UnknownResults []rules.EvaluationResult
 	}{
 		AnalysisReport: report,
 		RedResults:     filterByStatus(report.Results, rules.StatusRed),
 		YellowResults:  filterByStatus(report.Results, rules.StatusYellow),
 		GreenResults:   filterByStatus(report.Results, rules.StatusGreen),
+		UnknownResults: filterByStatus(report.Results, rules.StatusUnknown),
 	}
 
 	return ExecuteTemplate(tmpl, data)
@@ -0,0 +1,446 @@
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Severity of a lint diagnostic
+type Severity string
+
+const (
+	SeverityError   Severity = "error"   // The file is rejected when loading rules
+	SeverityWarning Severity = "warning" // The rule loads but is likely wrong
+)
+
+// Diagnostic is a problem found in a rule file
+type Diagnostic struct {
+	File     string
+	Line     int // 1-based; 0 when the position is unknown
+	Column   int // 1-based; 0 when the position is unknown
+	Severity Severity
+	Message  string
+}
+
+// String formats the diagnostic as file:line:column: severity: message
+func (d Diagnostic) String() string {
+	position := d.File
+	if d.Line > 0 {
+		position += fmt.Sprintf(":%d", d.Line)
+		if d.Column > 0 {
+			position += fmt.Sprintf(":%d", d.Column)
+		}
+	}
+	return fmt.Sprintf("%s: %s: %s", position, d.Severity, d.Message)
+}
+
+// LintResult holds every diagnostic of a rules directory
+type LintResult struct {
+	Diagnostics []Diagnostic // Sorted by file and line
+	Rules       []Rule       // Rules without errors
+	LoadRules   []LoadDetectionRule
+	LoadLevels  []LoadLevel
+}
+
+// Errors counts the diagnostics with error severity
+func (r LintResult) Errors() int {
+	return r.count(SeverityError)
+}
+
+// Warnings counts the diagnostics with warning severity
+func (r LintResult) Warnings() int {
+	return r.count(SeverityWarning)
+}
+
+func (r LintResult) count(severity Severity) int {
+	n := 0
+	for _, d := range r.Diagnostics {
+		if d.Severity == severity {
+			n++
+		}
+	}
+	return n
+}
+
+// linter collects diagnostics across files
+type linter struct {
+	diagnostics []Diagnostic
+}
+
+// errorCount counts the errors found so far
+func (l *linter) errorCount() int {
+	return LintResult{Diagnostics: l.diagnostics}.Errors()
+}
+
+func (l *linter) add(file string, line int, severity Severity, format string, args ...interface{}) {
+	l.diagnostics = append(l.diagnostics, Diagnostic{
+		File:     file,
+		Line:     line,
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// LintRules validates every rule, rule test and load detection rule file and
+// reports all problems instead of stopping at the first invalid file. Besides
+// validation errors it flags unknown TOML keys and lints rules for likely
+// mistakes.
+func LintRules(rulesDir, loadLevelDir string) (LintResult, error) {
+	var result LintResult
+	l := &linter{}
+
+	files, err := filepath.Glob(filepath.Join(rulesDir, "*.toml"))
+	if err != nil {
+		return result, fmt.Errorf("failed to glob rules directory: %w", err)
+	}
+	loadFiles, err := filepath.Glob(filepath.Join(loadLevelDir, "*.toml"))
+	if err != nil {
+		return result, fmt.Errorf("failed to glob load detection rules directory: %w", err)
+	}
+
+	for _, file := range loadFiles {
+		if rule, ok := l.lintLoadDetectionRule(file); ok {
+			result.LoadRules = append(result.LoadRules, rule)
+		}
+	}
+	result.LoadLevels, err = LoadTiers(result.LoadRules)
+	if err != nil {
+		l.add(loadLevelDir, 0, SeverityError, "%v", err)
+	}
+
+	ruleFiles := make(map[string]bool)
+	for _, file := range files {
+		if !IsTestFile(file) {
+			ruleFiles[file] = true
+		}
+	}
+
+	var decoded []decodedRule
+	for _, file := range files {
+		if IsTestFile(file) {
+			l.lintTestFile(file, ruleFiles)
+			continue
+		}
+		rule, ok := l.lintRule(file, result.LoadLevels)
+		if rule.source == "" {
+			continue
+		}
+		decoded = append(decoded, rule)
+		if ok {
+			result.Rules = append(result.Rules, rule.Rule)
+		}
+	}
+	l.lintDuplicates(decoded)
+
+	sort.SliceStable(l.diagnostics, func(i, j int) bool {
+		a, b := l.diagnostics[i], l.diagnostics[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+	result.Diagnostics = l.diagnostics
+	return result, nil
+}
+
+// decode reads and decodes a TOML file, reporting syntax errors and unknown keys
+func (l *linter) decode(file string, v interface{}) (string, bool) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		l.add(file, 0, SeverityError, "failed to read file: %v", err)
+		return "", false
+	}
+	source := string(data)
+
+	meta, err := toml.Decode(source, v)
+	if err != nil {
+		var parseErr toml.ParseError
+		if errors.As(err, &parseErr) {
+			l.diagnostics = append(l.diagnostics, Diagnostic{
+				File:     file,
+				Line:     parseErr.Position.Line,
+				Column:   parseErr.Position.Col,
+				Severity: SeverityError,
+				Message:  parseErr.Message,
+			})
+		} else {
+			l.add(file, 0, SeverityError, "%v", err)
+		}
+		return source, false
+	}
+
+	// The loader ignores unknown keys, so they only fail validation with --strict
+	for _, key := range meta.Undecoded() {
+		l.add(file, keyLine(source, key), SeverityWarning, "unknown key %q", key.String())
+	}
+	return source, true
+}
+
+func (l *linter) lintLoadDetectionRule(file string) (LoadDetectionRule, bool) {
+	var rule LoadDetectionRule
+	if _, ok := l.decode(file, &rule); !ok {
+		return rule, false
+	}
+	if rule.DisplayName == "" {
+		rule.DisplayName = strings.TrimSuffix(filepath.Base(file), ".toml")
+	}
+	if err := ValidateLoadDetectionRule(rule); err != nil {
+		l.add(file, 0, SeverityError, "%v", err)
+		return rule, false
+	}
+	return rule, true
+}
+
+func (l *linter) lintTestFile(file string, ruleFiles map[string]bool) {
+	ruleFile := strings.TrimSuffix(file, TestFileSuffix) + ".toml"
+	if !ruleFiles[ruleFile] {
+		l.add(file, 0, SeverityError, "no rule file %s for these tests", filepath.Base(ruleFile))
+	}
+
+	var tests struct {
+		Tests []RuleTest `toml:"tests"`
+	}
+	if _, ok := l.decode(file, &tests); !ok {
+		return
+	}
+	for i, test := range tests.Tests {
+		if err := ValidateRuleTest(test); err != nil {
+			l.add(file, 0, SeverityError, "tests[%d]: %v", i, err)
+		}
+	}
+}
+
+// decodedRule is a rule file that could be decoded, valid or not
+type decodedRule struct {
+	Rule
+	file   string
+	source string
+}
+
+// lintRule validates and lints a rule file. The source of the returned rule is
+// empty if the file could not be decoded; ok is false if it has errors.
+func (l *linter) lintRule(file string, loadLevels []LoadLevel) (decodedRule, bool) {
+	errorsBefore := l.errorCount()
+	var rule Rule
+	source, ok := l.decode(file, &rule)
+	if !ok {
+		return decodedRule{file: file}, false
+	}
+
+	if err := ValidateRule(rule); err != nil {
+		l.add(file, 0, SeverityError, "%v", err)
+	}
+	for _, level := range UndeclaredLoadLevels(rule, loadLevels) {
+		l.add(file, keyLine(source, toml.Key{"load_level_thresholds", string(level)}), SeverityError,
+			"load_level_thresholds.%s: load level is not declared by any load detection rule (declared: %s)", level, FormatLoadLevels(loadLevels))
+	}
+	valid := l.errorCount() == errorsBefore
+
+	l.lintThresholds(file, source, rule)
+	l.lintPlaceholders(file, source, rule)
+	// +Inf overflow rules suggest an action naming the highest bucket on their own
+	if canBeRed(rule) && rule.RuleType != RuleTypeHistogramInfOverflow && (rule.Remediation == nil || strings.TrimSpace(rule.Remediation.Red) == "") {
+		l.add(file, keyLine(source, toml.Key{"remediation"}), SeverityWarning, "no remediation.red for a rule that can turn RED")
+	}
+	return decodedRule{Rule: rule, file: file, source: source}, valid
+}
+
+// lintThresholds flags low > high for rule types whose validation does not already reject it
+func (l *linter) lintThresholds(file, source string, rule Rule) {
+	switch rule.RuleType {
+	case RuleTypeQueue, RuleTypeCacheHit:
+	default:
+		return
+	}
+	if rule.Thresholds.Low > rule.Thresholds.High {
+		l.add(file, keyLine(source, toml.Key{"thresholds", "low"}), SeverityWarning,
+			"thresholds.low (%g) is greater than thresholds.high (%g), the yellow band is empty", rule.Thresholds.Low, rule.Thresholds.High)
+	}
+}
+
+// lintPlaceholders flags message placeholders the evaluator never fills for the rule type
+func (l *linter) lintPlaceholders(file, source string, rule Rule) {
+	filled, dynamic := filledPlaceholders(rule)
+	if dynamic {
+		return
+	}
+
+	messages := []struct {
+		key      toml.Key
+		template string
+	}{
+		{toml.Key{"messages", "green"}, rule.Messages.Green},
+		{toml.Key{"messages", "yellow"}, rule.Messages.Yellow},
+		{toml.Key{"messages", "red"}, rule.Messages.Red},
+	}
+	if rule.CompositeConfig != nil {
+		for _, check := range rule.CompositeConfig.Checks {
+			messages = append(messages, struct {
+				key      toml.Key
+				template string
+			}{toml.Key{"composite_config", "checks", "message"}, check.Message})
+		}
+	}
+
+	for _, m := range messages {
+		for _, placeholder := range findPlaceholders(m.template) {
+			name := placeholder
+			if rule.RuleType != RuleTypeComposite {
+				// Composite messages only replace plain {name} placeholders
+				name = strings.SplitN(placeholder, ":", 2)[0]
+			}
+			if !filled[name] {
+				l.add(file, keyLine(source, m.key), SeverityWarning,
+					"%s: placeholder {%s} is never filled for %s rules (available: %s)",
+					m.key, placeholder, rule.RuleType, formatPlaceholders(filled))
+			}
+		}
+	}
+}
+
+// lintDuplicates flags rules of the same type evaluating the same series
+func (l *linter) lintDuplicates(decoded []decodedRule) {
+	type target struct {
+		ruleType      RuleType
+		metricName    string
+		labelSelector string
+	}
+	seen := make(map[target]string)
+	for _, rule := range decoded {
+		if rule.MetricName == "" {
+			continue
+		}
+		key := target{rule.RuleType, rule.MetricName, rule.LabelSelector}
+		if first, exists := seen[key]; exists {
+			l.add(rule.file, keyLine(rule.source, toml.Key{"metric_name"}), SeverityWarning,
+				"duplicate metric_name %q, already evaluated by %s", rule.MetricName, filepath.Base(first))
+			continue
+		}
+		seen[key] = rule.file
+	}
+}
+
+// filledPlaceholders returns the placeholders the evaluator fills in the
+// messages of a rule. dynamic is true when the placeholders depend on the metrics.
+func filledPlaceholders(rule Rule) (filled map[string]bool, dynamic bool) {
+	filled = map[string]bool{"value": true}
+	switch rule.RuleType {
+	case RuleTypeGauge:
+		filled["value_human"] = true
+	case RuleTypePercentage:
+		filled["numerator"] = true
+		filled["denominator"] = true
+	case RuleTypeQueue:
+		filled["add"] = true
+		filled["remove"] = true
+		filled["diff"] = true
+	case RuleTypeHistogram:
+		for _, name := range []string{"count", "mean", "p50", "p75", "p95", "p99"} {
+			filled[name] = true
+		}
+		if rule.HistogramConfig != nil {
+			if statistic, err := ParseHistogramStatistic(rule.HistogramConfig.Statistic); err == nil {
+				filled[statistic.Name] = true
+			}
+		}
+	case RuleTypeSummary:
+		// Quantile placeholders depend on the quantiles the summary exposes
+		filled["count"] = true
+		filled["mean"] = true
+		return filled, true
+	case RuleTypeCardinality:
+		filled["series"] = true
+		filled["top_label"] = true
+		filled["top_label_values"] = true
+	case RuleTypeCacheHit:
+		filled["hits"] = true
+		filled["misses"] = true
+	case RuleTypeComposite:
+		filled = map[string]bool{}
+		if rule.CompositeConfig != nil {
+			for _, metric := range rule.CompositeConfig.Metrics {
+				filled[metric.Name] = true
+			}
+		}
+	case RuleTypeExpression:
+		// Label names of the expression result are placeholders too
+		return filled, true
+	case RuleTypeHistogramInfOverflow:
+		// Messages are built in
+		filled = map[string]bool{}
+	}
+	return filled, false
+}
+
+func formatPlaceholders(filled map[string]bool) string {
+	names := make([]string, 0, len(filled))
+	for name := range filled {
+		names = append(names, "{"+name+"}")
+	}
+	sort.Strings(names)
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, ", ")
+}
+
+// canBeRed reports whether a rule can produce a RED result
+func canBeRed(rule Rule) bool {
+	if rule.RuleType != RuleTypeComposite {
+		return true
+	}
+	if rule.Correlation != nil && len(rule.Correlation.ElevateIf) > 0 {
+		return true
+	}
+	if rule.CompositeConfig == nil {
+		return false
+	}
+	for _, check := range rule.CompositeConfig.Checks {
+		if strings.EqualFold(check.Status, string(StatusRed)) {
+			return true
+		}
+	}
+	return false
+}
+
+// keyLine finds the line a key is defined on, or 0. The TOML decoder only
+// reports positions of syntax errors, so keys are located in the source.
+func keyLine(source string, key toml.Key) int {
+	full := key.String()
+	table := ""
+	for i, line := range strings.Split(source, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "[") {
+			header := trimmed
+			if end := strings.LastIndex(header, "]"); end >= 0 {
+				header = header[:end]
+			}
+			table = strings.TrimSpace(strings.Trim(header, "[]"))
+			if table == full {
+				return i + 1
+			}
+			continue
+		}
+
+		relative := full
+		if table != "" {
+			if !strings.HasPrefix(full, table+".") {
+				continue
+			}
+			relative = strings.TrimPrefix(full, table+".")
+		}
+		if rest, ok := strings.CutPrefix(trimmed, relative); ok && strings.HasPrefix(strings.TrimSpace(rest), "=") {
+			return i + 1
+		}
+	}
+	return 0
+}
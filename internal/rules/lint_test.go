@@ -0,0 +1,149 @@
+package rules
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+)
+
+const lintGaugeRule = `rule_type = "gauge_threshold"
+metric_name = "queue_size"
+display_name = "queue_size"
+
+[thresholds]
+low = 10
+high = 100
+higher_is_worse = true
+
+[messages]
+green = "Queue size {value:.0f}"
+yellow = "Queue size {value:.0f}"
+red = "Queue size {value:.0f}"
+
+[remediation]
+red = "Scale up"
+`
+
+func TestLintRules(t *testing.T) {
+	tests := map[string]struct {
+		files           map[string]string
+		wantDiagnostics []string
+		wantRules       int
+	}{
+		"should accept a clean rule": {
+			files:     map[string]string{"queue.toml": lintGaugeRule},
+			wantRules: 1,
+		},
+		"should report unknown keys with their line": {
+			files: map[string]string{
+				"queue.toml": strings.Replace(lintGaugeRule, "[remediation]\n", "[remediation]\nacs_versions = [\"4.8+\"]\n", 1),
+			},
+			wantDiagnostics: []string{`queue.toml:16: warning: unknown key "remediation.acs_versions"`},
+			wantRules:       1,
+		},
+		"should report syntax errors with line and column": {
+			files:           map[string]string{"queue.toml": "rule_type = \"gauge_threshold\"\nmetric_name = \n"},
+			wantDiagnostics: []string{"queue.toml:2:"},
+		},
+		"should report errors in every file": {
+			files: map[string]string{
+				"a.toml": strings.Replace(lintGaugeRule, `rule_type = "gauge_threshold"`, `rule_type = "unknown"`, 1),
+				"b.toml": strings.Replace(lintGaugeRule, `metric_name = "queue_size"`, `metric_name = ""`, 1),
+				"c.toml": strings.Replace(lintGaugeRule, `metric_name = "queue_size"`, `metric_name = "other"`, 1),
+			},
+			wantDiagnostics: []string{"a.toml: error: ", "b.toml: error: metric_name is required"},
+			wantRules:       1,
+		},
+		"should warn about placeholders that are never filled": {
+			files: map[string]string{
+				"queue.toml": strings.Replace(lintGaugeRule, `yellow = "Queue size {value:.0f}"`, `yellow = "Queue size {p95:.0f}"`, 1),
+			},
+			wantDiagnostics: []string{"queue.toml:12: warning: messages.yellow: placeholder {p95:.0f} is never filled for gauge_threshold rules"},
+			wantRules:       1,
+		},
+		"should warn about duplicate metric names": {
+			files: map[string]string{
+				"a.toml": lintGaugeRule,
+				"b.toml": lintGaugeRule,
+			},
+			wantDiagnostics: []string{`b.toml:2: warning: duplicate metric_name "queue_size", already evaluated by a.toml`},
+			wantRules:       2,
+		},
+		"should warn about a missing red remediation": {
+			files: map[string]string{
+				"queue.toml": strings.Replace(lintGaugeRule, `red = "Scale up"`, `yellow = "Watch it"`, 1),
+			},
+			wantDiagnostics: []string{"queue.toml:15: warning: no remediation.red"},
+			wantRules:       1,
+		},
+		"should warn about low thresholds above high ones": {
+			files: map[string]string{
+				"queue.toml": strings.Replace(strings.Replace(lintGaugeRule, `"gauge_threshold"`, `"queue_operations"`, 1),
+					"[thresholds]\nlow = 10\nhigh = 100\nhigher_is_worse = true",
+					"[queue_config]\noperation_label = \"Operation\"\nadd_value = \"Add\"\nremove_value = \"Remove\"\n\n[thresholds]\nlow = 100\nhigh = 10", 1),
+			},
+			wantDiagnostics: []string{"queue.toml:11: warning: thresholds.low (100) is greater than thresholds.high (10)"},
+			wantRules:       1,
+		},
+		"should report test files without a rule": {
+			files: map[string]string{
+				"orphan_test.toml": "[[tests]]\nname = \"t\"\nstatus = \"GREEN\"\n",
+			},
+			wantDiagnostics: []string{"orphan_test.toml: error: no rule file orphan.toml"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			for file, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			result, err := LintRules(dir, filepath.Join(dir, "load-level"))
+			if err != nil {
+				t.Fatalf("LintRules() error = %v", err)
+			}
+			if len(result.Diagnostics) != len(tt.wantDiagnostics) {
+				t.Fatalf("LintRules() diagnostics = %v, want %d", result.Diagnostics, len(tt.wantDiagnostics))
+			}
+			for i, want := range tt.wantDiagnostics {
+				got := strings.TrimPrefix(result.Diagnostics[i].String(), dir+string(filepath.Separator))
+				if !strings.HasPrefix(got, want) {
+					t.Errorf("diagnostic %d = %q, want prefix %q", i, got, want)
+				}
+			}
+			if len(result.Rules) != tt.wantRules {
+				t.Errorf("LintRules() got %d valid rules, want %d", len(result.Rules), tt.wantRules)
+			}
+		})
+	}
+}
+
+func TestKeyLine(t *testing.T) {
+	source := "metric_name = \"a\"\n# [thresholds]\n[thresholds]\nlow = 1\n\n[[composite_config.checks]]\nmessage = \"x\"\n"
+
+	tests := map[string]struct {
+		key  toml.Key
+		want int
+	}{
+		"should find a top-level key":        {key: toml.Key{"metric_name"}, want: 1},
+		"should find a table header":         {key: toml.Key{"thresholds"}, want: 3},
+		"should find a key in a table":       {key: toml.Key{"thresholds", "low"}, want: 4},
+		"should find a key in a table array": {key: toml.Key{"composite_config", "checks", "message"}, want: 7},
+		"should return 0 for a missing key":  {key: toml.Key{"thresholds", "high"}, want: 0},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := keyLine(source, tt.key); got != tt.want {
+				t.Errorf("keyLine(%s) = %d, want %d", tt.key, got, tt.want)
+			}
+		})
+	}
+}
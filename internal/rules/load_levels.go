@@ -0,0 +1,144 @@
+package rules
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// DeclaredLevels returns the tiers of a load detection rule from lowest to
+// highest. Without an explicit levels list the tiers are ordered by the
+// score ranges of their thresholds.
+func (r LoadDetectionRule) DeclaredLevels() []LoadLevel {
+	if len(r.Levels) > 0 {
+		return r.Levels
+	}
+
+	thresholds := make([]LoadDetectionThreshold, len(r.Thresholds))
+	copy(thresholds, r.Thresholds)
+	sort.SliceStable(thresholds, func(i, j int) bool {
+		return lowerBound(thresholds[i]) < lowerBound(thresholds[j])
+	})
+
+	var levels []LoadLevel
+	seen := make(map[LoadLevel]bool)
+	for _, threshold := range thresholds {
+		if !seen[threshold.Level] {
+			seen[threshold.Level] = true
+			levels = append(levels, threshold.Level)
+		}
+	}
+	return levels
+}
+
+// lowerBound returns the smallest score a threshold matches, treating an open
+// lower bound as smaller than any score
+func lowerBound(t LoadDetectionThreshold) float64 {
+	if t.MinValue == nil {
+		return math.Inf(-1)
+	}
+	return *t.MinValue
+}
+
+// LoadTiers merges the tiers declared by the load detection rules into one
+// order from lowest to highest. Levels whose relative order no rule fixes keep
+// the order in which they were first declared. Without rules the default
+// low, medium and high tiers are returned. An error is returned when two rules
+// order the same levels differently; the returned tiers are still usable.
+func LoadTiers(loadRules []LoadDetectionRule) ([]LoadLevel, error) {
+	if len(loadRules) == 0 {
+		return DefaultLoadLevels, nil
+	}
+
+	// Every rule orders its own levels; collect "a is below b" edges
+	var declared []LoadLevel
+	seen := make(map[LoadLevel]bool)
+	below := make(map[LoadLevel][]LoadLevel)
+	incoming := make(map[LoadLevel]int)
+	for _, rule := range loadRules {
+		levels := rule.DeclaredLevels()
+		for i, level := range levels {
+			if !seen[level] {
+				seen[level] = true
+				declared = append(declared, level)
+			}
+			if i > 0 {
+				below[levels[i-1]] = append(below[levels[i-1]], level)
+				incoming[level]++
+			}
+		}
+	}
+
+	// Topological sort, picking the earliest declared level among the ready ones
+	tiers := make([]LoadLevel, 0, len(declared))
+	placed := make(map[LoadLevel]bool)
+	for len(tiers) < len(declared) {
+		next := LoadLevel("")
+		for _, level := range declared {
+			if !placed[level] && incoming[level] == 0 {
+				next = level
+				break
+			}
+		}
+		if next == "" {
+			// Conflicting orders, append the rest as declared
+			var conflicting []string
+			for _, level := range declared {
+				if !placed[level] {
+					tiers = append(tiers, level)
+					conflicting = append(conflicting, string(level))
+				}
+			}
+			return tiers, fmt.Errorf("load detection rules order the levels %s differently", strings.Join(conflicting, ", "))
+		}
+		placed[next] = true
+		tiers = append(tiers, next)
+		for _, higher := range below[next] {
+			incoming[higher]--
+		}
+	}
+	return tiers, nil
+}
+
+// DefaultLoadLevel returns the level used when no load detection rule matched:
+// medium when declared, otherwise the middle tier
+func DefaultLoadLevel(tiers []LoadLevel) LoadLevel {
+	if len(tiers) == 0 {
+		return LoadLevelMedium
+	}
+	for _, level := range tiers {
+		if level == LoadLevelMedium {
+			return level
+		}
+	}
+	return tiers[(len(tiers)-1)/2]
+}
+
+// UndeclaredLoadLevels returns the levels of a rule's load_level_thresholds
+// that are not among the tiers, in sorted order. Thresholds for such levels
+// are never used.
+func UndeclaredLoadLevels(rule Rule, tiers []LoadLevel) []LoadLevel {
+	declared := make(map[LoadLevel]bool, len(tiers))
+	for _, level := range tiers {
+		declared[level] = true
+	}
+
+	var undeclared []LoadLevel
+	for level := range rule.LoadLevelThresholds {
+		if !declared[level] {
+			undeclared = append(undeclared, level)
+		}
+	}
+	sort.Slice(undeclared, func(i, j int) bool { return undeclared[i] < undeclared[j] })
+	return undeclared
+}
+
+// FormatLoadLevels joins levels for messages, e.g. "low, medium, high"
+func FormatLoadLevels(levels []LoadLevel) string {
+	names := make([]string, len(levels))
+	for i, level := range levels {
+		names[i] = string(level)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,107 @@
+package rules
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLoadTiers(t *testing.T) {
+	bound := func(v float64) *float64 { return &v }
+	rule := func(thresholds ...LoadDetectionThreshold) LoadDetectionRule {
+		return LoadDetectionRule{Thresholds: thresholds}
+	}
+
+	tests := map[string]struct {
+		loadRules []LoadDetectionRule
+		want      []LoadLevel
+		wantError bool
+	}{
+		"should default to low, medium and high without rules": {
+			want: DefaultLoadLevels,
+		},
+		"should order tiers by their score ranges": {
+			loadRules: []LoadDetectionRule{rule(
+				LoadDetectionThreshold{Level: "xlarge", MinValue: bound(5000)},
+				LoadDetectionThreshold{Level: "tiny", MaxValue: bound(20)},
+				LoadDetectionThreshold{Level: "large", MinValue: bound(500), MaxValue: bound(5000)},
+				LoadDetectionThreshold{Level: "small", MinValue: bound(20), MaxValue: bound(500)},
+			)},
+			want: []LoadLevel{"tiny", "small", "large", "xlarge"},
+		},
+		"should prefer an explicit levels list": {
+			loadRules: []LoadDetectionRule{{
+				Levels:     []LoadLevel{"tiny", "small", "large"},
+				Thresholds: []LoadDetectionThreshold{{Level: "small"}},
+			}},
+			want: []LoadLevel{"tiny", "small", "large"},
+		},
+		"should merge the tiers of several rules": {
+			loadRules: []LoadDetectionRule{
+				{Levels: []LoadLevel{"small", "large"}},
+				{Levels: []LoadLevel{"tiny", "small", "medium", "large", "xlarge"}},
+			},
+			want: []LoadLevel{"tiny", "small", "medium", "large", "xlarge"},
+		},
+		"should reject rules ordering levels differently": {
+			loadRules: []LoadDetectionRule{
+				{Levels: []LoadLevel{"small", "large"}},
+				{Levels: []LoadLevel{"large", "small"}},
+			},
+			want:      []LoadLevel{"small", "large"},
+			wantError: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := LoadTiers(tt.loadRules)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("LoadTiers() error = %v, wantError %v", err, tt.wantError)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LoadTiers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefaultLoadLevel(t *testing.T) {
+	tests := map[string]struct {
+		tiers []LoadLevel
+		want  LoadLevel
+	}{
+		"should prefer medium when declared": {
+			tiers: []LoadLevel{"tiny", "small", "medium", "large", "xlarge", "huge"},
+			want:  LoadLevelMedium,
+		},
+		"should pick the middle tier": {
+			tiers: []LoadLevel{"tiny", "small", "large"},
+			want:  "small",
+		},
+		"should pick the lower middle tier of an even count": {
+			tiers: []LoadLevel{"tiny", "small", "large", "xlarge"},
+			want:  "small",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := DefaultLoadLevel(tt.tiers); got != tt.want {
+				t.Errorf("DefaultLoadLevel() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUndeclaredLoadLevels(t *testing.T) {
+	rule := Rule{LoadLevelThresholds: LoadLevelThresholds{
+		"xlarge":      {Low: 1, High: 2},
+		LoadLevelHigh: {Low: 1, High: 2},
+		"huge":        {Low: 1, High: 2},
+	}}
+	got := UndeclaredLoadLevels(rule, []LoadLevel{"small", LoadLevelHigh})
+	want := []LoadLevel{"huge", "xlarge"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("UndeclaredLoadLevels() = %v, want %v", got, want)
+	}
+}
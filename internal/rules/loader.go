@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/BurntSushi/toml"
 )
@@ -18,6 +19,9 @@ func LoadRules(rulesDir string) ([]Rule, error) {
 	}
 
 	for _, file := range files {
+		if IsTestFile(file) {
+			continue
+		}
 		rule, err := LoadRule(file)
 		if err != nil {
 			return nil, fmt.Errorf("failed to load rule %s: %w", file, err)
@@ -28,6 +32,15 @@ func LoadRules(rulesDir string) ([]Rule, error) {
 	return rules, nil
 }
 
+// IsTestFile reports whether a TOML file holds rule tests rather than a rule
+func IsTestFile(path string) bool {
+	return strings.HasSuffix(path, TestFileSuffix)
+}
+
+// TestFileSuffix marks the sibling file holding the tests of a rule, e.g.
+// queue_drops_test.toml for queue_drops.toml
+const TestFileSuffix = "_test.toml"
+
 // LoadRule loads a single TOML rule file
 func LoadRule(filepath string) (Rule, error) {
 	var rule Rule
@@ -59,6 +72,9 @@ func LoadLoadDetectionRules(rulesDir string) ([]LoadDetectionRule, error) {
 	}
 
 	for _, file := range files {
+		if IsTestFile(file) {
+			continue
+		}
 		var rule LoadDetectionRule
 		data, err := os.ReadFile(file)
 		if err != nil {
@@ -68,9 +84,19 @@ func LoadLoadDetectionRules(rulesDir string) ([]LoadDetectionRule, error) {
 		if err := toml.Unmarshal(data, &rule); err != nil {
 			return nil, fmt.Errorf("failed to parse TOML %s: %w", file, err)
 		}
+		if rule.DisplayName == "" {
+			rule.DisplayName = strings.TrimSuffix(filepath.Base(file), ".toml")
+		}
+		if err := ValidateLoadDetectionRule(rule); err != nil {
+			return nil, fmt.Errorf("validation failed for %s: %w", file, err)
+		}
 
 		rules = append(rules, rule)
 	}
 
+	if _, err := LoadTiers(rules); err != nil {
+		return nil, err
+	}
+
 	return rules, nil
 }
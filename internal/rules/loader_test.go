@@ -36,6 +36,18 @@ func TestLoadRule(t *testing.T) {
 				}
 			},
 		},
+		"should load valid expression rule successfully": {
+			filename:  "../../testdata/fixtures/test_expression.toml",
+			wantError: false,
+			checkFunc: func(t *testing.T, r Rule) {
+				if r.RuleType != RuleTypeExpression {
+					t.Errorf("LoadRule() ruleType = %v, want %v", r.RuleType, RuleTypeExpression)
+				}
+				if r.ExpressionConfig == nil || r.ExpressionConfig.Expression == "" {
+					t.Error("LoadRule() ExpressionConfig is empty")
+				}
+			},
+		},
 		"should return error for invalid rule file": {
 			filename:  "../../testdata/fixtures/nonexistent.toml",
 			wantError: true,
@@ -150,6 +162,205 @@ func TestValidateRule(t *testing.T) {
 			},
 			wantError: true,
 		},
+		"should accept label selector with aggregation": {
+			rule: Rule{
+				RuleType:      RuleTypeGauge,
+				MetricName:    "test_metric",
+				LabelSelector: `{component="enricher"}`,
+				Aggregation:   AggregationMax,
+				Thresholds:    Thresholds{Low: 10, High: 100},
+			},
+			wantError: false,
+		},
+		"should return error for invalid aggregation": {
+			rule: Rule{
+				RuleType:    RuleTypeGauge,
+				MetricName:  "test_metric",
+				Aggregation: Aggregation("median"),
+				Thresholds:  Thresholds{Low: 10, High: 100},
+			},
+			wantError: true,
+		},
+		"should return error for malformed label selector": {
+			rule: Rule{
+				RuleType:      RuleTypeGauge,
+				MetricName:    "test_metric",
+				LabelSelector: `{component=enricher}`,
+				Thresholds:    Thresholds{Low: 10, High: 100},
+			},
+			wantError: true,
+		},
+		"should accept histogram statistic": {
+			rule: Rule{
+				RuleType:        RuleTypeHistogram,
+				MetricName:      "test_histogram",
+				HistogramConfig: &HistogramConfig{Statistic: "p99.9"},
+				Thresholds:      Thresholds{P95Good: 0.1, P95Warn: 1},
+			},
+			wantError: false,
+		},
+		"should return error for invalid histogram statistic": {
+			rule: Rule{
+				RuleType:        RuleTypeHistogram,
+				MetricName:      "test_histogram",
+				HistogramConfig: &HistogramConfig{Statistic: "p100"},
+				Thresholds:      Thresholds{P95Good: 0.1, P95Warn: 1},
+			},
+			wantError: true,
+		},
+		"should accept summary statistic max": {
+			rule: Rule{
+				RuleType:      RuleTypeSummary,
+				MetricName:    "go_gc_duration_seconds",
+				SummaryConfig: &SummaryConfig{Statistic: "max"},
+				Thresholds:    Thresholds{P95Good: 0.1, P95Warn: 1},
+			},
+			wantError: false,
+		},
+		"should return error for invalid summary statistic": {
+			rule: Rule{
+				RuleType:      RuleTypeSummary,
+				MetricName:    "go_gc_duration_seconds",
+				SummaryConfig: &SummaryConfig{Statistic: "median"},
+				Thresholds:    Thresholds{P95Good: 0.1, P95Warn: 1},
+			},
+			wantError: true,
+		},
+		"should return error for summary p95_good not below p95_warn": {
+			rule: Rule{
+				RuleType:   RuleTypeSummary,
+				MetricName: "go_gc_duration_seconds",
+				Thresholds: Thresholds{P95Good: 1, P95Warn: 1},
+			},
+			wantError: true,
+		},
+		"should accept cardinality rule on a label": {
+			rule: Rule{
+				RuleType:          RuleTypeCardinality,
+				MetricName:        "rox_sensor_events",
+				CardinalityConfig: &CardinalityConfig{Label: "pod"},
+				Thresholds:        Thresholds{Low: 100, High: 1000},
+			},
+			wantError: false,
+		},
+		"should return error for cardinality rule on a bounded label": {
+			rule: Rule{
+				RuleType:          RuleTypeCardinality,
+				MetricName:        "rox_sensor_latency",
+				CardinalityConfig: &CardinalityConfig{Label: "le"},
+				Thresholds:        Thresholds{Low: 100, High: 1000},
+			},
+			wantError: true,
+		},
+		"should accept a glob metric_name for gauge rules": {
+			rule: Rule{
+				RuleType:   RuleTypeGauge,
+				MetricName: "rox_sensor_*_queue_size",
+				Thresholds: Thresholds{Low: 100, High: 500},
+			},
+			wantError: false,
+		},
+		"should return error for an invalid regex metric_name": {
+			rule: Rule{
+				RuleType:   RuleTypeGauge,
+				MetricName: "/rox_sensor_(queue/",
+				Thresholds: Thresholds{Low: 100, High: 500},
+			},
+			wantError: true,
+		},
+		"should return error for a metric_name pattern on percentage rules": {
+			rule: Rule{
+				RuleType:         RuleTypePercentage,
+				MetricName:       "rox_sensor_*",
+				PercentageConfig: &PercentageConfig{Numerator: "a", Denominator: "b"},
+				Thresholds:       Thresholds{Low: 10, High: 20},
+			},
+			wantError: true,
+		},
+		"should return error for allow_empty without a metric_name pattern": {
+			rule: Rule{
+				RuleType:   RuleTypeGauge,
+				MetricName: "rox_sensor_queue_size",
+				AllowEmpty: true,
+				Thresholds: Thresholds{Low: 100, High: 500},
+			},
+			wantError: true,
+		},
+		"should accept histogram_inf_overflow rule": {
+			rule: Rule{
+				RuleType:   RuleTypeHistogramInfOverflow,
+				MetricName: "*",
+				Thresholds: Thresholds{Low: 25, High: 50},
+			},
+			wantError: false,
+		},
+		"should return error for histogram_inf_overflow thresholds above 100 percent": {
+			rule: Rule{
+				RuleType:   RuleTypeHistogramInfOverflow,
+				MetricName: "*",
+				Thresholds: Thresholds{Low: 50, High: 150},
+			},
+			wantError: true,
+		},
+		"should validate expression rule correctly": {
+			rule: Rule{
+				RuleType:         RuleTypeExpression,
+				ExpressionConfig: &ExpressionConfig{Expression: "max by (component) (queue_size) / 2"},
+				Thresholds:       Thresholds{Low: 10, High: 100},
+			},
+			wantError: false,
+		},
+		"should return error for expression with syntax error": {
+			rule: Rule{
+				RuleType:         RuleTypeExpression,
+				ExpressionConfig: &ExpressionConfig{Expression: "sum(queue_size"},
+				Thresholds:       Thresholds{Low: 10, High: 100},
+			},
+			wantError: true,
+		},
+		"should return error for missing expression": {
+			rule: Rule{
+				RuleType:   RuleTypeExpression,
+				Thresholds: Thresholds{Low: 10, High: 100},
+			},
+			wantError: true,
+		},
+		"should return error for aggregation on unsupported rule type": {
+			rule: Rule{
+				RuleType:    RuleTypeQueue,
+				MetricName:  "test_metric",
+				Aggregation: AggregationSum,
+				QueueConfig: &QueueConfig{OperationLabel: "Operation"},
+			},
+			wantError: true,
+		},
+		"should accept counter average": {
+			rule: Rule{
+				RuleType:       RuleTypeGauge,
+				MetricName:     "test_total",
+				CounterAverage: CounterAveragePerHour,
+				Thresholds:     Thresholds{Low: 10, High: 100},
+			},
+			wantError: false,
+		},
+		"should return error for invalid counter average": {
+			rule: Rule{
+				RuleType:       RuleTypeGauge,
+				MetricName:     "test_total",
+				CounterAverage: CounterAverage("per_day"),
+				Thresholds:     Thresholds{Low: 10, High: 100},
+			},
+			wantError: true,
+		},
+		"should return error for counter average on ratio rules": {
+			rule: Rule{
+				RuleType:         RuleTypePercentage,
+				CounterAverage:   CounterAveragePerSecond,
+				PercentageConfig: &PercentageConfig{Numerator: "a_total", Denominator: "b_total"},
+				Thresholds:       Thresholds{Low: 10, High: 100},
+			},
+			wantError: true,
+		},
 	}
 
 	for name, tt := range tests {
@@ -171,7 +382,7 @@ func TestLoadRules(t *testing.T) {
 	}{
 		"should load all rules from directory": {
 			dir:       "../../testdata/fixtures",
-			wantCount: 7, // Test rule files (excluding load detection which fails validation)
+			wantCount: 8, // Test rule files (excluding load detection which fails validation)
 			wantError: false,
 		},
 		"should handle non-existent directory gracefully": {
@@ -208,3 +419,159 @@ func TestLoadRules(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadLoadDetectionRules(t *testing.T) {
+	loadRules, err := LoadLoadDetectionRules("../../testdata/fixtures/load-level")
+	if err != nil {
+		t.Fatalf("LoadLoadDetectionRules() error = %v", err)
+	}
+	if len(loadRules) != 1 {
+		t.Fatalf("LoadLoadDetectionRules() got %d rules, want 1", len(loadRules))
+	}
+	thresholds := loadRules[0].Thresholds
+	if thresholds[0].MinValue != nil || thresholds[0].MaxValue == nil || *thresholds[0].MaxValue != 100 {
+		t.Errorf("low threshold = %+v, want only max_value 100", thresholds[0])
+	}
+	if thresholds[2].MaxValue != nil {
+		t.Errorf("high threshold = %+v, want no max_value", thresholds[2])
+	}
+
+	dir := t.TempDir()
+	unnamed := "rule_type = \"load_detection\"\n[[metrics]]\nsource = \"pods\"\nweight = 1.0\n[[thresholds]]\nlevel = \"high\"\nmin_value = 0\n"
+	if err := os.WriteFile(filepath.Join(dir, "pods.toml"), []byte(unnamed), 0600); err != nil {
+		t.Fatal(err)
+	}
+	loadRules, err = LoadLoadDetectionRules(dir)
+	if err != nil {
+		t.Fatalf("LoadLoadDetectionRules() error = %v", err)
+	}
+	if loadRules[0].DisplayName != "pods" {
+		t.Errorf("DisplayName = %q, want the file name", loadRules[0].DisplayName)
+	}
+	if loadRules[0].Thresholds[0].MinValue == nil {
+		t.Error("min_value = 0 should be an explicit bound")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "broken.toml"), []byte("rule_type = \"load_detection\"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadLoadDetectionRules(dir); err == nil {
+		t.Error("LoadLoadDetectionRules() expected error for a rule without metrics")
+	}
+}
+
+func TestValidateLoadDetectionRule(t *testing.T) {
+	bound := func(v float64) *float64 { return &v }
+	valid := func() LoadDetectionRule {
+		return LoadDetectionRule{
+			RuleType: RuleTypeLoadDetection,
+			Metrics:  []LoadDetectionMetric{{Source: "pods", Weight: 1}},
+			Thresholds: []LoadDetectionThreshold{
+				{Level: LoadLevelLow, MaxValue: bound(100)},
+				{Level: LoadLevelHigh, MinValue: bound(100)},
+			},
+		}
+	}
+
+	tests := map[string]struct {
+		modify    func(*LoadDetectionRule)
+		wantError bool
+	}{
+		"should accept a valid rule": {
+			modify: func(r *LoadDetectionRule) {},
+		},
+		"should reject a wrong rule type": {
+			modify:    func(r *LoadDetectionRule) { r.RuleType = RuleTypeGauge },
+			wantError: true,
+		},
+		"should reject a negative rule weight": {
+			modify:    func(r *LoadDetectionRule) { r.Weight = -1 },
+			wantError: true,
+		},
+		"should reject a metric without weight": {
+			modify:    func(r *LoadDetectionRule) { r.Metrics[0].Weight = 0 },
+			wantError: true,
+		},
+		"should accept a custom level": {
+			modify: func(r *LoadDetectionRule) { r.Thresholds[0].Level = "tiny" },
+		},
+		"should reject a malformed level": {
+			modify:    func(r *LoadDetectionRule) { r.Thresholds[0].Level = "Extra Large" },
+			wantError: true,
+		},
+		"should accept levels listing every threshold level": {
+			modify: func(r *LoadDetectionRule) { r.Levels = []LoadLevel{LoadLevelLow, LoadLevelMedium, LoadLevelHigh} },
+		},
+		"should reject levels missing a threshold level": {
+			modify:    func(r *LoadDetectionRule) { r.Levels = []LoadLevel{LoadLevelLow} },
+			wantError: true,
+		},
+		"should reject duplicate levels": {
+			modify:    func(r *LoadDetectionRule) { r.Levels = []LoadLevel{LoadLevelLow, LoadLevelHigh, LoadLevelLow} },
+			wantError: true,
+		},
+		"should reject an empty range": {
+			modify:    func(r *LoadDetectionRule) { r.Thresholds[0].MinValue = bound(100) },
+			wantError: true,
+		},
+		"should reject a rule without thresholds": {
+			modify:    func(r *LoadDetectionRule) { r.Thresholds = nil },
+			wantError: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			rule := valid()
+			tt.modify(&rule)
+			err := ValidateLoadDetectionRule(rule)
+			if (err != nil) != tt.wantError {
+				t.Errorf("ValidateLoadDetectionRule() error = %v, wantError %v", err, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestValidateRuleTest(t *testing.T) {
+	value := 1.0
+	tests := map[string]struct {
+		test      RuleTest
+		wantError bool
+	}{
+		"should accept a status expectation": {
+			test: RuleTest{Name: "t", Status: "yellow"},
+		},
+		"should accept a skipped test": {
+			test: RuleTest{Name: "t", ACSVersion: "4.7.1", Skipped: true},
+		},
+		"should reject a test without a name": {
+			test:      RuleTest{Status: StatusGreen},
+			wantError: true,
+		},
+		"should reject a test without expectations": {
+			test:      RuleTest{Name: "t"},
+			wantError: true,
+		},
+		"should reject an unknown status": {
+			test:      RuleTest{Name: "t", Status: "ORANGE"},
+			wantError: true,
+		},
+		"should reject a version range": {
+			test:      RuleTest{Name: "t", ACSVersion: "4.7+", Status: StatusGreen},
+			wantError: true,
+		},
+		"should reject expectations on a skipped test": {
+			test:      RuleTest{Name: "t", Skipped: true, Value: &value},
+			wantError: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateRuleTest(tt.test)
+			if (err != nil) != tt.wantError {
+				t.Errorf("ValidateRuleTest() error = %v, wantError %v", err, tt.wantError)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package rules
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// patternRuleTypes are the rule types whose metric_name may be a pattern
+var patternRuleTypes = []RuleType{
+	RuleTypeGauge, RuleTypeQueue, RuleTypeHistogram, RuleTypeSummary,
+	RuleTypeCardinality, RuleTypeHistogramInfOverflow,
+}
+
+// IsMetricPattern reports whether a metric_name selects metrics by pattern rather
+// than naming one: a glob containing * or ?, or a regular expression between
+// slashes. Neither character can appear in a metric name.
+func IsMetricPattern(metricName string) bool {
+	return strings.ContainsAny(metricName, "*?") || isRegexPattern(metricName)
+}
+
+func isRegexPattern(metricName string) bool {
+	return len(metricName) >= 2 && strings.HasPrefix(metricName, "/") && strings.HasSuffix(metricName, "/")
+}
+
+// CompileMetricPattern compiles a metric_name pattern into a regular expression
+// matching whole metric names, anchored like PromQL's =~ matcher. In globs, *
+// matches any sequence of characters and ? a single character.
+func CompileMetricPattern(pattern string) (*regexp.Regexp, error) {
+	if isRegexPattern(pattern) {
+		re, err := regexp.Compile("^(?:" + pattern[1:len(pattern)-1] + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid metric_name pattern %s: %w", pattern, err)
+		}
+		return re, nil
+	}
+
+	var expr strings.Builder
+	expr.WriteString("^")
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			expr.WriteString(".*")
+		case '?':
+			expr.WriteString(".")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	expr.WriteString("$")
+	return regexp.Compile(expr.String())
+}
+
+// MatchMetricNames returns the candidates a compiled pattern matches, sorted
+func MatchMetricNames(pattern *regexp.Regexp, candidates []string) []string {
+	var matches []string
+	for _, name := range candidates {
+		if pattern.MatchString(name) {
+			matches = append(matches, name)
+		}
+	}
+	sort.Strings(matches)
+	return matches
+}
+
+func validateMetricPattern(rule Rule) error {
+	supported := false
+	for _, ruleType := range patternRuleTypes {
+		if rule.RuleType == ruleType {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		return fmt.Errorf("metric_name patterns are not supported for %s rules", rule.RuleType)
+	}
+	_, err := CompileMetricPattern(rule.MetricName)
+	return err
+}
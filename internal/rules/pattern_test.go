@@ -0,0 +1,69 @@
+package rules
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMetricPattern(t *testing.T) {
+	candidates := []string{
+		"rox_sensor_resolver_queue_size",
+		"rox_sensor_deployment_enhancement_queue_size",
+		"rox_sensor_queue_size",
+		"rox_sensor_output_channel_size",
+		"go_goroutines",
+	}
+
+	tests := map[string]struct {
+		metricName  string
+		wantPattern bool
+		want        []string
+		wantError   bool
+	}{
+		"should not treat a metric name as a pattern": {
+			metricName: "rox_sensor_queue_size",
+		},
+		"should match a glob over whole names": {
+			metricName:  "rox_sensor_*_queue_size",
+			wantPattern: true,
+			want:        []string{"rox_sensor_deployment_enhancement_queue_size", "rox_sensor_resolver_queue_size"},
+		},
+		"should match single characters with ?": {
+			metricName:  "go_gorouti?es",
+			wantPattern: true,
+			want:        []string{"go_goroutines"},
+		},
+		"should anchor regular expressions": {
+			metricName:  "/rox_sensor_(output|resolver)_.*/",
+			wantPattern: true,
+			want:        []string{"rox_sensor_output_channel_size", "rox_sensor_resolver_queue_size"},
+		},
+		"should reject invalid regular expressions": {
+			metricName:  "/rox_sensor_(/",
+			wantPattern: true,
+			wantError:   true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := IsMetricPattern(tt.metricName); got != tt.wantPattern {
+				t.Fatalf("IsMetricPattern(%q) = %v, want %v", tt.metricName, got, tt.wantPattern)
+			}
+			if !tt.wantPattern {
+				return
+			}
+			pattern, err := CompileMetricPattern(tt.metricName)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("CompileMetricPattern(%q) error = %v, wantError %v", tt.metricName, err, tt.wantError)
+			}
+			if err != nil {
+				return
+			}
+			got := MatchMetricNames(pattern, candidates)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("MatchMetricNames() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
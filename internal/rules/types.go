@@ -6,25 +6,70 @@ import "time"
 type RuleType string
 
 const (
-	RuleTypeGauge         RuleType = "gauge_threshold"
-	RuleTypePercentage    RuleType = "percentage"
-	RuleTypeQueue         RuleType = "queue_operations"
-	RuleTypeHistogram     RuleType = "histogram"
-	RuleTypeCacheHit      RuleType = "cache_hit_rate"
-	RuleTypeComposite     RuleType = "composite"
-	RuleTypeLoadDetection RuleType = "load_detection"
+	RuleTypeGauge                RuleType = "gauge_threshold"
+	RuleTypePercentage           RuleType = "percentage"
+	RuleTypeQueue                RuleType = "queue_operations"
+	RuleTypeHistogram            RuleType = "histogram"
+	RuleTypeHistogramInfOverflow RuleType = "histogram_inf_overflow"
+	RuleTypeSummary              RuleType = "summary"
+	RuleTypeCardinality          RuleType = "cardinality"
+	RuleTypeCacheHit             RuleType = "cache_hit_rate"
+	RuleTypeComposite            RuleType = "composite"
+	RuleTypeLoadDetection        RuleType = "load_detection"
+	RuleTypeExpression           RuleType = "expression"
 )
 
 // Status represents the health status of a metric
 type Status string
 
 const (
-	StatusGreen  Status = "GREEN"
-	StatusYellow Status = "YELLOW"
-	StatusRed    Status = "RED"
+	StatusGreen   Status = "GREEN"
+	StatusYellow  Status = "YELLOW"
+	StatusRed     Status = "RED"
+	StatusUnknown Status = "UNKNOWN" // The rule could not be evaluated, see EvaluationResult.Reason
 )
 
-// LoadLevel represents the detected cluster load level
+// UnknownReason explains why a rule evaluated to StatusUnknown
+type UnknownReason string
+
+const (
+	ReasonMetricNotFound  UnknownReason = "metric_not_found" // A metric the rule reads is absent
+	ReasonSeriesNotFound  UnknownReason = "series_not_found" // The metric has no series matching the label selector
+	ReasonInvalidConfig   UnknownReason = "invalid_config"   // The rule is missing configuration or has an invalid one
+	ReasonEvaluationError UnknownReason = "evaluation_error" // Evaluating the rule failed or produced no number
+	ReasonNoData          UnknownReason = "no_data"          // The rule's query matched no samples
+	ReasonUptimeUnknown   UnknownReason = "uptime_unknown"   // The rule averages counters but the Sensor uptime is unknown
+)
+
+// Aggregation selects how a rule combines the series of a labeled metric
+type Aggregation string
+
+const (
+	AggregationSum       Aggregation = "sum"
+	AggregationMax       Aggregation = "max"
+	AggregationMin       Aggregation = "min"
+	AggregationAvg       Aggregation = "avg"
+	AggregationPerSeries Aggregation = "per_series" // One result per label set
+)
+
+// CounterAverage selects the unit a rule averages counters over time in
+type CounterAverage string
+
+const (
+	CounterAveragePerSecond CounterAverage = "per_second"
+	CounterAveragePerHour   CounterAverage = "per_hour"
+)
+
+// CounterWindow is the time the counters of the evaluated metrics accumulated
+// over, which rules with counter_average divide them by
+type CounterWindow struct {
+	Uptime       time.Duration // Sensor uptime at the scrape; zero if unknown
+	RateInterval time.Duration // Set when counters already are per-second rates over this interval
+}
+
+// LoadLevel represents the detected cluster load level. The levels are the
+// tiers declared by the load detection rules; low, medium and high are the
+// tiers used when no load detection rules are configured.
 type LoadLevel string
 
 const (
@@ -33,6 +78,9 @@ const (
 	LoadLevelHigh   LoadLevel = "high"
 )
 
+// DefaultLoadLevels are the tiers, from lowest to highest, used without load detection rules
+var DefaultLoadLevels = []LoadLevel{LoadLevelLow, LoadLevelMedium, LoadLevelHigh}
+
 // Thresholds contains threshold values for evaluation
 type Thresholds struct {
 	Low           float64 `toml:"low"`
@@ -43,12 +91,9 @@ type Thresholds struct {
 	MinRatio      float64 `toml:"min_ratio"`
 }
 
-// LoadLevelThresholds contains thresholds for each load level
-type LoadLevelThresholds struct {
-	Low    *Thresholds `toml:"low"`
-	Medium *Thresholds `toml:"medium"`
-	High   *Thresholds `toml:"high"`
-}
+// LoadLevelThresholds contains thresholds for each named load level,
+// e.g. [load_level_thresholds.xlarge]
+type LoadLevelThresholds map[LoadLevel]*Thresholds
 
 // Messages contains status message templates
 type Messages struct {
@@ -85,7 +130,7 @@ type CorrelationCondition struct {
 // Rule represents a loaded TOML rule
 type Rule struct {
 	RuleType    RuleType `toml:"rule_type"`
-	MetricName  string   `toml:"metric_name"`
+	MetricName  string   `toml:"metric_name"` // Or a glob/regex pattern, evaluated once per matching metric
 	DisplayName string   `toml:"display_name"`
 	Description string   `toml:"description"`
 
@@ -94,20 +139,36 @@ type Rule struct {
 	LastReviewBy string `toml:"last_review_by"`
 	LastReviewOn string `toml:"last_review_on"`
 
+	// Series selection for gauge, percentage and cache rules (optional).
+	// Without an aggregation the first matching series is used.
+	LabelSelector string      `toml:"label_selector"` // e.g., {component="enricher"}
+	Aggregation   Aggregation `toml:"aggregation"`    // sum, max, min, avg or per_series
+
+	// Grade counters as averages over the Sensor uptime instead of totals since
+	// start, for gauge, queue, composite and expression rules (optional)
+	CounterAverage CounterAverage `toml:"counter_average"` // per_second or per_hour
+
+	// Report nothing instead of UNKNOWN when the metric_name pattern matches no
+	// metric, for checks that only apply to the metrics a scrape happens to have
+	AllowEmpty bool `toml:"allow_empty"`
+
 	// Type-specific configurations
-	GaugeConfig      *GaugeConfig      `toml:"gauge_config"`
-	PercentageConfig *PercentageConfig `toml:"percentage_config"`
-	QueueConfig      *QueueConfig      `toml:"queue_config"`
-	HistogramConfig  *HistogramConfig  `toml:"histogram_config"`
-	CacheConfig      *CacheConfig      `toml:"cache_config"`
-	CompositeConfig  *CompositeConfig  `toml:"composite_config"`
+	GaugeConfig       *GaugeConfig       `toml:"gauge_config"`
+	PercentageConfig  *PercentageConfig  `toml:"percentage_config"`
+	QueueConfig       *QueueConfig       `toml:"queue_config"`
+	HistogramConfig   *HistogramConfig   `toml:"histogram_config"`
+	SummaryConfig     *SummaryConfig     `toml:"summary_config"`
+	CardinalityConfig *CardinalityConfig `toml:"cardinality_config"`
+	CacheConfig       *CacheConfig       `toml:"cache_config"`
+	CompositeConfig   *CompositeConfig   `toml:"composite_config"`
+	ExpressionConfig  *ExpressionConfig  `toml:"expression_config"`
 
 	Thresholds  Thresholds   `toml:"thresholds"`
 	Messages    Messages     `toml:"messages"`
 	Remediation *Remediation `toml:"remediation"` // Optional remediation actions
 
 	// Load-aware thresholds (optional, falls back to thresholds if not set)
-	LoadLevelThresholds *LoadLevelThresholds `toml:"load_level_thresholds"`
+	LoadLevelThresholds LoadLevelThresholds `toml:"load_level_thresholds"`
 
 	// Correlation: reference other metrics for conditional evaluation
 	Correlation *CorrelationConfig `toml:"correlation"`
@@ -120,6 +181,27 @@ type Rule struct {
 
 	// Maximum ACS version (inclusive)
 	MaxACSVersion string `toml:"max_acs_version"`
+
+	// Unit tests run by the test-rules command (optional)
+	Tests []RuleTest `toml:"tests"`
+}
+
+// RuleTest evaluates a rule against inline metrics and checks the outcome.
+// Tests can also be declared in a sibling <rule>_test.toml file.
+type RuleTest struct {
+	Name       string    `toml:"name"`
+	Metrics    string    `toml:"metrics"`     // Prometheus text exposition fixture
+	LoadLevel  LoadLevel `toml:"load_level"`  // Defaults to the level used when no load detection rule matches
+	ACSVersion string    `toml:"acs_version"` // Defaults to the version detected from the metrics
+
+	// Expectations; unset fields are not checked
+	Skipped         bool     `toml:"skipped"` // The rule must not apply to acs_version
+	Result          string   `toml:"result"`  // Result to check when the rule produces several, by rule name
+	Status          Status   `toml:"status"`
+	Value           *float64 `toml:"value"`
+	Tolerance       float64  `toml:"tolerance"` // Allowed difference from value; defaults to 1e-9
+	Message         string   `toml:"message"`
+	MessageContains string   `toml:"message_contains"`
 }
 
 // GaugeConfig for simple threshold-based gauge metrics
@@ -143,6 +225,31 @@ type QueueConfig struct {
 // HistogramConfig for latency/duration histograms
 type HistogramConfig struct {
 	Unit string `toml:"unit"`
+	// Statistic graded against p95_good/p95_warn: a quantile such as "p99" or
+	// "p99.9", or "mean" (from _sum/_count). Defaults to "p95".
+	Statistic string `toml:"statistic"`
+}
+
+// HistogramStatistic is the parsed form of HistogramConfig.Statistic
+type HistogramStatistic struct {
+	Name     string  // As configured, e.g. "p99" or "mean"
+	Quantile float64 // Between 0 and 1; unused for the mean
+	Mean     bool
+}
+
+// SummaryConfig for summaries, which expose precomputed quantiles
+type SummaryConfig struct {
+	Unit string `toml:"unit"`
+	// Statistic graded against p95_good/p95_warn: a quantile the summary exposes,
+	// such as "p99", "min" or "max" for quantiles 0 and 1, or "mean" (from
+	// _sum/_count). Defaults to "p95".
+	Statistic string `toml:"statistic"`
+}
+
+// CardinalityConfig for series cardinality rules
+type CardinalityConfig struct {
+	// Label whose distinct values are graded instead of the number of series (optional)
+	Label string `toml:"label"`
 }
 
 // CacheConfig for cache hit rate calculation
@@ -157,6 +264,11 @@ type CompositeConfig struct {
 	Checks  []CompositeCheck  `toml:"checks"`
 }
 
+// ExpressionConfig for rules graded on a PromQL-like expression
+type ExpressionConfig struct {
+	Expression string `toml:"expression"` // e.g., sum by (component) (rox_sensor_queue_size)
+}
+
 type CompositeMetric struct {
 	Name   string `toml:"name"`
 	Source string `toml:"source"`
@@ -172,12 +284,31 @@ type CompositeCheck struct {
 	Message     string   `toml:"message"`
 }
 
+// LoadStrategy selects how the levels of several load detection rules are combined
+type LoadStrategy string
+
+const (
+	LoadStrategyMax          LoadStrategy = "max"           // Highest level detected by any rule
+	LoadStrategyWeightedVote LoadStrategy = "weighted_vote" // Level with the largest total rule weight
+)
+
 // LoadDetectionRule represents a load detection rule
 type LoadDetectionRule struct {
-	RuleType    RuleType                 `toml:"rule_type"`
-	DisplayName string                   `toml:"display_name"`
-	Metrics     []LoadDetectionMetric    `toml:"metrics"`
-	Thresholds  []LoadDetectionThreshold `toml:"thresholds"`
+	RuleType    RuleType `toml:"rule_type"`
+	DisplayName string   `toml:"display_name"` // Defaults to the file name
+	Weight      float64  `toml:"weight"`       // Vote weight for weighted_vote; defaults to 1
+
+	// Review metadata (optional)
+	Reviewed     string `toml:"reviewed"`
+	LastReviewBy string `toml:"last_review_by"`
+	LastReviewOn string `toml:"last_review_on"`
+
+	Metrics    []LoadDetectionMetric    `toml:"metrics"`
+	Thresholds []LoadDetectionThreshold `toml:"thresholds"`
+
+	// Levels orders the tiers this rule declares from lowest to highest.
+	// Defaults to the order of the threshold ranges.
+	Levels []LoadLevel `toml:"levels"`
 }
 
 type LoadDetectionMetric struct {
@@ -186,16 +317,58 @@ type LoadDetectionMetric struct {
 	Weight float64 `toml:"weight"`
 }
 
+// LoadDetectionThreshold matches scores in [MinValue, MaxValue); a nil bound is open
 type LoadDetectionThreshold struct {
 	Level    LoadLevel `toml:"level"`
-	MinValue float64   `toml:"min_value"`
-	MaxValue float64   `toml:"max_value"`
+	MinValue *float64  `toml:"min_value"`
+	MaxValue *float64  `toml:"max_value"`
+}
+
+// Matches reports whether the score falls within the threshold
+func (t LoadDetectionThreshold) Matches(score float64) bool {
+	if t.MinValue != nil && score < *t.MinValue {
+		return false
+	}
+	if t.MaxValue != nil && score >= *t.MaxValue {
+		return false
+	}
+	return true
+}
+
+// LoadDetection explains how the load level of a report was determined
+type LoadDetection struct {
+	Strategy   LoadStrategy
+	Levels     []LoadLevel       // Declared tiers from lowest to highest
+	Detected   LoadLevel         // Level the rules produced, even when overridden
+	Overridden bool              // The level was set by the user instead of detected
+	Rules      []LoadRuleVerdict // In rule load order
+}
+
+// LoadRuleVerdict is the outcome of a single load detection rule
+type LoadRuleVerdict struct {
+	RuleName  string
+	Weight    float64
+	Score     float64                 // Weighted average of the rule's metrics
+	Level     LoadLevel               // Empty when no metric was found or no threshold matched
+	Threshold *LoadDetectionThreshold // Threshold that matched the score
+	Metrics   []LoadMetricInput       // In rule order
+}
+
+// LoadMetricInput is one metric's part in a load detection score
+type LoadMetricInput struct {
+	Name         string
+	Source       string
+	Found        bool
+	Value        float64 // Sum of all series of Source
+	Weight       float64
+	Contribution float64 // Value × Weight / total weight of the found metrics; the contributions add up to the score
 }
 
 // EvaluationResult represents the result of evaluating a rule
 type EvaluationResult struct {
 	RuleName                 string
 	Status                   Status
+	Reason                   UnknownReason // Set only for StatusUnknown
 	MetricHelp               string
 	Message                  string
 	Value                    float64
@@ -209,12 +382,16 @@ type EvaluationResult struct {
 
 // AnalysisReport contains all evaluation results
 type AnalysisReport struct {
-	ClusterName string
-	ACSVersion  string    // Detected or user-specified
-	LoadLevel   LoadLevel // Detected or user-specified
-	Timestamp   time.Time
-	Results     []EvaluationResult
-	Summary     Summary
+	ClusterName   string
+	ACSVersion    string        // Detected or user-specified
+	LoadLevel     LoadLevel     // Detected or user-specified
+	LoadDetection LoadDetection // How LoadLevel was determined
+	Timestamp     time.Time
+	RateInterval  time.Duration // Time between baseline and current scrape; zero for a single scrape
+	Uptime        time.Duration // Sensor uptime at the (current) scrape; zero if unknown
+	Warnings      []string      // Caveats about the analysis as a whole
+	Results       []EvaluationResult
+	Summary       Summary
 }
 
 // Summary contains aggregate statistics
@@ -223,4 +400,98 @@ type Summary struct {
 	RedCount      int
 	YellowCount   int
 	GreenCount    int
+	UnknownCount  int
+}
+
+// BundleReport combines the analyses of all metrics files found in a diagnostic bundle
+type BundleReport struct {
+	Source    string // Directory or archive the metrics files were read from
+	Timestamp time.Time
+	Clusters  []ClusterReport // In discovery order
+	Summary   Summary         // Totals over all successfully analyzed clusters
+}
+
+// ClusterReport is the analysis of one metrics file of a bundle
+type ClusterReport struct {
+	ClusterName string
+	File        string // Path of the metrics file within the bundle
+	Report      AnalysisReport
+	Error       string // Set when the file could not be analyzed
+}
+
+// ChangeKind classifies how a rule's result changed between two analyses
+type ChangeKind string
+
+const (
+	ChangeRegressed ChangeKind = "regressed" // Status got more severe
+	ChangeImproved  ChangeKind = "improved"  // Status got less severe
+	ChangeAdded     ChangeKind = "added"     // Result only present in the second analysis
+	ChangeRemoved   ChangeKind = "removed"   // Result only present in the first analysis
+	ChangeUnchanged ChangeKind = "unchanged" // Same status; the value may still differ
+)
+
+// ResultDiff compares one result across two analyses
+type ResultDiff struct {
+	RuleName string
+	Change   ChangeKind
+	Before   *EvaluationResult // nil for added results
+	After    *EvaluationResult // nil for removed results
+}
+
+// Delta returns the value change, or zero unless the result is in both analyses
+func (d ResultDiff) Delta() float64 {
+	if d.Before == nil || d.After == nil {
+		return 0
+	}
+	return d.After.Value - d.Before.Value
+}
+
+// ReportDiff compares two analyses, typically before and after a remediation
+type ReportDiff struct {
+	Before  AnalysisReport
+	After   AnalysisReport
+	Results []ResultDiff // Sorted by change kind (regressions first), then rule name
+	Summary DiffSummary
+}
+
+// DiffSummary counts results by change kind
+type DiffSummary struct {
+	Regressed int
+	Improved  int
+	Added     int
+	Removed   int
+	Unchanged int
+}
+
+// CoverageReport compares the metrics rules reference with the metrics of a scrape
+type CoverageReport struct {
+	Source     string            // Metrics file the rules were checked against
+	ACSVersion string            // Detected or user-specified; rules not applicable to it are skipped
+	Rules      []RuleCoverage    // Rules with at least one absent metric, sorted by rule name
+	Uncovered  []UncoveredMetric // Sensor metrics no rule references, ranked by type
+	Summary    CoverageSummary
+}
+
+// RuleCoverage lists the metrics of a rule that are absent from a scrape
+type RuleCoverage struct {
+	RuleName string
+	Missing  []string // In rule order
+	Matched  int      // Referenced metrics present in the scrape
+}
+
+// UncoveredMetric is a metric family of a scrape that no rule references
+type UncoveredMetric struct {
+	Name   string
+	Type   string // counter, gauge, histogram, summary or untyped
+	Help   string
+	Series int
+}
+
+// CoverageSummary counts rules and metrics by coverage
+type CoverageSummary struct {
+	Rules            int // Rules checked, load detection rules included
+	SkippedRules     int // Rules not applicable to the ACS version
+	RulesWithMissing int
+	SensorMetrics    int // rox_sensor_* metric families in the scrape
+	UncoveredMetrics int
 }
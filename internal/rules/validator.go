@@ -3,7 +3,12 @@ package rules
 import (
 	"fmt"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+
+	"github.com/stackrox/sensor-metrics-analyzer/internal/expr"
+	"github.com/stackrox/sensor-metrics-analyzer/internal/parser"
 )
 
 // ValidateRule validates a rule's structure and values
@@ -16,7 +21,9 @@ func ValidateRule(rule Rule) error {
 	// Validate rule type
 	validTypes := []RuleType{
 		RuleTypeGauge, RuleTypePercentage, RuleTypeQueue,
-		RuleTypeHistogram, RuleTypeCacheHit, RuleTypeComposite,
+		RuleTypeHistogram, RuleTypeSummary, RuleTypeCacheHit,
+		RuleTypeComposite, RuleTypeExpression, RuleTypeCardinality,
+		RuleTypeHistogramInfOverflow,
 	}
 	isValid := false
 	for _, vt := range validTypes {
@@ -51,7 +58,7 @@ func ValidateRule(rule Rule) error {
 	}
 
 	// Validate load level thresholds if specified
-	if rule.LoadLevelThresholds != nil {
+	if len(rule.LoadLevelThresholds) > 0 {
 		if err := validateLoadLevelThresholds(rule.LoadLevelThresholds); err != nil {
 			return fmt.Errorf("invalid load_level_thresholds: %w", err)
 		}
@@ -64,6 +71,32 @@ func ValidateRule(rule Rule) error {
 		}
 	}
 
+	if IsMetricPattern(rule.MetricName) {
+		if err := validateMetricPattern(rule); err != nil {
+			return err
+		}
+	} else if rule.AllowEmpty {
+		return fmt.Errorf("allow_empty requires a metric_name pattern")
+	}
+
+	if rule.LabelSelector != "" || rule.Aggregation != "" {
+		if err := validateSeriesSelection(rule); err != nil {
+			return err
+		}
+	}
+
+	if rule.CounterAverage != "" {
+		if err := validateCounterAverage(rule); err != nil {
+			return err
+		}
+	}
+
+	for i, test := range rule.Tests {
+		if err := ValidateRuleTest(test); err != nil {
+			return fmt.Errorf("tests[%d]: %w", i, err)
+		}
+	}
+
 	// Type-specific validation
 	switch rule.RuleType {
 	case RuleTypeGauge:
@@ -74,10 +107,18 @@ func ValidateRule(rule Rule) error {
 		return validateQueueRule(rule)
 	case RuleTypeHistogram:
 		return validateHistogramRule(rule)
+	case RuleTypeSummary:
+		return validateSummaryRule(rule)
 	case RuleTypeCacheHit:
 		return validateCacheRule(rule)
 	case RuleTypeComposite:
 		return validateCompositeRule(rule)
+	case RuleTypeExpression:
+		return validateExpressionRule(rule)
+	case RuleTypeCardinality:
+		return validateCardinalityRule(rule)
+	case RuleTypeHistogramInfOverflow:
+		return validateInfOverflowRule(rule)
 	}
 
 	return nil
@@ -130,9 +171,64 @@ func validateHistogramRule(rule Rule) error {
 	if rule.Thresholds.P95Good >= rule.Thresholds.P95Warn {
 		return fmt.Errorf("p95_good must be less than p95_warn")
 	}
+	if rule.HistogramConfig != nil {
+		if _, err := ParseHistogramStatistic(rule.HistogramConfig.Statistic); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// ParseHistogramStatistic parses a histogram_config statistic such as "p99", "p99.9" or "mean".
+// An empty value selects p95.
+func ParseHistogramStatistic(value string) (HistogramStatistic, error) {
+	name := strings.ToLower(strings.TrimSpace(value))
+	switch name {
+	case "":
+		return HistogramStatistic{Name: "p95", Quantile: 0.95}, nil
+	case "mean":
+		return HistogramStatistic{Name: name, Mean: true}, nil
+	}
+	if strings.HasPrefix(name, "p") {
+		if percentile, err := strconv.ParseFloat(name[1:], 64); err == nil && percentile > 0 && percentile < 100 {
+			return HistogramStatistic{Name: name, Quantile: percentile / 100}, nil
+		}
+	}
+	return HistogramStatistic{}, fmt.Errorf("invalid histogram statistic: %s (expected a quantile like p95 or p99.9, or mean)", value)
+}
+
+func validateSummaryRule(rule Rule) error {
+	if rule.MetricName == "" {
+		return fmt.Errorf("metric_name is required for summary rules")
+	}
+	if rule.Thresholds.P95Good >= rule.Thresholds.P95Warn {
+		return fmt.Errorf("p95_good must be less than p95_warn")
+	}
+	if rule.SummaryConfig != nil {
+		if _, err := ParseSummaryStatistic(rule.SummaryConfig.Statistic); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParseSummaryStatistic parses a summary_config statistic: anything
+// ParseHistogramStatistic accepts, "min" for quantile 0 or "max" for quantile 1.
+func ParseSummaryStatistic(value string) (HistogramStatistic, error) {
+	name := strings.ToLower(strings.TrimSpace(value))
+	switch name {
+	case "min":
+		return HistogramStatistic{Name: name, Quantile: 0}, nil
+	case "max":
+		return HistogramStatistic{Name: name, Quantile: 1}, nil
+	}
+	statistic, err := ParseHistogramStatistic(value)
+	if err != nil {
+		return HistogramStatistic{}, fmt.Errorf("invalid summary statistic: %s (expected a quantile like p95 or p99.9, min, max, or mean)", value)
+	}
+	return statistic, nil
+}
+
 func validateCacheRule(rule Rule) error {
 	if rule.CacheConfig == nil {
 		return fmt.Errorf("cache_config is required")
@@ -153,25 +249,209 @@ func validateCompositeRule(rule Rule) error {
 	return nil
 }
 
-func validateLoadLevelThresholds(thresholds *LoadLevelThresholds) error {
-	if thresholds.Low != nil {
-		if thresholds.Low.Low >= thresholds.Low.High {
-			return fmt.Errorf("low load level: low threshold must be less than high threshold")
+func validateExpressionRule(rule Rule) error {
+	if rule.ExpressionConfig == nil || strings.TrimSpace(rule.ExpressionConfig.Expression) == "" {
+		return fmt.Errorf("expression_config.expression is required")
+	}
+	if _, err := expr.Parse(rule.ExpressionConfig.Expression); err != nil {
+		return fmt.Errorf("invalid expression: %w", err)
+	}
+	// Allow low == high == 0 for zero-check rules, as for gauges
+	if rule.Thresholds.Low == 0 && rule.Thresholds.High == 0 {
+		return nil
+	}
+	if rule.Thresholds.Low >= rule.Thresholds.High {
+		return fmt.Errorf("low threshold must be less than high threshold")
+	}
+	return nil
+}
+
+func validateCardinalityRule(rule Rule) error {
+	if rule.MetricName == "" {
+		return fmt.Errorf("metric_name is required for cardinality rules")
+	}
+	if rule.CardinalityConfig != nil {
+		switch rule.CardinalityConfig.Label {
+		case "le", "quantile":
+			return fmt.Errorf("cardinality_config.label %s is bounded by the metric definition and cannot be graded", rule.CardinalityConfig.Label)
+		}
+	}
+	if rule.Thresholds.Low >= rule.Thresholds.High {
+		return fmt.Errorf("low threshold must be less than high threshold")
+	}
+	return nil
+}
+
+func validateInfOverflowRule(rule Rule) error {
+	if rule.MetricName == "" {
+		return fmt.Errorf("metric_name is required for histogram_inf_overflow rules")
+	}
+	if rule.Thresholds.Low < 0 || rule.Thresholds.High > 100 {
+		return fmt.Errorf("histogram_inf_overflow thresholds are percentages between 0 and 100")
+	}
+	if rule.Thresholds.Low >= rule.Thresholds.High {
+		return fmt.Errorf("low threshold must be less than high threshold")
+	}
+	return nil
+}
+
+func validateSeriesSelection(rule Rule) error {
+	switch rule.RuleType {
+	case RuleTypeGauge, RuleTypePercentage, RuleTypeCacheHit:
+	default:
+		return fmt.Errorf("label_selector and aggregation are not supported for %s rules", rule.RuleType)
+	}
+	if _, err := parser.ParseLabelSelector(rule.LabelSelector); err != nil {
+		return err
+	}
+	switch rule.Aggregation {
+	case "", AggregationSum, AggregationMax, AggregationMin, AggregationAvg, AggregationPerSeries:
+		return nil
+	}
+	return fmt.Errorf("invalid aggregation: %s (must be one of: sum, max, min, avg, per_series)", rule.Aggregation)
+}
+
+func validateCounterAverage(rule Rule) error {
+	switch rule.RuleType {
+	case RuleTypeGauge, RuleTypeQueue, RuleTypeComposite, RuleTypeExpression:
+	default:
+		return fmt.Errorf("counter_average is not supported for %s rules", rule.RuleType)
+	}
+	switch rule.CounterAverage {
+	case CounterAveragePerSecond, CounterAveragePerHour:
+		return nil
+	}
+	return fmt.Errorf("invalid counter_average: %s (must be per_second or per_hour)", rule.CounterAverage)
+}
+
+func validateLoadLevelThresholds(thresholds LoadLevelThresholds) error {
+	levels := make([]LoadLevel, 0, len(thresholds))
+	for level := range thresholds {
+		levels = append(levels, level)
+	}
+	sort.Slice(levels, func(i, j int) bool { return levels[i] < levels[j] })
+
+	for _, level := range levels {
+		if err := validateLoadLevelName(level); err != nil {
+			return err
 		}
+		t := thresholds[level]
+		if t == nil {
+			continue
+		}
+		if t.Low >= t.High {
+			return fmt.Errorf("%s load level: low threshold must be less than high threshold", level)
+		}
+	}
+	return nil
+}
+
+// loadLevelPattern restricts level names to something usable as TOML keys and CLI flags
+var loadLevelPattern = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
+
+func validateLoadLevelName(level LoadLevel) error {
+	if !loadLevelPattern.MatchString(string(level)) {
+		return fmt.Errorf("invalid load level %q (must be lower-case letters, digits, '_' or '-')", level)
 	}
-	if thresholds.Medium != nil {
-		if thresholds.Medium.Low >= thresholds.Medium.High {
-			return fmt.Errorf("medium load level: low threshold must be less than high threshold")
+	return nil
+}
+
+// ValidateLoadDetectionRule validates a load detection rule's metrics and thresholds
+func ValidateLoadDetectionRule(rule LoadDetectionRule) error {
+	if rule.RuleType != RuleTypeLoadDetection {
+		return fmt.Errorf("rule_type must be %s, got %q", RuleTypeLoadDetection, rule.RuleType)
+	}
+	if rule.Weight < 0 {
+		return fmt.Errorf("weight must not be negative")
+	}
+	if len(rule.Metrics) == 0 {
+		return fmt.Errorf("at least one metric is required")
+	}
+	for i, metric := range rule.Metrics {
+		if metric.Source == "" {
+			return fmt.Errorf("metrics[%d]: source is required", i)
+		}
+		if metric.Weight <= 0 {
+			return fmt.Errorf("metrics[%d]: weight must be positive", i)
 		}
 	}
-	if thresholds.High != nil {
-		if thresholds.High.Low >= thresholds.High.High {
-			return fmt.Errorf("high load level: low threshold must be less than high threshold")
+	if len(rule.Thresholds) == 0 {
+		return fmt.Errorf("at least one threshold is required")
+	}
+	for i, threshold := range rule.Thresholds {
+		if err := validateLoadLevelName(threshold.Level); err != nil {
+			return fmt.Errorf("thresholds[%d]: %w", i, err)
+		}
+		if threshold.MinValue != nil && threshold.MaxValue != nil && *threshold.MinValue >= *threshold.MaxValue {
+			return fmt.Errorf("thresholds[%d]: min_value must be less than max_value", i)
+		}
+	}
+	if len(rule.Levels) > 0 {
+		declared := make(map[LoadLevel]bool, len(rule.Levels))
+		for _, level := range rule.Levels {
+			if err := validateLoadLevelName(level); err != nil {
+				return fmt.Errorf("levels: %w", err)
+			}
+			if declared[level] {
+				return fmt.Errorf("levels: duplicate level %q", level)
+			}
+			declared[level] = true
+		}
+		for i, threshold := range rule.Thresholds {
+			if !declared[threshold.Level] {
+				return fmt.Errorf("thresholds[%d]: level %q is not listed in levels", i, threshold.Level)
+			}
+		}
+	}
+	return nil
+}
+
+var acsReleasePattern = regexp.MustCompile(`^\d+\.\d+(?:\.\d+)?$`)
+
+// ValidateRuleTest validates the fixture and expectations of a rule test
+func ValidateRuleTest(test RuleTest) error {
+	if strings.TrimSpace(test.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if test.LoadLevel != "" {
+		if err := validateLoadLevelName(test.LoadLevel); err != nil {
+			return err
 		}
 	}
+	if test.ACSVersion != "" && !acsReleasePattern.MatchString(test.ACSVersion) {
+		return fmt.Errorf("invalid acs_version: %s (expected a release like 4.7 or 4.7.1)", test.ACSVersion)
+	}
+	if test.Skipped {
+		if test.Status != "" || test.Value != nil || test.Message != "" || test.MessageContains != "" {
+			return fmt.Errorf("skipped tests cannot expect a status, value or message")
+		}
+		return nil
+	}
+	switch Status(strings.ToUpper(string(test.Status))) {
+	case "", StatusGreen, StatusYellow, StatusRed, StatusUnknown:
+	default:
+		return fmt.Errorf("invalid status: %s (must be one of: GREEN, YELLOW, RED, UNKNOWN)", test.Status)
+	}
+	if test.Tolerance < 0 {
+		return fmt.Errorf("tolerance must not be negative")
+	}
+	if test.Status == "" && test.Value == nil && test.Message == "" && test.MessageContains == "" {
+		return fmt.Errorf("at least one of status, value, message, message_contains or skipped is required")
+	}
 	return nil
 }
 
+// ParseLoadStrategy validates a load detection strategy name; empty selects max
+func ParseLoadStrategy(value string) (LoadStrategy, error) {
+	switch LoadStrategy(value) {
+	case "", LoadStrategyMax:
+		return LoadStrategyMax, nil
+	case LoadStrategyWeightedVote:
+		return LoadStrategyWeightedVote, nil
+	}
+	return "", fmt.Errorf("invalid load detection strategy: %s (must be one of: max, weighted_vote)", value)
+}
+
 func validateCorrelationConfig(config *CorrelationConfig) error {
 	for i, cond := range config.SuppressIf {
 		if err := validateCorrelationCondition(cond); err != nil {
@@ -267,9 +547,25 @@ func isValidPlaceholder(placeholder string, ruleType RuleType) bool {
 	case RuleTypeQueue:
 		return strings.Contains(placeholder, "add") || strings.Contains(placeholder, "remove") || strings.Contains(placeholder, "diff")
 	case RuleTypeHistogram:
-		return strings.Contains(placeholder, "p95") || strings.Contains(placeholder, "p99")
-	case RuleTypeComposite:
-		// Any placeholder is valid for composite (metric names)
+		for _, stat := range []string{"p50", "p75", "p95", "p99", "mean", "count"} {
+			if strings.Contains(placeholder, stat) {
+				return true
+			}
+		}
+		return false
+	case RuleTypeSummary:
+		// Summaries expose arbitrary quantiles, named like p99.9
+		name, _, _ := strings.Cut(placeholder, ":")
+		if name == "count" {
+			return true
+		}
+		_, err := ParseSummaryStatistic(name)
+		return err == nil
+	case RuleTypeCardinality:
+		return placeholder == "series" || strings.HasPrefix(placeholder, "series:") ||
+			strings.HasPrefix(placeholder, "top_label")
+	case RuleTypeComposite, RuleTypeExpression:
+		// Any placeholder is valid for composite (metric names) and expression (label names) rules
 		return true
 	}
 
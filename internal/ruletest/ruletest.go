@@ -0,0 +1,234 @@
+// Package ruletest runs the unit tests declared next to rules: inline metric
+// fixtures with the status, value and message a rule is expected to produce.
+package ruletest
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/stackrox/sensor-metrics-analyzer/internal/evaluator"
+	"github.com/stackrox/sensor-metrics-analyzer/internal/parser"
+	"github.com/stackrox/sensor-metrics-analyzer/internal/rules"
+)
+
+// defaultTolerance is the allowed difference from an expected value when a test sets none
+const defaultTolerance = 1e-9
+
+// Case is a single test of a rule
+type Case struct {
+	RuleFile string // Rule file the test belongs to
+	TestFile string // File the test was declared in
+	Rule     rules.Rule
+	Test     rules.RuleTest
+}
+
+// Name identifies the case as <rule file>/<test name>
+func (c Case) Name() string {
+	return strings.TrimSuffix(filepath.Base(c.RuleFile), ".toml") + "/" + c.Test.Name
+}
+
+// Result is the outcome of a case
+type Result struct {
+	Case     Case
+	Failures []string
+}
+
+// Passed reports whether every expectation of the case held
+func (r Result) Passed() bool {
+	return len(r.Failures) == 0
+}
+
+// testFile is the layout of a sibling <rule>_test.toml file
+type testFile struct {
+	Tests []rules.RuleTest `toml:"tests"`
+}
+
+// LoadCases loads the tests of every rule in a directory, both those inline in
+// the rule files and those in sibling _test.toml files
+func LoadCases(rulesDir string) ([]Case, error) {
+	files, err := filepath.Glob(filepath.Join(rulesDir, "*.toml"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to glob rules directory: %w", err)
+	}
+
+	ruleFiles := make(map[string]bool)
+	for _, file := range files {
+		if !rules.IsTestFile(file) {
+			ruleFiles[file] = true
+		}
+	}
+
+	var cases []Case
+	for _, file := range files {
+		if rules.IsTestFile(file) {
+			ruleFile := strings.TrimSuffix(file, rules.TestFileSuffix) + ".toml"
+			if !ruleFiles[ruleFile] {
+				return nil, fmt.Errorf("%s: no rule file %s", file, filepath.Base(ruleFile))
+			}
+			continue
+		}
+
+		rule, err := rules.LoadRule(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load rule %s: %w", file, err)
+		}
+		for _, test := range rule.Tests {
+			cases = append(cases, Case{RuleFile: file, TestFile: file, Rule: rule, Test: test})
+		}
+
+		siblingFile := strings.TrimSuffix(file, ".toml") + rules.TestFileSuffix
+		siblingTests, err := loadTestFile(siblingFile)
+		if err != nil {
+			return nil, err
+		}
+		for _, test := range siblingTests {
+			cases = append(cases, Case{RuleFile: file, TestFile: siblingFile, Rule: rule, Test: test})
+		}
+	}
+	return cases, nil
+}
+
+// loadTestFile loads and validates a sibling test file; a missing file has no tests
+func loadTestFile(path string) ([]rules.RuleTest, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", path, err)
+	}
+
+	var file testFile
+	if err := toml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse TOML %s: %w", path, err)
+	}
+	for i, test := range file.Tests {
+		if err := rules.ValidateRuleTest(test); err != nil {
+			return nil, fmt.Errorf("validation failed for %s: tests[%d]: %w", path, i, err)
+		}
+	}
+	return file.Tests, nil
+}
+
+// Run evaluates the rule of a case against its fixture and checks the
+// expectations. loadLevels are the declared load levels a test may use.
+func Run(c Case, loadLevels []rules.LoadLevel) Result {
+	result := Result{Case: c}
+	fail := func(format string, args ...interface{}) Result {
+		result.Failures = append(result.Failures, fmt.Sprintf(format, args...))
+		return result
+	}
+	test := c.Test
+
+	loadLevel := test.LoadLevel
+	if loadLevel == "" {
+		loadLevel = rules.DefaultLoadLevel(loadLevels)
+	}
+	if !containsLevel(loadLevels, loadLevel) {
+		return fail("load_level %s is not declared (declared: %s)", loadLevel, rules.FormatLoadLevels(loadLevels))
+	}
+
+	metrics, _, err := parser.ParseReaderWithOptions(strings.NewReader(test.Metrics), parser.ParseOptions{Strict: true})
+	if err != nil {
+		return fail("invalid metrics fixture: %v", err)
+	}
+
+	acsVersion := test.ACSVersion
+	if acsVersion == "" {
+		acsVersion, _ = metrics.DetectACSVersion()
+	}
+	applicable := acsVersion == "" || evaluator.IsRuleApplicable(c.Rule, acsVersion)
+	if test.Skipped {
+		if acsVersion == "" {
+			return fail("skipped tests need an acs_version or an ACS version in the metrics")
+		}
+		if applicable {
+			return fail("rule applies to ACS version %q, want it skipped", acsVersion)
+		}
+		return result
+	}
+	if !applicable {
+		return fail("rule does not apply to ACS version %s", acsVersion)
+	}
+
+	// Fixtures have no scrape time other than sample timestamps
+	var window rules.CounterWindow
+	window.Uptime, _ = metrics.Uptime(time.Time{})
+	evaluated := evaluator.EvaluateRule(c.Rule, metrics, loadLevel, window)
+	got, err := selectResult(evaluated, test.Result)
+	if err != nil {
+		return fail("%v", err)
+	}
+
+	if test.Status != "" && !strings.EqualFold(string(got.Status), string(test.Status)) {
+		fail("status = %s, want %s (message: %s)", got.Status, strings.ToUpper(string(test.Status)), got.Message)
+	}
+	if test.Value != nil {
+		tolerance := test.Tolerance
+		if tolerance == 0 {
+			tolerance = defaultTolerance
+		}
+		if math.Abs(got.Value-*test.Value) > tolerance {
+			fail("value = %g, want %g", got.Value, *test.Value)
+		}
+	}
+	if test.Message != "" && got.Message != test.Message {
+		fail("message = %q, want %q", got.Message, test.Message)
+	}
+	if test.MessageContains != "" && !strings.Contains(got.Message, test.MessageContains) {
+		fail("message = %q, want it to contain %q", got.Message, test.MessageContains)
+	}
+	return result
+}
+
+// RunAll runs every case in order
+func RunAll(cases []Case, loadLevels []rules.LoadLevel) []Result {
+	results := make([]Result, 0, len(cases))
+	for _, c := range cases {
+		results = append(results, Run(c, loadLevels))
+	}
+	return results
+}
+
+// selectResult picks the result a test checks: the one with the given rule
+// name, or the only result when no name is given
+func selectResult(results []rules.EvaluationResult, name string) (rules.EvaluationResult, error) {
+	if name != "" {
+		for _, r := range results {
+			if r.RuleName == name {
+				return r, nil
+			}
+		}
+		return rules.EvaluationResult{}, fmt.Errorf("no result %q (results: %s)", name, resultNames(results))
+	}
+
+	switch len(results) {
+	case 0:
+		return rules.EvaluationResult{}, fmt.Errorf("rule produced no results")
+	case 1:
+		return results[0], nil
+	}
+	return rules.EvaluationResult{}, fmt.Errorf("rule produced %d results, set result to one of: %s", len(results), resultNames(results))
+}
+
+func resultNames(results []rules.EvaluationResult) string {
+	names := make([]string, len(results))
+	for i, r := range results {
+		names[i] = fmt.Sprintf("%q", r.RuleName)
+	}
+	return strings.Join(names, ", ")
+}
+
+func containsLevel(levels []rules.LoadLevel, level rules.LoadLevel) bool {
+	for _, l := range levels {
+		if l == level {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+package ruletest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stackrox/sensor-metrics-analyzer/internal/rules"
+)
+
+const gaugeRule = `rule_type = "gauge_threshold"
+metric_name = "queue_size"
+display_name = "queue_size"
+acs_versions = ["4.8+"]
+
+[thresholds]
+low = 10
+high = 100
+higher_is_worse = true
+
+[load_level_thresholds.xlarge]
+low = 100
+high = 1000
+higher_is_worse = true
+
+[messages]
+green = "Queue size {value:.0f}"
+yellow = "Queue size {value:.0f} - elevated"
+red = "Queue size {value:.0f} - full"
+
+[[tests]]
+name = "inline"
+metrics = "queue_size 5"
+status = "GREEN"
+`
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadCases(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "queue_size.toml", gaugeRule)
+	writeFile(t, dir, "queue_size_test.toml", "[[tests]]\nname = \"sibling\"\nmetrics = \"queue_size 50\"\nstatus = \"YELLOW\"\n")
+
+	cases, err := LoadCases(dir)
+	if err != nil {
+		t.Fatalf("LoadCases() error = %v", err)
+	}
+	if len(cases) != 2 {
+		t.Fatalf("LoadCases() got %d cases, want 2", len(cases))
+	}
+	if cases[0].Name() != "queue_size/inline" || cases[1].Name() != "queue_size/sibling" {
+		t.Errorf("LoadCases() names = %s, %s", cases[0].Name(), cases[1].Name())
+	}
+	if filepath.Base(cases[1].TestFile) != "queue_size_test.toml" {
+		t.Errorf("TestFile = %s, want the sibling file", cases[1].TestFile)
+	}
+
+	writeFile(t, dir, "orphan_test.toml", "[[tests]]\nname = \"orphan\"\nstatus = \"GREEN\"\n")
+	if _, err := LoadCases(dir); err == nil {
+		t.Error("LoadCases() expected error for a test file without a rule")
+	}
+}
+
+func TestRun(t *testing.T) {
+	value := func(v float64) *float64 { return &v }
+	rule, err := loadRule(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	levels := []rules.LoadLevel{"small", "medium", "xlarge"}
+
+	tests := map[string]struct {
+		test         rules.RuleTest
+		wantFailures []string
+	}{
+		"should pass matching expectations": {
+			test: rules.RuleTest{Name: "t", Metrics: "queue_size 50", Status: "yellow", Value: value(50), Message: "Queue size 50 - elevated"},
+		},
+		"should use load level thresholds": {
+			test: rules.RuleTest{Name: "t", Metrics: "queue_size 50", LoadLevel: "xlarge", Status: rules.StatusGreen},
+		},
+		"should report a wrong status": {
+			test:         rules.RuleTest{Name: "t", Metrics: "queue_size 500", Status: rules.StatusYellow},
+			wantFailures: []string{"status = RED, want YELLOW"},
+		},
+		"should report every failed expectation": {
+			test:         rules.RuleTest{Name: "t", Metrics: "queue_size 5", Value: value(6), MessageContains: "elevated"},
+			wantFailures: []string{"value = 5, want 6", "want it to contain \"elevated\""},
+		},
+		"should allow a tolerance": {
+			test: rules.RuleTest{Name: "t", Metrics: "queue_size 5", Value: value(6), Tolerance: 1},
+		},
+		"should reject an undeclared load level": {
+			test:         rules.RuleTest{Name: "t", Metrics: "queue_size 5", LoadLevel: "high", Status: rules.StatusGreen},
+			wantFailures: []string{"load_level high is not declared"},
+		},
+		"should report a malformed fixture": {
+			test:         rules.RuleTest{Name: "t", Metrics: "queue_size five", Status: rules.StatusGreen},
+			wantFailures: []string{"invalid metrics fixture"},
+		},
+		"should pass when the rule is skipped for the version": {
+			test: rules.RuleTest{Name: "t", Metrics: "queue_size 5", ACSVersion: "4.7", Skipped: true},
+		},
+		"should fail when the rule applies but is expected skipped": {
+			test:         rules.RuleTest{Name: "t", Metrics: "queue_size 5", ACSVersion: "4.8", Skipped: true},
+			wantFailures: []string{"want it skipped"},
+		},
+		"should fail when the rule does not apply": {
+			test:         rules.RuleTest{Name: "t", Metrics: "queue_size 5", ACSVersion: "4.7", Status: rules.StatusGreen},
+			wantFailures: []string{"does not apply to ACS version 4.7"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			result := Run(Case{Rule: rule, Test: tt.test}, levels)
+			if len(result.Failures) != len(tt.wantFailures) {
+				t.Fatalf("Run() failures = %q, want %d", result.Failures, len(tt.wantFailures))
+			}
+			for i, want := range tt.wantFailures {
+				if !strings.Contains(result.Failures[i], want) {
+					t.Errorf("Run() failure %d = %q, want it to contain %q", i, result.Failures[i], want)
+				}
+			}
+		})
+	}
+}
+
+func loadRule(t *testing.T) (rules.Rule, error) {
+	dir := t.TempDir()
+	writeFile(t, dir, "queue_size.toml", gaugeRule)
+	return rules.LoadRule(filepath.Join(dir, "queue_size.toml"))
+}
@@ -0,0 +1,432 @@
+// Package scaffold proposes a pre-filled rule for a metric of a sample scrape:
+// the rule type from the metric's type and labels, and thresholds from the
+// values observed in the scrape.
+package scaffold
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/stackrox/sensor-metrics-analyzer/internal/evaluator"
+	"github.com/stackrox/sensor-metrics-analyzer/internal/parser"
+	"github.com/stackrox/sensor-metrics-analyzer/internal/rules"
+)
+
+const (
+	// The sample scrape is assumed healthy: warn at twice the observed value
+	// and turn red at five times it
+	warnFactor = 2
+	redFactor  = 5
+
+	// Thresholds used when the scrape has no observations to derive them from
+	defaultLow  = 100
+	defaultHigh = 1000
+)
+
+// reviewedGenerated marks generated rules as not reviewed yet
+const reviewedGenerated = "No, generated by new-rule"
+
+// Scaffold is a proposed rule and what its thresholds were derived from
+type Scaffold struct {
+	Rule     rules.Rule
+	Unit     string        // Guessed from the metric name and HELP text; empty when unknown
+	Observed float64       // Value the thresholds were derived from
+	Derived  bool          // False when the scrape had no observations and default thresholds were used
+	Uptime   time.Duration // Sensor uptime counters were averaged over; zero when unknown
+	Release  string        // ACS release of the scrape, e.g. "4.8"; empty when unknown
+}
+
+// Generate inspects a metric of a scrape and proposes a rule for it: a
+// histogram or summary rule for histograms and summaries, a queue rule for
+// metrics with an Operation label with Add and Remove values, and a gauge rule
+// otherwise. Gauge rules for counters average them per second over the Sensor
+// uptime at scrapeTime, as lifetime totals grow with the uptime. metricName may
+// also name a series of a histogram or summary, e.g. its _bucket or _count series.
+func Generate(metrics parser.MetricsData, metricName string, scrapeTime time.Time) (Scaffold, error) {
+	name := distributionBaseName(metrics, metricName)
+	rule := rules.Rule{
+		MetricName:   name,
+		DisplayName:  name,
+		Reviewed:     reviewedGenerated,
+		LastReviewOn: "never",
+	}
+
+	help := metricHelp(metrics, name)
+	rule.Description = help
+	if rule.Description == "" {
+		rule.Description = "TODO: describe what the metric measures."
+	}
+	s := Scaffold{Unit: evaluator.GuessMetricUnit(name, help)}
+	if version, ok := metrics.DetectACSVersion(); ok {
+		s.Release = releaseOf(version)
+	}
+	metric, exists := metrics.GetMetric(name)
+	switch {
+	case isHistogram(metrics, name):
+		rule.RuleType = rules.RuleTypeHistogram
+		rule.HistogramConfig = &rules.HistogramConfig{Unit: s.Unit}
+	case exists && metric.Type == "summary":
+		rule.RuleType = rules.RuleTypeSummary
+		rule.SummaryConfig = &rules.SummaryConfig{Unit: s.Unit, Statistic: summaryStatistic(metric)}
+	case !exists || len(metric.Values) == 0:
+		return Scaffold{}, fmt.Errorf("metric %s not found in the scrape", metricName)
+	default:
+		if queue := queueConfig(metric); queue != nil {
+			rule.RuleType = rules.RuleTypeQueue
+			rule.QueueConfig = queue
+		} else {
+			rule.RuleType = rules.RuleTypeGauge
+			if seriesCount(metric) > 1 {
+				rule.Aggregation = rules.AggregationMax
+			}
+			if metric.Type == "counter" {
+				rule.CounterAverage = rules.CounterAveragePerSecond
+			}
+		}
+	}
+
+	var window rules.CounterWindow
+	if rule.CounterAverage != "" {
+		window.Uptime, _ = metrics.Uptime(scrapeTime)
+		s.Uptime = window.Uptime
+	}
+	s.Observed, s.Derived = observe(rule, metrics, window)
+	s.Rule = withThresholds(rule, s)
+	s.Rule.Messages = messages(s.Rule, s.Unit)
+	s.Rule.Remediation = &rules.Remediation{
+		Red:    fmt.Sprintf("TODO: describe what to do when %s is critical.", name),
+		Yellow: fmt.Sprintf("TODO: describe what to watch when %s is elevated.", name),
+	}
+	return s, nil
+}
+
+// distributionBaseName maps a _bucket, _sum or _count series to its histogram or summary
+func distributionBaseName(metrics parser.MetricsData, name string) string {
+	for _, suffix := range []string{"_bucket", "_sum", "_count"} {
+		base, found := strings.CutSuffix(name, suffix)
+		if !found {
+			continue
+		}
+		if summary, ok := metrics.GetMetric(base); isHistogram(metrics, base) || (ok && summary.Type == "summary") {
+			return base
+		}
+	}
+	return name
+}
+
+// summaryStatistic picks the highest quantile a summary exposes, or the mean
+// of a summary without quantiles
+func summaryStatistic(metric *parser.Metric) string {
+	statistic := "mean"
+	highest := -1.0
+	for _, s := range metric.GetSummarySeries() {
+		for _, q := range s.Quantiles {
+			if q.Quantile > highest {
+				highest = q.Quantile
+				statistic = evaluator.SummaryQuantileName(q.Quantile)
+			}
+		}
+	}
+	return statistic
+}
+
+// metricHelp returns the HELP text of a metric, or of its histogram buckets
+func metricHelp(metrics parser.MetricsData, name string) string {
+	for _, candidate := range []string{name, name + "_bucket"} {
+		if metric, ok := metrics.GetMetric(candidate); ok && metric.Help != "" {
+			return metric.Help
+		}
+	}
+	return ""
+}
+
+func isHistogram(metrics parser.MetricsData, name string) bool {
+	if metric, ok := metrics.GetMetric(name); ok && metric.Type == "histogram" {
+		return true
+	}
+	buckets, ok := metrics.GetMetric(name + "_bucket")
+	return ok && len(buckets.Values) > 0
+}
+
+// queueConfig detects an operation label with Add and Remove values
+func queueConfig(metric *parser.Metric) *rules.QueueConfig {
+	for _, v := range metric.Values {
+		for label := range v.Labels {
+			if !strings.EqualFold(label, "operation") {
+				continue
+			}
+			config := &rules.QueueConfig{OperationLabel: label}
+			for op := range metric.GetValuesByLabel(label) {
+				switch strings.ToLower(op) {
+				case "add":
+					config.AddValue = op
+				case "remove":
+					config.RemoveValue = op
+				}
+			}
+			if config.AddValue != "" && config.RemoveValue != "" {
+				return config
+			}
+		}
+	}
+	return nil
+}
+
+// seriesCount counts the distinct label sets of a metric
+func seriesCount(metric *parser.Metric) int {
+	seen := make(map[string]bool)
+	for _, v := range metric.Values {
+		seen[parser.SeriesKey(v.Labels)] = true
+	}
+	return len(seen)
+}
+
+// observe evaluates the rule against the scrape and returns the worst value
+// it grades: the largest value, or the largest p95 of a histogram's series
+func observe(rule rules.Rule, metrics parser.MetricsData, window rules.CounterWindow) (float64, bool) {
+	observed, found := 0.0, false
+	for _, result := range evaluator.EvaluateRule(rule, metrics, rules.LoadLevelMedium, window) {
+		if !found || result.Value > observed {
+			observed = result.Value
+		}
+		found = true
+	}
+	return observed, found && observed > 0
+}
+
+// withThresholds sets thresholds derived from the observed value
+func withThresholds(rule rules.Rule, s Scaffold) rules.Rule {
+	low, high := float64(defaultLow), float64(defaultHigh)
+	if s.Derived {
+		low = niceCeil(s.Observed * warnFactor)
+		high = niceCeil(s.Observed * redFactor)
+		if high <= low {
+			high = niceCeil(low * 2)
+		}
+	}
+
+	switch rule.RuleType {
+	case rules.RuleTypeHistogram, rules.RuleTypeSummary:
+		rule.Thresholds = rules.Thresholds{P95Good: low, P95Warn: high}
+	case rules.RuleTypeQueue:
+		rule.Thresholds = rules.Thresholds{Low: low, High: high}
+	default:
+		rule.Thresholds = rules.Thresholds{Low: low, High: high, HigherIsWorse: true}
+	}
+	return rule
+}
+
+// niceCeil rounds up to the next 1, 2 or 5 times a power of ten
+func niceCeil(value float64) float64 {
+	if value <= 0 {
+		return 0
+	}
+	magnitude := math.Pow(10, math.Floor(math.Log10(value)))
+	for _, step := range []float64{1, 2, 5, 10} {
+		// Rounded to absorb floating point error in magnitude
+		if candidate := roundSignificant(step * magnitude); candidate >= value {
+			return candidate
+		}
+	}
+	return roundSignificant(10 * magnitude)
+}
+
+func roundSignificant(value float64) float64 {
+	rounded, _ := strconv.ParseFloat(strconv.FormatFloat(value, 'g', 6, 64), 64)
+	return rounded
+}
+
+// messages proposes message templates using the placeholders of the rule type
+func messages(rule rules.Rule, unit string) rules.Messages {
+	suffix := unitSuffix(unit)
+	switch rule.RuleType {
+	case rules.RuleTypeHistogram:
+		format := "p95={p95:.3f}" + suffix + ", p99={p99:.3f}" + suffix + " (%s)"
+		return rules.Messages{
+			Green:  fmt.Sprintf(format, "good"),
+			Yellow: fmt.Sprintf(format, "elevated"),
+			Red:    fmt.Sprintf(format, "high"),
+		}
+	case rules.RuleTypeSummary:
+		format := "{value:.3f}" + suffix + " (%s)"
+		return rules.Messages{
+			Green:  fmt.Sprintf(format, "good"),
+			Yellow: fmt.Sprintf(format, "elevated"),
+			Red:    fmt.Sprintf(format, "high"),
+		}
+	case rules.RuleTypeQueue:
+		format := "Add={add:.0f}, Remove={remove:.0f}, Diff={diff:.0f} (%s)"
+		return rules.Messages{
+			Green:  fmt.Sprintf(format, "balanced"),
+			Yellow: fmt.Sprintf(format, "backlog forming"),
+			Red:    fmt.Sprintf(format, "backed up"),
+		}
+	}
+	format := "{value:.0f}" + suffix + " (%s)"
+	if rule.CounterAverage == rules.CounterAveragePerSecond {
+		format = "{value:.2f}" + suffix + "/s (%s)"
+	}
+	return rules.Messages{
+		Green:  fmt.Sprintf(format, "healthy"),
+		Yellow: fmt.Sprintf(format, "elevated"),
+		Red:    fmt.Sprintf(format, "critical"),
+	}
+}
+
+func unitSuffix(unit string) string {
+	switch unit {
+	case "seconds":
+		return "s"
+	case "milliseconds":
+		return "ms"
+	case "":
+		return ""
+	}
+	return " " + unit
+}
+
+// releaseOf returns the major.minor release of a version such as "4.8.2"
+func releaseOf(version string) string {
+	parts := strings.SplitN(strings.TrimPrefix(version, "v"), ".", 3)
+	if len(parts) < 2 {
+		return ""
+	}
+	for _, part := range parts[:2] {
+		if _, err := strconv.Atoi(part); err != nil {
+			return ""
+		}
+	}
+	return parts[0] + "." + parts[1]
+}
+
+// TOML renders the rule as a rule file in the layout of the bundled rules and
+// checks that it loads
+func (s Scaffold) TOML() ([]byte, error) {
+	rule := s.Rule
+	var b strings.Builder
+	line := func(format string, args ...interface{}) {
+		fmt.Fprintf(&b, format+"\n", args...)
+	}
+
+	line("rule_type = %s", quote(string(rule.RuleType)))
+	line("metric_name = %s", quote(rule.MetricName))
+	line("display_name = %s", quote(rule.DisplayName))
+	line("description = %s", quote(rule.Description))
+	line("reviewed = %s", quote(rule.Reviewed))
+	line("last_review_by = %s", quote(rule.LastReviewBy))
+	line("last_review_on = %s", quote(rule.LastReviewOn))
+	if s.Release != "" {
+		// A rule constrained to the scraped release would skip older clusters
+		line("# Uncomment only if the metric is missing before %s:", s.Release)
+		line("# acs_versions = [%s]", quote(s.Release+"+"))
+	}
+	if rule.Aggregation != "" {
+		line("aggregation = %s", quote(string(rule.Aggregation)))
+	}
+	if rule.CounterAverage != "" {
+		line("counter_average = %s", quote(string(rule.CounterAverage)))
+	}
+
+	if rule.QueueConfig != nil {
+		line("\n[queue_config]")
+		line("operation_label = %s", quote(rule.QueueConfig.OperationLabel))
+		line("add_value = %s", quote(rule.QueueConfig.AddValue))
+		line("remove_value = %s", quote(rule.QueueConfig.RemoveValue))
+	}
+	if rule.HistogramConfig != nil && rule.HistogramConfig.Unit != "" {
+		line("\n[histogram_config]")
+		line("unit = %s", quote(rule.HistogramConfig.Unit))
+	}
+	if rule.SummaryConfig != nil {
+		line("\n[summary_config]")
+		if rule.SummaryConfig.Unit != "" {
+			line("unit = %s", quote(rule.SummaryConfig.Unit))
+		}
+		line("statistic = %s", quote(rule.SummaryConfig.Statistic))
+	}
+
+	line("\n[thresholds]")
+	switch {
+	case s.Derived && s.Uptime > 0:
+		line("# Proposed from the sample scrape, where the rule graded %s averaged over %s of uptime",
+			formatNumber(s.Observed), s.Uptime.Round(time.Second))
+	case s.Derived:
+		line("# Proposed from the sample scrape, where the rule graded %s", formatNumber(s.Observed))
+	case s.Rule.CounterAverage != "":
+		line("# The Sensor uptime of the sample scrape is unknown; adjust these placeholders")
+	default:
+		line("# The sample scrape had no observations; adjust these placeholders")
+	}
+	if rule.RuleType == rules.RuleTypeHistogram || rule.RuleType == rules.RuleTypeSummary {
+		line("p95_good = %s", formatNumber(rule.Thresholds.P95Good))
+		line("p95_warn = %s", formatNumber(rule.Thresholds.P95Warn))
+	} else {
+		line("low = %s", formatNumber(rule.Thresholds.Low))
+		line("high = %s", formatNumber(rule.Thresholds.High))
+		if rule.Thresholds.HigherIsWorse {
+			line("higher_is_worse = true")
+		}
+	}
+
+	line("\n[messages]")
+	line("green = %s", quote(rule.Messages.Green))
+	line("yellow = %s", quote(rule.Messages.Yellow))
+	line("red = %s", quote(rule.Messages.Red))
+
+	line("\n[remediation]")
+	line("red = %s", quote(rule.Remediation.Red))
+	line("yellow = %s", quote(rule.Remediation.Yellow))
+
+	data := []byte(b.String())
+	if err := check(data); err != nil {
+		return nil, fmt.Errorf("generated rule is invalid: %w", err)
+	}
+	return data, nil
+}
+
+// check decodes a rendered rule like the rule loader does
+func check(data []byte) error {
+	var rule rules.Rule
+	meta, err := toml.Decode(string(data), &rule)
+	if err != nil {
+		return err
+	}
+	if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+		return fmt.Errorf("unknown key %q", undecoded[0].String())
+	}
+	return rules.ValidateRule(rule)
+}
+
+// quote renders a TOML basic string
+func quote(value string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range value {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04X`, r)
+				continue
+			}
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
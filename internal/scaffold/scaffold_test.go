@@ -0,0 +1,206 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stackrox/sensor-metrics-analyzer/internal/parser"
+	"github.com/stackrox/sensor-metrics-analyzer/internal/rules"
+)
+
+const sampleMetrics = `# TYPE rox_sensor_info gauge
+rox_sensor_info{version="4.8.2"} 1
+# HELP rox_sensor_queue_size Number of items in the queue
+# TYPE rox_sensor_queue_size gauge
+rox_sensor_queue_size{component="a"} 30
+rox_sensor_queue_size{component="b"} 45
+# TYPE rox_sensor_buffer_size gauge
+rox_sensor_buffer_size 0
+# TYPE rox_sensor_queue_operations_total counter
+rox_sensor_queue_operations_total{Operation="Add"} 120
+rox_sensor_queue_operations_total{Operation="Remove"} 100
+# HELP rox_sensor_event_duration Event processing time in milliseconds
+# TYPE rox_sensor_event_duration histogram
+rox_sensor_event_duration_bucket{le="10"} 50
+rox_sensor_event_duration_bucket{le="100"} 100
+rox_sensor_event_duration_bucket{le="+Inf"} 100
+rox_sensor_event_duration_sum 1200
+rox_sensor_event_duration_count 100
+# TYPE rox_sensor_latency summary
+rox_sensor_latency{quantile="0.5"} 1
+rox_sensor_latency{quantile="0.9"} 3
+rox_sensor_latency_sum 5
+rox_sensor_latency_count 5
+# TYPE rox_sensor_flush_seconds summary
+rox_sensor_flush_seconds_sum 12
+rox_sensor_flush_seconds_count 4
+# TYPE process_start_time_seconds gauge
+process_start_time_seconds 1000
+# TYPE rox_sensor_dedupe_cache_hits counter
+rox_sensor_dedupe_cache_hits 80
+`
+
+func TestGenerate(t *testing.T) {
+	metrics, err := parser.ParseReader(strings.NewReader(sampleMetrics))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		metric     string
+		scrapeTime time.Time
+		wantError  string
+		checkFunc  func(*testing.T, Scaffold)
+	}{
+		"should propose a gauge rule for the worst series": {
+			metric: "rox_sensor_queue_size",
+			checkFunc: func(t *testing.T, s Scaffold) {
+				r := s.Rule
+				if r.RuleType != rules.RuleTypeGauge || r.Aggregation != rules.AggregationMax {
+					t.Errorf("rule_type = %s, aggregation = %s, want gauge_threshold with max", r.RuleType, r.Aggregation)
+				}
+				if r.Thresholds.Low != 100 || r.Thresholds.High != 500 || !r.Thresholds.HigherIsWorse {
+					t.Errorf("thresholds = %+v, want 100/500 from the observed 45", r.Thresholds)
+				}
+				if r.Description != "Number of items in the queue" || len(r.ACSVersions) > 0 || s.Release != "4.8" {
+					t.Errorf("description = %q, acs_versions = %v, release = %q", r.Description, r.ACSVersions, s.Release)
+				}
+				if content, _ := s.TOML(); !strings.Contains(string(content), "\n# acs_versions = [\"4.8+\"]\n") {
+					t.Errorf("TOML() = %s, want acs_versions commented out", content)
+				}
+			},
+		},
+		"should propose a queue rule for Add and Remove operations": {
+			metric: "rox_sensor_queue_operations_total",
+			checkFunc: func(t *testing.T, s Scaffold) {
+				if s.Rule.RuleType != rules.RuleTypeQueue || s.Rule.QueueConfig.OperationLabel != "Operation" {
+					t.Errorf("rule = %s %+v, want queue_operations on Operation", s.Rule.RuleType, s.Rule.QueueConfig)
+				}
+				if s.Observed != 20 || s.Rule.Thresholds.Low != 50 || s.Rule.Thresholds.High != 100 {
+					t.Errorf("observed = %g, thresholds = %+v, want 50/100 from the diff 20", s.Observed, s.Rule.Thresholds)
+				}
+			},
+		},
+		"should propose a histogram rule from a bucket series": {
+			metric: "rox_sensor_event_duration_bucket",
+			checkFunc: func(t *testing.T, s Scaffold) {
+				r := s.Rule
+				if r.RuleType != rules.RuleTypeHistogram || r.MetricName != "rox_sensor_event_duration" {
+					t.Errorf("rule = %s %s, want a histogram rule on the base name", r.RuleType, r.MetricName)
+				}
+				if r.HistogramConfig.Unit != "milliseconds" || !strings.Contains(r.Messages.Red, "{p95:.3f}ms") {
+					t.Errorf("unit = %q, red = %q, want milliseconds from HELP", r.HistogramConfig.Unit, r.Messages.Red)
+				}
+				if r.Thresholds.P95Good != 200 || r.Thresholds.P95Warn != 500 {
+					t.Errorf("thresholds = %+v, want 200/500 from the observed p95", r.Thresholds)
+				}
+			},
+		},
+		"should use placeholder thresholds without observations": {
+			metric: "rox_sensor_buffer_size",
+			checkFunc: func(t *testing.T, s Scaffold) {
+				if s.Derived || s.Rule.Thresholds.Low != defaultLow || s.Rule.Thresholds.High != defaultHigh {
+					t.Errorf("derived = %v, thresholds = %+v, want the defaults", s.Derived, s.Rule.Thresholds)
+				}
+			},
+		},
+		"should propose a summary rule on the highest quantile": {
+			metric: "rox_sensor_latency_count",
+			checkFunc: func(t *testing.T, s Scaffold) {
+				r := s.Rule
+				if r.RuleType != rules.RuleTypeSummary || r.MetricName != "rox_sensor_latency" || r.SummaryConfig.Statistic != "p90" {
+					t.Errorf("rule = %s %s %+v, want a summary rule on p90 of the base name", r.RuleType, r.MetricName, r.SummaryConfig)
+				}
+				if s.Observed != 3 || r.Thresholds.P95Good != 10 || r.Thresholds.P95Warn != 20 {
+					t.Errorf("observed = %g, thresholds = %+v, want 10/20 from the observed 3", s.Observed, r.Thresholds)
+				}
+			},
+		},
+		"should propose a summary rule on the mean without quantiles": {
+			metric: "rox_sensor_flush_seconds",
+			checkFunc: func(t *testing.T, s Scaffold) {
+				if s.Rule.SummaryConfig.Statistic != "mean" || s.Observed != 3 {
+					t.Errorf("statistic = %q, observed = %g, want the mean 3", s.Rule.SummaryConfig.Statistic, s.Observed)
+				}
+			},
+		},
+		"should average counters per second over the uptime": {
+			metric:     "rox_sensor_dedupe_cache_hits",
+			scrapeTime: time.Unix(1100, 0),
+			checkFunc: func(t *testing.T, s Scaffold) {
+				r := s.Rule
+				if r.RuleType != rules.RuleTypeGauge || r.CounterAverage != rules.CounterAveragePerSecond {
+					t.Errorf("rule = %s with counter_average %q, want gauge_threshold per_second", r.RuleType, r.CounterAverage)
+				}
+				if s.Observed != 0.8 || r.Thresholds.Low != 2 || r.Thresholds.High != 5 {
+					t.Errorf("observed = %g, thresholds = %+v, want 2/5 from 80 over 100s", s.Observed, r.Thresholds)
+				}
+			},
+		},
+		"should use placeholder thresholds for counters without uptime": {
+			metric: "rox_sensor_dedupe_cache_hits",
+			checkFunc: func(t *testing.T, s Scaffold) {
+				if s.Rule.CounterAverage != rules.CounterAveragePerSecond || s.Derived {
+					t.Errorf("counter_average = %q, derived = %v, want per_second with the defaults", s.Rule.CounterAverage, s.Derived)
+				}
+			},
+		},
+		"should reject missing metrics": {
+			metric:    "rox_sensor_missing",
+			wantError: "not found",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, err := Generate(metrics, tt.metric, tt.scrapeTime)
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Fatalf("Generate() error = %v, want it to contain %q", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			tt.checkFunc(t, s)
+
+			// The rendered file must load like any other rule
+			content, err := s.TOML()
+			if err != nil {
+				t.Fatalf("TOML() error = %v", err)
+			}
+			path := filepath.Join(t.TempDir(), "rule.toml")
+			if err := os.WriteFile(path, content, 0600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := rules.LoadRule(path); err != nil {
+				t.Errorf("LoadRule() error = %v\n%s", err, content)
+			}
+		})
+	}
+}
+
+func TestNiceCeil(t *testing.T) {
+	tests := map[string]struct {
+		value float64
+		want  float64
+	}{
+		"should keep a nice value":         {value: 200, want: 200},
+		"should round up to 2":             {value: 110, want: 200},
+		"should round up to 5":             {value: 0.3, want: 0.5},
+		"should round up to the next tens": {value: 6000, want: 10000},
+		"should return 0 for 0":            {value: 0, want: 0},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := niceCeil(tt.value); got != tt.want {
+				t.Errorf("niceCeil(%g) = %g, want %g", tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package scrape
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// acceptHeader prefers the classic text format, which is what metrics dumps contain;
+// the parser detects OpenMetrics and protobuf responses as well.
+const acceptHeader = "text/plain;version=0.0.4;q=1,application/openmetrics-text;version=1.0.0;q=0.5,*/*;q=0.1"
+
+// DefaultTimeout bounds a single scrape when Options.Timeout is zero
+const DefaultTimeout = 30 * time.Second
+
+// Options configures how a metrics endpoint is scraped
+type Options struct {
+	URL string
+
+	// BearerToken is sent in the Authorization header. BearerTokenFile is read
+	// instead when set, e.g. a mounted service account token.
+	BearerToken     string
+	BearerTokenFile string
+
+	// CAFile verifies the server certificate; CertFile and KeyFile authenticate
+	// the client with mutual TLS.
+	CAFile             string
+	CertFile           string
+	KeyFile            string
+	InsecureSkipVerify bool
+
+	Timeout time.Duration
+}
+
+// Client scrapes a single metrics endpoint
+type Client struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+// NewClient validates the options and creates a client
+func NewClient(opts Options) (*Client, error) {
+	parsed, err := url.Parse(opts.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid metrics URL %q: must be an http:// or https:// URL", opts.URL)
+	}
+
+	token := opts.BearerToken
+	if opts.BearerTokenFile != "" {
+		data, err := os.ReadFile(opts.BearerTokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bearer token file: %w", err)
+		}
+		token = strings.TrimSpace(string(data))
+	}
+
+	tlsConfig, err := newTLSConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	return &Client{
+		url:    opts.URL,
+		token:  token,
+		client: &http.Client{Transport: transport, Timeout: timeout},
+	}, nil
+}
+
+func newTLSConfig(opts Options) (*tls.Config, error) {
+	config := &tls.Config{InsecureSkipVerify: opts.InsecureSkipVerify}
+
+	if opts.CAFile != "" {
+		pem, err := os.ReadFile(opts.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA file %s", opts.CAFile)
+		}
+		config.RootCAs = pool
+	}
+
+	if (opts.CertFile == "") != (opts.KeyFile == "") {
+		return nil, fmt.Errorf("client certificate and key must be specified together")
+	}
+	if opts.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
+
+// Scrape fetches the metrics once and returns the response body
+func (c *Client) Scrape(ctx context.Context) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", acceptHeader)
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scrape %s: %w", c.url, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response from %s: %w", c.url, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("scraping %s returned %s", c.url, resp.Status)
+	}
+	return body, nil
+}
+
+// ScrapeSeries scrapes samples times, waiting interval between the start of
+// consecutive scrapes, and returns the response bodies in order. Progress is
+// logged to logOut, which may be nil.
+func (c *Client) ScrapeSeries(ctx context.Context, samples int, interval time.Duration, logOut io.Writer) ([][]byte, error) {
+	if logOut == nil {
+		logOut = io.Discard
+	}
+	if samples < 1 {
+		return nil, fmt.Errorf("number of samples must be at least 1, got %d", samples)
+	}
+	if samples > 1 && interval <= 0 {
+		return nil, fmt.Errorf("interval between samples must be positive, got %s", interval)
+	}
+
+	ticker := time.NewTicker(max(interval, time.Millisecond))
+	defer ticker.Stop()
+
+	scrapes := make([][]byte, 0, samples)
+	for i := 0; i < samples; i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-ticker.C:
+			}
+		}
+		fmt.Fprintf(logOut, "Scraping %s (%d/%d)...\n", c.url, i+1, samples)
+		body, err := c.Scrape(ctx)
+		if err != nil {
+			return nil, err
+		}
+		scrapes = append(scrapes, body)
+	}
+	return scrapes, nil
+}
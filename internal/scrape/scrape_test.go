@@ -0,0 +1,130 @@
+package scrape
+
+import (
+	"context"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestScrape(t *testing.T) {
+	metrics, err := os.ReadFile(filepath.Join("..", "..", "testdata", "fixtures", "sample_metrics.txt"))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		w.Write(metrics)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, caPEM, 0600); err != nil {
+		t.Fatalf("failed to write CA file: %v", err)
+	}
+	tokenFile := filepath.Join(dir, "token")
+	if err := os.WriteFile(tokenFile, []byte("secret\n"), 0600); err != nil {
+		t.Fatalf("failed to write token file: %v", err)
+	}
+
+	tests := map[string]struct {
+		opts      Options
+		wantError bool
+	}{
+		"should scrape with bearer token and CA": {
+			opts: Options{URL: server.URL, BearerToken: "secret", CAFile: caFile},
+		},
+		"should read bearer token from file": {
+			opts: Options{URL: server.URL, BearerTokenFile: tokenFile, CAFile: caFile},
+		},
+		"should fail without the CA": {
+			opts:      Options{URL: server.URL, BearerToken: "secret"},
+			wantError: true,
+		},
+		"should fail on non-200 responses": {
+			opts:      Options{URL: server.URL, BearerToken: "wrong", InsecureSkipVerify: true},
+			wantError: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			client, err := NewClient(tt.opts)
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+			body, err := client.Scrape(context.Background())
+			if tt.wantError {
+				if err == nil {
+					t.Error("Scrape() expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Scrape() error = %v", err)
+			}
+			if string(body) != string(metrics) {
+				t.Errorf("Scrape() returned %d bytes, want the %d byte fixture", len(body), len(metrics))
+			}
+		})
+	}
+}
+
+func TestNewClientErrors(t *testing.T) {
+	tests := map[string]Options{
+		"should reject URLs without scheme":         {URL: "sensor:9090/metrics"},
+		"should reject a certificate without a key": {URL: "https://sensor:9090/metrics", CertFile: "cert.pem"},
+		"should reject a missing CA file":           {URL: "https://sensor:9090/metrics", CAFile: "missing.pem"},
+		"should reject a missing bearer token file": {URL: "https://sensor:9090/metrics", BearerTokenFile: "missing"},
+	}
+
+	for name, opts := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewClient(opts); err == nil {
+				t.Error("NewClient() expected error but got none")
+			}
+		})
+	}
+}
+
+func TestScrapeSeries(t *testing.T) {
+	var scrapes atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := scrapes.Add(1)
+		fmt.Fprintf(w, "# TYPE ops_total counter\nops_total %d\n", n)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(Options{URL: server.URL, Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	bodies, err := client.ScrapeSeries(context.Background(), 3, 10*time.Millisecond, io.Discard)
+	if err != nil {
+		t.Fatalf("ScrapeSeries() error = %v", err)
+	}
+	if len(bodies) != 3 {
+		t.Fatalf("ScrapeSeries() got %d scrapes, want 3", len(bodies))
+	}
+	if want := "# TYPE ops_total counter\nops_total 3\n"; string(bodies[2]) != want {
+		t.Errorf("ScrapeSeries() last scrape = %q, want %q", bodies[2], want)
+	}
+
+	if _, err := client.ScrapeSeries(context.Background(), 2, 0, io.Discard); err == nil {
+		t.Error("ScrapeSeries() expected error for multiple samples without an interval")
+	}
+}
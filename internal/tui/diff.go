@@ -0,0 +1,290 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/stackrox/sensor-metrics-analyzer/internal/reporter"
+	"github.com/stackrox/sensor-metrics-analyzer/internal/rules"
+)
+
+// diffFilters are the change kinds selectable with the number keys; nil shows all
+var diffFilters = []struct {
+	label   string
+	changes []rules.ChangeKind
+}{
+	{"1:All", nil},
+	{"2:🔺 Regressed", []rules.ChangeKind{rules.ChangeRegressed}},
+	{"3:✅ Improved", []rules.ChangeKind{rules.ChangeImproved}},
+	{"4:🆕 Added/Removed", []rules.ChangeKind{rules.ChangeAdded, rules.ChangeRemoved}},
+	{"5:➖ Unchanged", []rules.ChangeKind{rules.ChangeUnchanged}},
+}
+
+// DiffModel is the TUI state for browsing the changes between two analyses
+type DiffModel struct {
+	diff     rules.ReportDiff
+	filter   int
+	filtered []rules.ResultDiff
+	cursor   int
+	detail   bool
+	width    int
+	ready    bool
+}
+
+// NewDiffModel creates a TUI model for the given diff
+func NewDiffModel(diff rules.ReportDiff) DiffModel {
+	m := DiffModel{diff: diff}
+	m.applyFilter()
+	return m
+}
+
+// RunDiff starts the interactive TUI for a diff between two analyses
+func RunDiff(diff rules.ReportDiff) error {
+	if !IsTerminal() {
+		return fmt.Errorf("TUI mode requires an interactive terminal (stdout is not a TTY)")
+	}
+	if _, err := tea.NewProgram(NewDiffModel(diff)).Run(); err != nil {
+		return fmt.Errorf("error running TUI: %w", err)
+	}
+	return nil
+}
+
+func (m *DiffModel) applyFilter() {
+	m.filtered = make([]rules.ResultDiff, 0)
+	changes := diffFilters[m.filter].changes
+	for _, d := range m.diff.Results {
+		if changes == nil {
+			m.filtered = append(m.filtered, d)
+			continue
+		}
+		for _, change := range changes {
+			if d.Change == change {
+				m.filtered = append(m.filtered, d)
+				break
+			}
+		}
+	}
+	if m.cursor >= len(m.filtered) {
+		m.cursor = max(0, len(m.filtered)-1)
+	}
+}
+
+// Init initializes the model
+func (m DiffModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages and updates the model
+func (m DiffModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.ready = true
+	case tea.KeyMsg:
+		switch key := msg.String(); key {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.filtered)-1 {
+				m.cursor++
+			}
+		case "home", "g":
+			m.cursor = 0
+		case "end", "G":
+			m.cursor = max(0, len(m.filtered)-1)
+		case "enter", "l", "right":
+			if len(m.filtered) > 0 {
+				m.detail = true
+			}
+		case "esc", "h", "left", "backspace":
+			m.detail = false
+		case "1", "2", "3", "4", "5":
+			if !m.detail {
+				m.filter = int(key[0] - '1')
+				m.applyFilter()
+			}
+		}
+	}
+	return m, nil
+}
+
+// View renders the UI
+func (m DiffModel) View() string {
+	if !m.ready {
+		return "\n  Initializing..."
+	}
+	if m.detail && len(m.filtered) > 0 {
+		return m.viewDetail()
+	}
+	return m.viewList()
+}
+
+func (m DiffModel) viewList() string {
+	var b strings.Builder
+
+	b.WriteString(logoStyle.Render(logo))
+	b.WriteString("\n")
+
+	info := fmt.Sprintf("%s %s\n%s %s",
+		detailLabelStyle.Render("Before:"),
+		detailValueStyle.Render(fmt.Sprintf("%s (%s)", m.diff.Before.ClusterName, m.diff.Before.Timestamp.Format("2006-01-02 15:04:05"))),
+		detailLabelStyle.Render("After: "),
+		detailValueStyle.Render(fmt.Sprintf("%s (%s)", m.diff.After.ClusterName, m.diff.After.Timestamp.Format("2006-01-02 15:04:05"))),
+	)
+	b.WriteString(headerBoxStyle.Render(info))
+	b.WriteString("\n")
+
+	summary := fmt.Sprintf("  🔺 %s   ✅ %s   🆕 %d   🗑️ %d   ➖ %d",
+		redCountStyle.Render(fmt.Sprintf("%d", m.diff.Summary.Regressed)),
+		greenCountStyle.Render(fmt.Sprintf("%d", m.diff.Summary.Improved)),
+		m.diff.Summary.Added,
+		m.diff.Summary.Removed,
+		m.diff.Summary.Unchanged,
+	)
+	b.WriteString(summaryStyle.Render(summary))
+	b.WriteString("\n")
+
+	var tabs []string
+	for i, f := range diffFilters {
+		if i == m.filter {
+			tabs = append(tabs, activeTabStyle.Render(f.label))
+		} else {
+			tabs = append(tabs, inactiveTabStyle.Render(f.label))
+		}
+	}
+	b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, tabs...))
+	b.WriteString("\n")
+
+	b.WriteString(listStyle.Render(m.renderList()))
+	b.WriteString("\n")
+
+	b.WriteString(helpStyle.Render(fmt.Sprintf("%s navigate  %s details  %s filter  %s quit",
+		helpKeyStyle.Render("↑↓"),
+		helpKeyStyle.Render("Enter"),
+		helpKeyStyle.Render("1-5"),
+		helpKeyStyle.Render("q"),
+	)))
+
+	return appStyle.Render(b.String())
+}
+
+func (m DiffModel) renderList() string {
+	if len(m.filtered) == 0 {
+		return detailLabelStyle.Render("  No changes match the current filter")
+	}
+
+	var lines []string
+	visibleHeight := 15
+	start := 0
+	if m.cursor >= visibleHeight {
+		start = m.cursor - visibleHeight + 1
+	}
+	end := min(start+visibleHeight, len(m.filtered))
+
+	if start > 0 {
+		lines = append(lines, detailLabelStyle.Render(fmt.Sprintf("  ↑ %d more above", start)))
+	}
+
+	nameMax := 35
+	if m.width > 90 {
+		nameMax = m.width*45/100 - 3
+	}
+	for i := start; i < end; i++ {
+		d := m.filtered[i]
+		name := d.RuleName
+		if len(name) > nameMax {
+			name = name[:nameMax-3] + "..."
+		}
+		line := fmt.Sprintf("%s %-*s %-18s %s", changeEmoji(d.Change), nameMax, name,
+			reporter.FormatStatusTransition(d), reporter.FormatValueChange(d))
+		if i == m.cursor {
+			lines = append(lines, selectedItemStyle.Render(line))
+		} else {
+			lines = append(lines, normalItemStyle.Render(line))
+		}
+	}
+
+	if end < len(m.filtered) {
+		lines = append(lines, detailLabelStyle.Render(fmt.Sprintf("  ↓ %d more below", len(m.filtered)-end)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m DiffModel) viewDetail() string {
+	var b strings.Builder
+	d := m.filtered[m.cursor]
+
+	b.WriteString(logoStyle.Render(logo))
+	b.WriteString("\n")
+	b.WriteString(detailLabelStyle.Render(fmt.Sprintf("  Change %d of %d", m.cursor+1, len(m.filtered))))
+	b.WriteString("\n\n")
+
+	var detail strings.Builder
+	detail.WriteString(detailTitleStyle.Render(fmt.Sprintf("%s  %s", changeEmoji(d.Change), d.RuleName)))
+	detail.WriteString("\n\n")
+	detail.WriteString(detailLabelStyle.Render("Status:     "))
+	detail.WriteString(detailValueStyle.Render(reporter.FormatStatusTransition(d)))
+	detail.WriteString("\n")
+	if values := reporter.FormatValueChange(d); values != "" {
+		detail.WriteString(detailLabelStyle.Render("Value:      "))
+		detail.WriteString(detailValueStyle.Render(values))
+		detail.WriteString("\n")
+	}
+	detail.WriteString("\n")
+
+	messageWidth := 70
+	if m.width > 0 {
+		messageWidth = max(m.width-10, 40)
+	}
+	for _, side := range []struct {
+		label  string
+		result *rules.EvaluationResult
+	}{
+		{"Before:", d.Before},
+		{"After:", d.After},
+	} {
+		if side.result == nil {
+			continue
+		}
+		detail.WriteString(detailLabelStyle.Render(side.label))
+		detail.WriteString(" " + StatusBadge(string(side.result.Status)) + "\n")
+		for _, line := range strings.Split(wordWrap(side.result.Message, messageWidth), "\n") {
+			detail.WriteString(fmt.Sprintf("  %s\n", line))
+		}
+		for _, detailLine := range side.result.Details {
+			detail.WriteString(fmt.Sprintf("    %s\n", detailLine))
+		}
+		detail.WriteString("\n")
+	}
+
+	b.WriteString(detailBoxStyle.Render(detail.String()))
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render(fmt.Sprintf("%s back  %s/%s prev/next  %s quit",
+		helpKeyStyle.Render("←/esc"),
+		helpKeyStyle.Render("↑"),
+		helpKeyStyle.Render("↓"),
+		helpKeyStyle.Render("q"),
+	)))
+
+	return appStyle.Render(b.String())
+}
+
+func changeEmoji(change rules.ChangeKind) string {
+	switch change {
+	case rules.ChangeRegressed:
+		return "🔺"
+	case rules.ChangeImproved:
+		return "✅"
+	case rules.ChangeAdded:
+		return "🆕"
+	case rules.ChangeRemoved:
+		return "🗑️"
+	}
+	return "➖"
+}
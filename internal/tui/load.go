@@ -0,0 +1,101 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stackrox/sensor-metrics-analyzer/internal/reporter"
+	"github.com/stackrox/sensor-metrics-analyzer/internal/rules"
+)
+
+func (m Model) handleLoadKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q":
+		return m, tea.Quit
+
+	case "esc", "L", "h", "left", "backspace":
+		m.viewMode = ViewList
+
+	case "?":
+		m.viewMode = ViewHelp
+	}
+
+	return m, nil
+}
+
+// viewLoad explains how the load level, and with it the thresholds of
+// load-aware rules, was determined
+func (m Model) viewLoad() string {
+	var b strings.Builder
+
+	b.WriteString(logoStyle.Render(logo))
+	b.WriteString("\n")
+
+	var detail strings.Builder
+	detail.WriteString(detailTitleStyle.Render(fmt.Sprintf("⚖️  Load level: %s", m.report.LoadLevel)))
+	detail.WriteString("\n\n")
+
+	detection := m.report.LoadDetection
+	if detection.Strategy == "" {
+		detail.WriteString(detailLabelStyle.Render("No load detection breakdown is available for this report."))
+		detail.WriteString("\n")
+	} else {
+		detail.WriteString(detailLabelStyle.Render("Determined: "))
+		detail.WriteString(detailValueStyle.Render(reporter.FormatLoadLevel(m.report)))
+		detail.WriteString("\n")
+		detail.WriteString(detailLabelStyle.Render("Strategy:   "))
+		detail.WriteString(detailValueStyle.Render(string(detection.Strategy)))
+		detail.WriteString("\n")
+		if len(detection.Levels) > 0 {
+			detail.WriteString(detailLabelStyle.Render("Levels:     "))
+			detail.WriteString(detailValueStyle.Render(rules.FormatLoadLevels(detection.Levels)))
+			detail.WriteString("\n")
+		}
+		if detection.Overridden {
+			detail.WriteString(detailLabelStyle.Render("Overridden: "))
+			detail.WriteString(changedStyle.Render(fmt.Sprintf("--load-level %s replaced the detected %s", m.report.LoadLevel, detection.Detected)))
+			detail.WriteString("\n")
+		}
+
+		for _, v := range detection.Rules {
+			detail.WriteString("\n")
+			detail.WriteString(detailTitleStyle.Render(v.RuleName))
+			detail.WriteString("\n")
+			detail.WriteString(fmt.Sprintf("  %s\n", reporter.FormatLoadVerdict(v)))
+
+			nameWidth, sourceWidth := len("Metric"), len("Source")
+			for _, input := range v.Metrics {
+				nameWidth = max(nameWidth, len(input.Name))
+				sourceWidth = max(sourceWidth, len(input.Source))
+			}
+			detail.WriteString(detailLabelStyle.Render(fmt.Sprintf("  %-*s  %-*s  %12s  %7s  %12s",
+				nameWidth, "Metric", sourceWidth, "Source", "Value", "Weight", "Contribution")))
+			detail.WriteString("\n")
+			for _, input := range v.Metrics {
+				value, contribution := "missing", "-"
+				if input.Found {
+					value = formatFloat(input.Value)
+					contribution = formatFloat(input.Contribution)
+				}
+				detail.WriteString(fmt.Sprintf("  %-*s  %-*s  %12s  %7s  %12s\n",
+					nameWidth, input.Name, sourceWidth, input.Source, value, formatFloat(input.Weight), contribution))
+			}
+		}
+	}
+
+	b.WriteString(detailBoxStyle.Render(detail.String()))
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render(fmt.Sprintf("%s back  %s help  %s quit",
+		helpKeyStyle.Render("←/esc/L"),
+		helpKeyStyle.Render("?"),
+		helpKeyStyle.Render("q"),
+	)))
+
+	return appStyle.Render(b.String())
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', 6, 64)
+}
@@ -12,6 +12,7 @@ const (
 	ViewList ViewMode = iota
 	ViewDetail
 	ViewHelp
+	ViewLoad // How the load level was determined
 )
 
 // FilterMode represents what to filter by
@@ -22,6 +23,7 @@ const (
 	FilterRed
 	FilterYellow
 	FilterGreen
+	FilterUnknown
 )
 
 // Model represents the application state
@@ -29,6 +31,7 @@ type Model struct {
 	// Data
 	report  rules.AnalysisReport
 	results []rules.EvaluationResult
+	keys    []string // Identifies each result across refreshes, parallel to results
 
 	// UI state
 	cursor      int
@@ -43,6 +46,10 @@ type Model struct {
 
 	// Filtered results
 	filteredResults []rules.EvaluationResult
+	filteredKeys    []string
+
+	// Watch mode; nil for a static report
+	watch *watchState
 }
 
 // NewModel creates a new TUI model with the given report
@@ -55,6 +62,7 @@ func NewModel(report rules.AnalysisReport) Model {
 	m := Model{
 		report:      report,
 		results:     report.Results,
+		keys:        resultKeys(report.Results),
 		filterInput: ti,
 		filterMode:  FilterAll,
 		viewMode:    ViewList,
@@ -67,8 +75,9 @@ func NewModel(report rules.AnalysisReport) Model {
 // applyFilter filters results based on current filter settings
 func (m *Model) applyFilter() {
 	m.filteredResults = make([]rules.EvaluationResult, 0)
+	m.filteredKeys = make([]string, 0)
 
-	for _, r := range m.results {
+	for i, r := range m.results {
 		// Status filter
 		if m.filterMode != FilterAll {
 			switch m.filterMode {
@@ -84,6 +93,10 @@ func (m *Model) applyFilter() {
 				if r.Status != rules.StatusGreen {
 					continue
 				}
+			case FilterUnknown:
+				if r.Status != rules.StatusUnknown {
+					continue
+				}
 			}
 		}
 
@@ -96,6 +109,7 @@ func (m *Model) applyFilter() {
 		}
 
 		m.filteredResults = append(m.filteredResults, r)
+		m.filteredKeys = append(m.filteredKeys, m.keys[i])
 	}
 
 	// Reset cursor if out of bounds
@@ -20,9 +20,10 @@ var (
 	colorSelection  = lipgloss.Color("#44475A")
 
 	// Status colors
-	statusRed    = lipgloss.Color("#FF5555")
-	statusYellow = lipgloss.Color("#F1FA8C")
-	statusGreen  = lipgloss.Color("#50FA7B")
+	statusRed     = lipgloss.Color("#FF5555")
+	statusYellow  = lipgloss.Color("#F1FA8C")
+	statusGreen   = lipgloss.Color("#50FA7B")
+	statusUnknown = colorComment
 )
 
 // Styles
@@ -67,6 +68,10 @@ var (
 			Bold(true).
 			Foreground(statusGreen)
 
+	unknownCountStyle = lipgloss.NewStyle().
+				Bold(true).
+				Foreground(statusUnknown)
+
 	// List styles
 	listStyle = lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
@@ -103,6 +108,12 @@ var (
 			Background(statusGreen).
 			Padding(0, 1)
 
+	unknownBadgeStyle = lipgloss.NewStyle().
+				Bold(true).
+				Foreground(colorForeground).
+				Background(statusUnknown).
+				Padding(0, 1)
+
 	// Detail panel
 	detailBoxStyle = lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
@@ -136,6 +147,11 @@ var (
 	helpDescStyle = lipgloss.NewStyle().
 			Foreground(colorComment)
 
+	// Marks results whose status changed in the last refresh
+	changedStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(colorOrange)
+
 	// Filter input
 	filterPromptStyle = lipgloss.NewStyle().
 				Foreground(colorCyan).
@@ -174,6 +190,8 @@ func StatusBadge(status string) string {
 		return yellowBadgeStyle.Render("YEL")
 	case "GREEN":
 		return greenBadgeStyle.Render("GRN")
+	case "UNKNOWN":
+		return unknownBadgeStyle.Render("UNK")
 	default:
 		return status
 	}
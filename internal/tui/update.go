@@ -20,6 +20,18 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 		m.ready = true
 		return m, nil
+
+	case ReportMsg:
+		if m.watch != nil {
+			m.applyReport(msg.Report)
+		}
+		return m, nil
+
+	case RefreshErrorMsg:
+		if m.watch != nil {
+			m.watch.err = msg.Err
+		}
+		return m, nil
 	}
 
 	// Handle text input updates when filtering
@@ -69,6 +81,8 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleDetailKeys(msg)
 	case ViewHelp:
 		return m.handleHelpKeys(msg)
+	case ViewLoad:
+		return m.handleLoadKeys(msg)
 	}
 
 	return m, nil
@@ -114,6 +128,9 @@ func (m Model) handleListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "?":
 		m.viewMode = ViewHelp
 
+	case "L":
+		m.viewMode = ViewLoad
+
 	case "1":
 		m.filterMode = FilterAll
 		m.applyFilter()
@@ -130,6 +147,10 @@ func (m Model) handleListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.filterMode = FilterGreen
 		m.applyFilter()
 
+	case "5":
+		m.filterMode = FilterUnknown
+		m.applyFilter()
+
 	case "esc":
 		// Clear filter
 		m.filterText = ""
@@ -161,6 +182,9 @@ func (m Model) handleDetailKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	case "?":
 		m.viewMode = ViewHelp
+
+	case "L":
+		m.viewMode = ViewLoad
 	}
 
 	return m, nil
@@ -3,6 +3,7 @@ package tui
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 )
@@ -26,6 +27,8 @@ func (m Model) View() string {
 		return m.viewHelp()
 	case ViewDetail:
 		return m.viewDetail()
+	case ViewLoad:
+		return m.viewLoad()
 	default:
 		return m.viewList()
 	}
@@ -48,18 +51,48 @@ func (m Model) viewList() string {
 		detailLabelStyle.Render("Load:"),
 		detailValueStyle.Render(string(m.report.LoadLevel)),
 	)
+	if m.report.RateInterval > 0 {
+		infoContent += fmt.Sprintf("  │  %s %s",
+			detailLabelStyle.Render("Rates over:"),
+			detailValueStyle.Render(m.report.RateInterval.String()),
+		)
+	}
+	if m.report.Uptime > 0 {
+		infoContent += fmt.Sprintf("  │  %s %s",
+			detailLabelStyle.Render("Uptime:"),
+			detailValueStyle.Render(m.report.Uptime.Round(time.Second).String()),
+		)
+	}
+	for _, warning := range m.report.Warnings {
+		infoContent += "\n" + yellowCountStyle.Render("⚠ "+warning)
+	}
+	if m.watch != nil {
+		infoContent += fmt.Sprintf("\n%s %s  │  %s %s  │  %s %s",
+			detailLabelStyle.Render("Watching:"),
+			detailValueStyle.Render(m.watch.source),
+			detailLabelStyle.Render("Every:"),
+			detailValueStyle.Render(m.watch.interval.String()),
+			detailLabelStyle.Render("Updated:"),
+			detailValueStyle.Render(m.watch.lastRefresh.Format("15:04:05")),
+		)
+		if m.watch.err != nil {
+			infoContent += "\n" + redCountStyle.Render(fmt.Sprintf("Refresh failed: %v", m.watch.err))
+		}
+	}
 	b.WriteString(headerBoxStyle.Render(infoContent))
 	b.WriteString("\n")
 
 	// Summary
 	summary := fmt.Sprintf(
-		"  %s %s   %s %s   %s %s   │   Total: %d",
+		"  %s %s   %s %s   %s %s   %s %s   │   Total: %d",
 		"🔴",
 		redCountStyle.Render(fmt.Sprintf("%d", m.report.Summary.RedCount)),
 		"🟡",
 		yellowCountStyle.Render(fmt.Sprintf("%d", m.report.Summary.YellowCount)),
 		"🟢",
 		greenCountStyle.Render(fmt.Sprintf("%d", m.report.Summary.GreenCount)),
+		"⚪",
+		unknownCountStyle.Render(fmt.Sprintf("%d", m.report.Summary.UnknownCount)),
 		m.report.Summary.TotalAnalyzed,
 	)
 	b.WriteString(summaryStyle.Render(summary))
@@ -118,6 +151,12 @@ func (m Model) renderFilterTabs() string {
 		tabs = append(tabs, inactiveTabStyle.Render("4:🟢 Green"))
 	}
 
+	if m.filterMode == FilterUnknown {
+		tabs = append(tabs, activeTabStyle.Render("5:⚪ Unknown"))
+	} else {
+		tabs = append(tabs, inactiveTabStyle.Render("5:⚪ Unknown"))
+	}
+
 	return lipgloss.JoinHorizontal(lipgloss.Top, tabs...)
 }
 
@@ -151,6 +190,18 @@ func (m Model) renderResultsList() string {
 			msgMax = m.width - nameMax - 5
 		}
 
+		// In watch mode, mark status changes and show the value history
+		marker, spark := "", ""
+		if m.watch != nil {
+			key := m.filteredKeys[i]
+			marker = "  "
+			if _, changed := m.watch.changed[key]; changed {
+				marker = changedStyle.Render("Δ ")
+			}
+			spark = sparkline(m.watch.history[key], sparklineWidth) + " "
+			msgMax -= sparklineWidth + 3
+		}
+
 		name := r.RuleName
 		if len(name) > nameMax {
 			name = name[:nameMax-3] + "..."
@@ -158,10 +209,10 @@ func (m Model) renderResultsList() string {
 
 		msg := r.Message
 		if len(msg) > msgMax {
-			msg = msg[:msgMax-3] + "..."
+			msg = msg[:max(msgMax-3, 0)] + "..."
 		}
 
-		line := fmt.Sprintf("%s %-*s %s", StatusEmoji(string(r.Status)), nameMax, name, msg)
+		line := fmt.Sprintf("%s%s %-*s %s%s", marker, StatusEmoji(string(r.Status)), nameMax, name, spark, msg)
 
 		if i == m.cursor {
 			lines = append(lines, selectedItemStyle.Render(line))
@@ -212,6 +263,8 @@ func (m Model) viewDetail() string {
 		detail.WriteString(yellowCountStyle.Render("● YELLOW - Warning"))
 	case "GREEN":
 		detail.WriteString(greenCountStyle.Render("● GREEN - Healthy"))
+	case "UNKNOWN":
+		detail.WriteString(unknownCountStyle.Render(fmt.Sprintf("● UNKNOWN - Not evaluated (%s)", result.Reason)))
 	}
 	detail.WriteString("\n\n")
 
@@ -261,6 +314,23 @@ func (m Model) viewDetail() string {
 		detail.WriteString("\n\n")
 	}
 
+	// History of values and the last status change in watch mode
+	if m.watch != nil {
+		key := m.filteredKeys[m.cursor]
+		if before, changed := m.watch.changed[key]; changed {
+			detail.WriteString(detailLabelStyle.Render("Changed:    "))
+			detail.WriteString(changedStyle.Render(fmt.Sprintf("%s → %s in the last refresh", before, result.Status)))
+			detail.WriteString("\n")
+		}
+		if history := m.watch.history[key]; len(history) > 1 {
+			detail.WriteString(detailLabelStyle.Render("History:    "))
+			detail.WriteString(detailValueStyle.Render(sparkline(history, historyLength)))
+			detail.WriteString(detailLabelStyle.Render(fmt.Sprintf("  (%d refreshes)", len(history))))
+			detail.WriteString("\n")
+		}
+		detail.WriteString("\n")
+	}
+
 	// Details map - display all details in plain text format for easy copying
 	if len(result.Details) > 0 {
 		detail.WriteString(detailLabelStyle.Render("Details:"))
@@ -326,7 +396,8 @@ func (m Model) viewHelp() string {
 		{"G/End", "Go to bottom"},
 		{"PgUp/PgDn", "Page up/down"},
 		{"/", "Search/filter"},
-		{"1-4", "Filter by status (All/Red/Yellow/Green)"},
+		{"1-5", "Filter by status (All/Red/Yellow/Green/Unknown)"},
+		{"L", "Explain load level detection"},
 		{"Esc", "Clear filter"},
 		{"?", "Toggle help"},
 		{"q", "Quit"},
@@ -356,11 +427,12 @@ func (m Model) renderHelp() string {
 	}
 
 	return helpStyle.Render(
-		fmt.Sprintf("%s navigate  %s details  %s search  %s filter  %s help  %s quit",
+		fmt.Sprintf("%s navigate  %s details  %s search  %s filter  %s load  %s help  %s quit",
 			helpKeyStyle.Render("↑↓"),
 			helpKeyStyle.Render("Enter"),
 			helpKeyStyle.Render("/"),
-			helpKeyStyle.Render("1-4"),
+			helpKeyStyle.Render("1-5"),
+			helpKeyStyle.Render("L"),
 			helpKeyStyle.Render("?"),
 			helpKeyStyle.Render("q"),
 		),
@@ -0,0 +1,195 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stackrox/sensor-metrics-analyzer/internal/rules"
+)
+
+const (
+	// historyLength is the number of values kept per rule for the sparkline
+	historyLength = 30
+	// sparklineWidth is the number of values shown in the results list
+	sparklineWidth = 12
+)
+
+// sparkBlocks are the sparkline levels from lowest to highest
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// ReportMsg delivers a refreshed analysis report to a watching model
+type ReportMsg struct {
+	Report rules.AnalysisReport
+}
+
+// RefreshErrorMsg reports a failed refresh; the previous report stays on screen
+type RefreshErrorMsg struct {
+	Err error
+}
+
+// watchState is the state of a model that receives refreshed reports
+type watchState struct {
+	source      string
+	interval    time.Duration
+	lastRefresh time.Time
+	err         error
+	history     map[string][]float64    // Values by result key, oldest first
+	changed     map[string]rules.Status // Previous status of results that changed in the last refresh
+}
+
+// NewWatchModel creates a TUI model that expects refreshed reports as ReportMsg
+func NewWatchModel(report rules.AnalysisReport, source string, interval time.Duration) Model {
+	m := NewModel(report)
+	m.watch = &watchState{
+		source:      source,
+		interval:    interval,
+		lastRefresh: time.Now(),
+		history:     make(map[string][]float64),
+		changed:     make(map[string]rules.Status),
+	}
+	m.recordHistory()
+	return m
+}
+
+// RunWatch starts the interactive TUI and refreshes the report every interval
+// until the user quits. The first report is produced before the TUI starts so
+// that configuration errors are reported directly.
+func RunWatch(source string, interval time.Duration, refresh func() (rules.AnalysisReport, error)) error {
+	if !IsTerminal() {
+		return fmt.Errorf("TUI mode requires an interactive terminal (stdout is not a TTY)")
+	}
+
+	report, err := refresh()
+	if err != nil {
+		return err
+	}
+
+	p := tea.NewProgram(NewWatchModel(report, source, interval))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			report, err := refresh()
+			if err != nil {
+				p.Send(RefreshErrorMsg{Err: err})
+				continue
+			}
+			p.Send(ReportMsg{Report: report})
+		}
+	}()
+
+	if _, err := p.Run(); err != nil {
+		return fmt.Errorf("error running TUI: %w", err)
+	}
+	return nil
+}
+
+// applyReport replaces the report, recording which results changed status and
+// keeping the cursor on the selected result when it still exists
+func (m *Model) applyReport(report rules.AnalysisReport) {
+	selected := ""
+	if m.cursor < len(m.filteredKeys) {
+		selected = m.filteredKeys[m.cursor]
+	}
+
+	previous := make(map[string]rules.Status, len(m.results))
+	for i, r := range m.results {
+		previous[m.keys[i]] = r.Status
+	}
+
+	m.report = report
+	m.results = report.Results
+	m.keys = resultKeys(report.Results)
+
+	m.watch.err = nil
+	m.watch.lastRefresh = time.Now()
+	m.watch.changed = make(map[string]rules.Status)
+	for i, r := range m.results {
+		if before, ok := previous[m.keys[i]]; ok && before != r.Status {
+			m.watch.changed[m.keys[i]] = before
+		}
+	}
+	m.recordHistory()
+
+	m.applyFilter()
+	for i, key := range m.filteredKeys {
+		if key == selected {
+			m.cursor = i
+			break
+		}
+	}
+}
+
+// recordHistory appends the current values to each result's history
+func (m *Model) recordHistory() {
+	current := make(map[string]bool, len(m.results))
+	for i, r := range m.results {
+		key := m.keys[i]
+		current[key] = true
+		history := append(m.watch.history[key], r.Value)
+		if len(history) > historyLength {
+			history = history[len(history)-historyLength:]
+		}
+		m.watch.history[key] = history
+	}
+	// Forget results that disappeared so their history does not resume stale
+	for key := range m.watch.history {
+		if !current[key] {
+			delete(m.watch.history, key)
+		}
+	}
+}
+
+// resultKeys identifies results across refreshes by rule name, numbering
+// repeated names in order of appearance
+func resultKeys(results []rules.EvaluationResult) []string {
+	seen := make(map[string]int, len(results))
+	keys := make([]string, len(results))
+	for i, r := range results {
+		seen[r.RuleName]++
+		keys[i] = r.RuleName
+		if n := seen[r.RuleName]; n > 1 {
+			keys[i] = fmt.Sprintf("%s#%d", r.RuleName, n)
+		}
+	}
+	return keys
+}
+
+// sparkline renders the last width values scaled between their minimum and maximum
+func sparkline(values []float64, width int) string {
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+	if len(values) == 0 {
+		return strings.Repeat(" ", width)
+	}
+
+	lowest, highest := values[0], values[0]
+	for _, v := range values[1:] {
+		lowest = math.Min(lowest, v)
+		highest = math.Max(highest, v)
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		level := 0
+		if highest > lowest {
+			level = int((v - lowest) / (highest - lowest) * float64(len(sparkBlocks)-1))
+		}
+		b.WriteRune(sparkBlocks[level])
+	}
+	b.WriteString(strings.Repeat(" ", width-len(values)))
+	return b.String()
+}
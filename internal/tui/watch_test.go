@@ -0,0 +1,82 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stackrox/sensor-metrics-analyzer/internal/rules"
+)
+
+func TestApplyReport(t *testing.T) {
+	report := func(statuses ...rules.Status) rules.AnalysisReport {
+		names := []string{"queue_size", "goroutines", "goroutines"}
+		var r rules.AnalysisReport
+		for i, status := range statuses {
+			r.Results = append(r.Results, rules.EvaluationResult{RuleName: names[i], Status: status, Value: float64(i + 1)})
+		}
+		return r
+	}
+
+	m := NewWatchModel(report(rules.StatusGreen, rules.StatusGreen, rules.StatusYellow), "metrics.txt", time.Second)
+	m.cursor = 2
+	m.applyReport(report(rules.StatusRed, rules.StatusGreen, rules.StatusYellow))
+
+	if len(m.watch.changed) != 1 || m.watch.changed["queue_size"] != rules.StatusGreen {
+		t.Errorf("changed = %v, want only queue_size changed from GREEN", m.watch.changed)
+	}
+	if m.filteredKeys[m.cursor] != "goroutines#2" {
+		t.Errorf("cursor on %s, want goroutines#2", m.filteredKeys[m.cursor])
+	}
+	if got := len(m.watch.history["queue_size"]); got != 2 {
+		t.Errorf("history length = %d, want 2", got)
+	}
+
+	for i := 0; i < historyLength; i++ {
+		m.applyReport(report(rules.StatusRed))
+	}
+	if len(m.watch.changed) != 0 {
+		t.Errorf("changed = %v, want none", m.watch.changed)
+	}
+	if got := len(m.watch.history["queue_size"]); got != historyLength {
+		t.Errorf("history length = %d, want %d", got, historyLength)
+	}
+	if _, ok := m.watch.history["goroutines"]; ok {
+		t.Error("history of removed results should be dropped")
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	tests := map[string]struct {
+		values []float64
+		width  int
+		want   string
+	}{
+		"should pad empty history": {
+			width: 3,
+			want:  "   ",
+		},
+		"should scale between min and max": {
+			values: []float64{0, 7, 14},
+			width:  4,
+			want:   "▁▄█ ",
+		},
+		"should draw flat history at the lowest level": {
+			values: []float64{5, 5},
+			width:  2,
+			want:   "▁▁",
+		},
+		"should keep only the most recent values": {
+			values: []float64{100, 0, 1},
+			width:  2,
+			want:   "▁█",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := sparkline(tt.values, tt.width); got != tt.want {
+				t.Errorf("sparkline() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/stackrox/sensor-metrics-analyzer/internal/analyzer"
@@ -32,9 +33,10 @@ type Config struct {
 }
 
 type AnalyzeResponse struct {
-	Markdown string `json:"markdown"`
-	Console  string `json:"console"`
-	Error    string `json:"error,omitempty"`
+	Markdown string               `json:"markdown"`
+	Console  string               `json:"console"`
+	Report   *reporter.JSONReport `json:"report,omitempty"`
+	Error    string               `json:"error,omitempty"`
 }
 
 type VersionResponse struct {
@@ -174,6 +176,7 @@ func handleAnalyzeBoth(cfg *Config) http.HandlerFunc {
 			RulesDir:     cfg.RulesDir,
 			LoadLevelDir: cfg.LoadLevelDir,
 			ClusterName:  analyzer.ExtractClusterName(header.Filename),
+			ScrapeTime:   uploadScrapeTime(r),
 			Logger:       log.New(os.Stdout, "analyzer: ", log.LstdFlags).Writer(),
 		})
 		if err := ctx.Err(); err != nil {
@@ -183,6 +186,8 @@ func handleAnalyzeBoth(cfg *Config) http.HandlerFunc {
 		if err != nil {
 			response.Error = fmt.Sprintf("Analysis failed: %v", err)
 		} else {
+			jsonReport := reporter.NewJSONReport(report)
+			response.Report = &jsonReport
 			response.Console = reporter.GenerateConsole(report)
 			markdown, mdErr := reporter.GenerateMarkdown(report, cfg.TemplatePath)
 			if mdErr != nil {
@@ -206,3 +211,14 @@ func respondError(w http.ResponseWriter, status int, message string) {
 	w.WriteHeader(status)
 	json.NewEncoder(w).Encode(AnalyzeResponse{Error: message})
 }
+
+// uploadScrapeTime returns the modification time of the uploaded file, sent by
+// the browser as the last_modified form field in milliseconds since the epoch.
+// Without it the Sensor uptime is unknown.
+func uploadScrapeTime(r *http.Request) time.Time {
+	millis, err := strconv.ParseInt(r.FormValue("last_modified"), 10, 64)
+	if err != nil || millis <= 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(millis)
+}